@@ -4,24 +4,45 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+
+	"Engee-Server/cors"
+	registry "Engee-Server/gameRegistry"
+	"Engee-Server/ratelimit"
 )
 
 const configPath = "./config.json"
 
 type Config struct {
-	Port string `json:"server_port"`
+	Port                  string            `json:"server_port"`
+	PublicURL             string            `json:"public_url"`
+	AdminToken            string            `json:"admin_token"`
+	GameModeSecrets       map[string]string `json:"game_mode_secrets"`
+	RegistryWebhooks      []string          `json:"registry_webhooks"`
+	RegistryWebhookSecret string            `json:"registry_webhook_secret"`
+	ShutdownTimeout       int               `json:"shutdown_timeout_seconds"`
+	StateFile             string            `json:"state_file"`
+	RequireGameMode       bool              `json:"require_game_mode"`
+	LogFormat             string            `json:"log_format"`
+	LogLevel              string            `json:"log_level"`
+	TraceExporter         string            `json:"trace_exporter"`
+	TraceFile             string            `json:"trace_file"`
+	CORS                  *cors.Policy      `json:"cors"`
+	TrustedProxies        []string          `json:"trusted_proxies"`
+	RateLimits            ratelimit.Config  `json:"rate_limits"`
+
+	GameModes []registry.CatalogueEntry `json:"game_modes"`
 }
 
 func ReadConfig() Config {
 	content, err := ioutil.ReadFile(configPath)
 	if err != nil {
-		panic(fmt.Sprintf("Could not read config file on launch: %w", err))
+		panic(fmt.Sprintf("Could not read config file on launch: %v", err))
 	}
 
 	var payload Config
 	err = json.Unmarshal(content, &payload)
 	if err != nil {
-		panic(fmt.Sprintf("Could not parse config file on launch: %w", err))
+		panic(fmt.Sprintf("Could not parse config file on launch: %v", err))
 	}
 
 	return payload
@@ -0,0 +1,209 @@
+package cors
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	sErr "Engee-Server/stockErrors"
+)
+
+const wildcard = "*"
+
+var DefaultMethods = []string{
+	http.MethodGet,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodOptions,
+}
+
+type Policy struct {
+	AllowedOrigins   []string `json:"allowed_origins"`
+	AllowedMethods   []string `json:"allowed_methods"`
+	AllowedHeaders   []string `json:"allowed_headers"`
+	ExposedHeaders   []string `json:"exposed_headers"`
+	AllowCredentials bool     `json:"allow_credentials"`
+	MaxAge           int      `json:"max_age_seconds"`
+}
+
+// DefaultPolicy lets any origin call the API without credentials, matching
+// what the server allowed before CORS was configurable.
+func DefaultPolicy() Policy {
+	return Policy{
+		AllowedOrigins: []string{wildcard},
+		AllowedMethods: DefaultMethods,
+		AllowedHeaders: []string{wildcard},
+	}
+}
+
+// Validate rejects policies browsers would refuse or that would hand
+// credentials to every origin.
+func (p Policy) Validate() error {
+	if len(p.AllowedOrigins) == 0 {
+		return &sErr.EmptyValueError{
+			Field: "Allowed Origins",
+		}
+	}
+
+	for _, origin := range p.AllowedOrigins {
+		if strings.Count(origin, wildcard) > 1 {
+			return &sErr.InvalidValueError[string]{
+				Field: "Allowed Origin",
+				Value: origin,
+			}
+		}
+
+		if p.AllowCredentials && origin == wildcard {
+			return &sErr.InvalidValueError[string]{
+				Field: "Allowed Origin with credentials",
+				Value: origin,
+			}
+		}
+	}
+
+	if p.MaxAge < 0 {
+		return &sErr.InvalidValueError[int]{
+			Field: "Max Age",
+			Value: p.MaxAge,
+		}
+	}
+
+	return nil
+}
+
+// MiddleWare applies policy to every request. Preflight requests are
+// answered here and never reach the route handlers.
+func MiddleWare(policy Policy) gin.HandlerFunc {
+	if len(policy.AllowedMethods) == 0 {
+		policy.AllowedMethods = DefaultMethods
+	}
+
+	methods := strings.Join(policy.AllowedMethods, ", ")
+	exposed := strings.Join(policy.ExposedHeaders, ", ")
+
+	return func(c *gin.Context) {
+		origin := c.Request.Header.Get("Origin")
+		preflight := c.Request.Method == http.MethodOptions && c.Request.Header.Get("Access-Control-Request-Method") != ""
+
+		if origin == "" {
+			c.Next()
+			return
+		}
+
+		c.Writer.Header().Add("Vary", "Origin")
+
+		if !policy.allowsOrigin(origin) {
+			if preflight {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+
+			c.Next()
+			return
+		}
+
+		if policy.AllowCredentials || !policy.allowsAnyOrigin() {
+			c.Header("Access-Control-Allow-Origin", origin)
+		} else {
+			c.Header("Access-Control-Allow-Origin", wildcard)
+		}
+
+		if policy.AllowCredentials {
+			c.Header("Access-Control-Allow-Credentials", "true")
+		}
+
+		if !preflight {
+			if exposed != "" {
+				c.Header("Access-Control-Expose-Headers", exposed)
+			}
+
+			c.Next()
+			return
+		}
+
+		requestedHeaders := c.Request.Header.Get("Access-Control-Request-Headers")
+		if !policy.allowsMethod(c.Request.Header.Get("Access-Control-Request-Method")) || !policy.allowsHeaders(requestedHeaders) {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+
+		c.Header("Access-Control-Allow-Methods", methods)
+		if requestedHeaders != "" {
+			c.Header("Access-Control-Allow-Headers", requestedHeaders)
+		}
+
+		if policy.MaxAge > 0 {
+			c.Header("Access-Control-Max-Age", strconv.Itoa(policy.MaxAge))
+		}
+
+		c.AbortWithStatus(http.StatusNoContent)
+	}
+}
+
+func (p Policy) allowsAnyOrigin() bool {
+	for _, allowed := range p.AllowedOrigins {
+		if allowed == wildcard {
+			return true
+		}
+	}
+
+	return false
+}
+
+// allowsOrigin matches origin against each allowed origin, where a single
+// "*" stands for any run of characters, as in "https://*.example.com".
+func (p Policy) allowsOrigin(origin string) bool {
+	origin = strings.ToLower(origin)
+
+	for _, allowed := range p.AllowedOrigins {
+		prefix, suffix, found := strings.Cut(strings.ToLower(allowed), wildcard)
+		if !found {
+			if origin == prefix {
+				return true
+			}
+
+			continue
+		}
+
+		if len(origin) >= len(prefix)+len(suffix) && strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p Policy) allowsMethod(method string) bool {
+	for _, allowed := range p.AllowedMethods {
+		if strings.EqualFold(allowed, method) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p Policy) allowsHeaders(requested string) bool {
+	for _, header := range strings.Split(requested, ",") {
+		header = strings.TrimSpace(header)
+		if header != "" && !p.allowsHeader(header) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (p Policy) allowsHeader(header string) bool {
+	for _, allowed := range p.AllowedHeaders {
+		if allowed == wildcard || strings.EqualFold(allowed, header) {
+			return true
+		}
+	}
+
+	return false
+}
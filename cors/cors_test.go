@@ -0,0 +1,130 @@
+package cors
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	sErr "Engee-Server/stockErrors"
+)
+
+var handled bool
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	code := m.Run()
+	os.Exit(code)
+}
+
+func TestPreflightShortCircuits(t *testing.T) {
+	recorder := serveCORSRequest(t, DefaultPolicy(), preflightRequest("https://game.example.com", http.MethodPost, "Content-Type"))
+
+	if recorder.Code != http.StatusNoContent || handled {
+		t.Fatalf(`MiddleWare(Preflight) = %d, handled %v, want 204 without reaching handler`, recorder.Code, handled)
+	}
+
+	if recorder.Header().Get("Access-Control-Allow-Origin") != "*" || recorder.Header().Get("Access-Control-Allow-Headers") != "Content-Type" {
+		t.Fatalf(`MiddleWare(Preflight) = %v, want wildcard origin and requested headers allowed`, recorder.Header())
+	}
+}
+
+func TestWildcardOrigin(t *testing.T) {
+	policy := Policy{AllowedOrigins: []string{"https://*.example.com"}, MaxAge: 600}
+
+	recorder := serveCORSRequest(t, policy, preflightRequest("https://play.example.com", http.MethodGet, ""))
+	if recorder.Code != http.StatusNoContent || recorder.Header().Get("Access-Control-Allow-Origin") != "https://play.example.com" {
+		t.Fatalf(`MiddleWare(WildcardMatch) = %d, %v, want 204 echoing origin`, recorder.Code, recorder.Header())
+	}
+
+	if recorder.Header().Get("Access-Control-Max-Age") != "600" {
+		t.Fatalf(`MiddleWare(MaxAge) = %q, want "600"`, recorder.Header().Get("Access-Control-Max-Age"))
+	}
+
+	recorder = serveCORSRequest(t, policy, preflightRequest("https://example.org", http.MethodGet, ""))
+	if recorder.Code != http.StatusForbidden || recorder.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Fatalf(`MiddleWare(WildcardMismatch) = %d, %v, want 403 without CORS headers`, recorder.Code, recorder.Header())
+	}
+}
+
+func TestDisallowedMethod(t *testing.T) {
+	policy := Policy{AllowedOrigins: []string{"https://example.com"}, AllowedMethods: []string{http.MethodGet}}
+
+	recorder := serveCORSRequest(t, policy, preflightRequest("https://example.com", http.MethodDelete, ""))
+	if recorder.Code != http.StatusForbidden {
+		t.Fatalf(`MiddleWare(DisallowedMethod) = %d, want 403`, recorder.Code)
+	}
+}
+
+func TestDisallowedHeader(t *testing.T) {
+	policy := Policy{AllowedOrigins: []string{"https://example.com"}, AllowedHeaders: []string{"Content-Type"}}
+
+	recorder := serveCORSRequest(t, policy, preflightRequest("https://example.com", http.MethodGet, "content-type, X-Secret"))
+	if recorder.Code != http.StatusForbidden {
+		t.Fatalf(`MiddleWare(DisallowedHeader) = %d, want 403`, recorder.Code)
+	}
+}
+
+func TestSimpleRequest(t *testing.T) {
+	policy := Policy{
+		AllowedOrigins:   []string{"https://example.com"},
+		ExposedHeaders:   []string{"X-Engee-Token"},
+		AllowCredentials: true,
+	}
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("Origin", "https://example.com")
+
+	recorder := serveCORSRequest(t, policy, request)
+	if !handled || recorder.Header().Get("Access-Control-Allow-Credentials") != "true" || recorder.Header().Get("Access-Control-Expose-Headers") != "X-Engee-Token" {
+		t.Fatalf(`MiddleWare(Simple) = %v, handled %v, want credentials and exposed headers`, recorder.Header(), handled)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	err := DefaultPolicy().Validate()
+	if err != nil {
+		t.Fatalf(`Validate(Default) = %v, want nil`, err)
+	}
+
+	err = Policy{}.Validate()
+	if !errors.As(err, &sErr.EV_ERR) {
+		t.Fatalf(`Validate(NoOrigins) = %v, want EmptyValueError`, err)
+	}
+
+	err = Policy{AllowedOrigins: []string{"*"}, AllowCredentials: true}.Validate()
+	if !errors.As(err, &sErr.IV_ERR) {
+		t.Fatalf(`Validate(WildcardCredentials) = %v, want InvalidValueError`, err)
+	}
+}
+
+func serveCORSRequest(t *testing.T, policy Policy, request *http.Request) *httptest.ResponseRecorder {
+	handled = false
+	t.Cleanup(func() { handled = false })
+
+	router := gin.New()
+	router.Use(MiddleWare(policy))
+	router.Any("/", func(c *gin.Context) {
+		handled = true
+		c.Status(http.StatusOK)
+	})
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	return recorder
+}
+
+func preflightRequest(origin string, method string, headers string) *http.Request {
+	request := httptest.NewRequest(http.MethodOptions, "/", nil)
+	request.Header.Set("Origin", origin)
+	request.Header.Set("Access-Control-Request-Method", method)
+	if headers != "" {
+		request.Header.Set("Access-Control-Request-Headers", headers)
+	}
+
+	return request
+}
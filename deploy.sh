@@ -7,7 +7,7 @@ time=$(date)
 
 mkdir -p "$loc/logs/$time"
 
-sed -i "s/\"server_port\":.*/\"server_port\": \"${SERVER_INNER}\"/g" config.json
+sed -i "s/\"server_port\":[^,]*/\"server_port\": \"${SERVER_INNER}\"/g" config.json
 
 buildLog="$loc/logs/$time/build.log"
 runLog="$loc/logs/$time/run.log"
@@ -17,5 +17,16 @@ go build >> "$buildLog"
 echo "Done."
 
 echo "Starting server..."
-go run main.go >> "$runLog"
+go run main.go >> "$runLog" 2>&1 &
+server=$!
+
+for attempt in $(seq 1 30); do
+    if curl -sf "http://localhost:${SERVER_INNER}/readyz" > /dev/null; then
+        echo "Server ready."
+        break
+    fi
+    sleep 1
+done
+
+wait $server
 echo "Server stopped."
\ No newline at end of file
@@ -0,0 +1,100 @@
+package gameclient
+
+import (
+	"sync"
+	"time"
+
+	sErr "Engee-Server/stockErrors"
+)
+
+const (
+	breakerClosed = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+type circuitBreaker struct {
+	lock      sync.Mutex
+	addr      string
+	state     int
+	failures  int
+	threshold int
+	cooldown  time.Duration
+	openedAt  time.Time
+}
+
+func newCircuitBreaker(addr string, threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		addr:      addr,
+		state:     breakerClosed,
+		threshold: threshold,
+		cooldown:  cooldown,
+	}
+}
+
+func (cb *circuitBreaker) allow() error {
+	cb.lock.Lock()
+	defer cb.lock.Unlock()
+
+	switch cb.state {
+	case breakerOpen:
+		if time.Since(cb.openedAt) < cb.cooldown {
+			return &sErr.UnavailableError{
+				Space:  cb.addr,
+				Reason: "circuit breaker is open",
+			}
+		}
+
+		cb.state = breakerHalfOpen
+		return nil
+	case breakerHalfOpen:
+		return &sErr.UnavailableError{
+			Space:  cb.addr,
+			Reason: "circuit breaker is testing recovery",
+		}
+	}
+
+	return nil
+}
+
+// Returns true when the breaker closes after having been open
+func (cb *circuitBreaker) recordSuccess() bool {
+	cb.lock.Lock()
+	defer cb.lock.Unlock()
+
+	recovered := cb.state != breakerClosed
+	cb.state = breakerClosed
+	cb.failures = 0
+
+	return recovered
+}
+
+// Returns true when the failure trips the breaker open
+func (cb *circuitBreaker) recordFailure() bool {
+	cb.lock.Lock()
+	defer cb.lock.Unlock()
+
+	cb.failures++
+
+	if cb.state == breakerHalfOpen || (cb.state == breakerClosed && cb.failures >= cb.threshold) {
+		tripped := cb.state == breakerClosed
+		cb.state = breakerOpen
+		cb.openedAt = time.Now()
+		return tripped
+	}
+
+	return false
+}
+
+// Puts a half-open breaker back to open when its probe was abandoned by the
+// caller, so the next call after the cooldown can probe again. An abandoned
+// call says nothing about the game server, so a closed breaker is left as is.
+func (cb *circuitBreaker) recordAbandoned() {
+	cb.lock.Lock()
+	defer cb.lock.Unlock()
+
+	if cb.state == breakerHalfOpen {
+		cb.state = breakerOpen
+		cb.openedAt = time.Now()
+	}
+}
@@ -0,0 +1,306 @@
+package gameclient
+
+import (
+	"Engee-Server/gameClient/payload"
+	sErr "Engee-Server/stockErrors"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+const GameStateCreated = "Created"
+const GameStateRunning = "Running"
+const GameStatePaused = "Paused"
+
+type FakeGame struct {
+	GameInstance
+	State          string
+	Scores         map[string]int
+	RemovedPlayers []string
+	StateQueries   int
+	Snapshot       []byte
+}
+
+type FakeBackend struct {
+	lock     sync.Mutex
+	games    map[string]FakeGame
+	failures map[string]error
+}
+
+func NewFakeBackend() *FakeBackend {
+	return &FakeBackend{
+		games:    make(map[string]FakeGame),
+		failures: make(map[string]error),
+	}
+}
+
+func (b *FakeBackend) SetFailure(operation string, err error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if err == nil {
+		delete(b.failures, operation)
+		return
+	}
+
+	b.failures[operation] = err
+}
+
+func (b *FakeBackend) GetFakeGame(rid string) (FakeGame, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	err := b.checkRID(rid)
+	if err != nil {
+		return FakeGame{}, err
+	}
+
+	return b.games[rid], nil
+}
+
+func (b *FakeBackend) CreateGameInstance(ctx context.Context, setup GameSetup) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	err := validateSetup(setup)
+	if err != nil {
+		return err
+	}
+
+	_, found := b.games[setup.RID]
+	if found {
+		return &sErr.MatchFoundError[string]{
+			Space: "Games",
+			Field: "RID",
+			Value: setup.RID,
+		}
+	}
+
+	err = b.failures["CreateGameInstance"]
+	if err != nil {
+		return err
+	}
+
+	err = ctx.Err()
+	if err != nil {
+		return err
+	}
+
+	b.games[setup.RID] = FakeGame{
+		GameInstance: newGameInstance(setup, setup.Addr+"/games/"+setup.RID),
+		State:        GameStateCreated,
+		Snapshot:     setup.Snapshot,
+	}
+
+	return nil
+}
+
+func (b *FakeBackend) MigrateGameInstance(ctx context.Context, setup GameSetup) (GameInstance, error) {
+	err := validateSetup(setup)
+	if err != nil {
+		return GameInstance{}, err
+	}
+
+	var previous GameInstance
+	err = b.update(ctx, "MigrateGameInstance", setup.RID, func(game *FakeGame) {
+		previous = game.GameInstance
+		game.GameInstance = newGameInstance(setup, setup.Addr+"/games/"+setup.RID)
+		game.Snapshot = setup.Snapshot
+	})
+
+	return previous, err
+}
+
+func (b *FakeBackend) RecreateGameInstance(ctx context.Context, rid string) error {
+	return b.update(ctx, "RecreateGameInstance", rid, func(game *FakeGame) {
+		game.State = GameStateCreated
+		game.RemovedPlayers = nil
+	})
+}
+
+func (b *FakeBackend) EndGame(ctx context.Context, rid string) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	err := b.checkOperation(ctx, "EndGame", rid)
+	if err != nil {
+		return err
+	}
+
+	delete(b.games, rid)
+	return nil
+}
+
+func (b *FakeBackend) EndGameInstance(ctx context.Context, game GameInstance) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	err := b.failures["EndGame"]
+	if err != nil {
+		return err
+	}
+
+	err = ctx.Err()
+	if err != nil {
+		return err
+	}
+
+	current, found := b.games[game.RID]
+	if found && current.URL == game.URL {
+		delete(b.games, game.RID)
+	}
+
+	return nil
+}
+
+func (b *FakeBackend) ExportGameSnapshot(ctx context.Context, rid string) ([]byte, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	err := b.checkOperation(ctx, "ExportGameSnapshot", rid)
+	if err != nil {
+		return nil, err
+	}
+
+	game := b.games[rid]
+	snapshot, err := json.Marshal(GameState{
+		Phase:  game.State,
+		Scores: game.Scores,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal game snapshot: %w", err)
+	}
+
+	return snapshot, nil
+}
+
+func (b *FakeBackend) SetGameRules(ctx context.Context, rid string, rules payload.Rules) error {
+	return b.update(ctx, "SetGameRules", rid, func(game *FakeGame) {
+		game.Rules = rules
+	})
+}
+
+func (b *FakeBackend) StartGame(ctx context.Context, rid string) error {
+	return b.update(ctx, "StartGame", rid, func(game *FakeGame) {
+		game.State = GameStateRunning
+	})
+}
+
+func (b *FakeBackend) PauseGame(ctx context.Context, rid string) error {
+	return b.update(ctx, "PauseGame", rid, func(game *FakeGame) {
+		game.State = GameStatePaused
+	})
+}
+
+func (b *FakeBackend) ResetGame(ctx context.Context, rid string) error {
+	return b.update(ctx, "ResetGame", rid, func(game *FakeGame) {
+		game.State = GameStateCreated
+	})
+}
+
+func (b *FakeBackend) RemovePlayer(ctx context.Context, rid string, targetUID string) error {
+	return b.update(ctx, "RemovePlayer", rid, func(game *FakeGame) {
+		game.RemovedPlayers = append(game.RemovedPlayers, targetUID)
+	})
+}
+
+func (b *FakeBackend) GetGameState(ctx context.Context, rid string) (GameState, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	err := b.checkOperation(ctx, "GetGameState", rid)
+	if err != nil {
+		return GameState{}, err
+	}
+
+	game := b.games[rid]
+	game.StateQueries++
+	b.games[rid] = game
+
+	scores := make(map[string]int, len(game.Scores))
+	for uid, score := range game.Scores {
+		scores[uid] = score
+	}
+
+	players := make([]string, 0, len(game.Players))
+	for _, player := range game.Players {
+		if !slices.Contains(game.RemovedPlayers, player.UID) {
+			players = append(players, player.UID)
+		}
+	}
+
+	return GameState{
+		Phase:   game.State,
+		Scores:  scores,
+		Players: players,
+	}, nil
+}
+
+func (b *FakeBackend) SetFakeScores(rid string, scores map[string]int) error {
+	return b.update(context.Background(), "SetFakeScores", rid, func(game *FakeGame) {
+		game.Scores = scores
+	})
+}
+
+func (b *FakeBackend) HasGameInstance(rid string) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	_, found := b.games[rid]
+	return found
+}
+
+func (b *FakeBackend) GetGameInstance(rid string) (GameInstance, error) {
+	game, err := b.GetFakeGame(rid)
+	return game.GameInstance, err
+}
+
+func (b *FakeBackend) GetGameInstances() []GameInstance {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	instances := make([]GameInstance, 0, len(b.games))
+	for _, game := range maps.Values(b.games) {
+		instances = append(instances, game.GameInstance)
+	}
+
+	return instances
+}
+
+func (b *FakeBackend) update(ctx context.Context, operation string, rid string, apply func(game *FakeGame)) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	err := b.checkOperation(ctx, operation, rid)
+	if err != nil {
+		return err
+	}
+
+	game := b.games[rid]
+	apply(&game)
+	b.games[rid] = game
+
+	return nil
+}
+
+func (b *FakeBackend) checkOperation(ctx context.Context, operation string, rid string) error {
+	err := b.checkRID(rid)
+	if err != nil {
+		return err
+	}
+
+	err = b.failures[operation]
+	if err != nil {
+		return err
+	}
+
+	return ctx.Err()
+}
+
+func (b *FakeBackend) checkRID(rid string) error {
+	return checkRID(b.games, rid)
+}
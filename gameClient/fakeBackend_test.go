@@ -0,0 +1,77 @@
+package gameclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"Engee-Server/gameClient/payload"
+	sErr "Engee-Server/stockErrors"
+)
+
+func TestFakeCreateGame(t *testing.T) {
+	fake := NewFakeBackend()
+
+	err := fake.CreateGameInstance(context.Background(), GameSetup{RID: testRID, GameMode: testGameMode, Addr: testURL})
+	if err != nil || !fake.HasGameInstance(testRID) {
+		t.Fatalf(`FakeBackend.CreateGameInstance(Valid) = %v, want nil`, err)
+	}
+}
+
+func TestFakeCreateGameDouble(t *testing.T) {
+	fake := NewFakeBackend()
+
+	fake.CreateGameInstance(context.Background(), GameSetup{RID: testRID, GameMode: testGameMode, Addr: testURL})
+	err := fake.CreateGameInstance(context.Background(), GameSetup{RID: testRID, GameMode: testGameMode, Addr: testURL})
+	if !errors.As(err, &sErr.MF_ERR) {
+		t.Fatalf(`FakeBackend.CreateGameInstance(Double) = %v, want MatchFoundError`, err)
+	}
+}
+
+func TestFakeGameLifecycle(t *testing.T) {
+	fake := NewFakeBackend()
+	fake.CreateGameInstance(context.Background(), GameSetup{RID: testRID, GameMode: testGameMode, Addr: testURL})
+
+	fake.SetGameRules(context.Background(), testRID, payload.Rules{Rules: updatedRules})
+	fake.StartGame(context.Background(), testRID)
+	fake.RemovePlayer(context.Background(), testRID, altRID)
+
+	game, err := fake.GetFakeGame(testRID)
+	if err != nil || game.Rules.Rules != updatedRules || game.State != GameStateRunning || len(game.RemovedPlayers) != 1 {
+		t.Fatalf(`FakeBackend(Lifecycle) = %v, %v, want running game with rules and removed player`, game, err)
+	}
+
+	fake.EndGame(context.Background(), testRID)
+	if fake.HasGameInstance(testRID) {
+		t.Fatalf(`FakeBackend.EndGame(Valid) left game instance behind`)
+	}
+}
+
+func TestFakeFailure(t *testing.T) {
+	fake := NewFakeBackend()
+	fake.CreateGameInstance(context.Background(), GameSetup{RID: testRID, GameMode: testGameMode, Addr: testURL})
+
+	failure := errors.New("unreachable")
+	fake.SetFailure("EndGame", failure)
+
+	err := fake.EndGame(context.Background(), testRID)
+	if !errors.Is(err, failure) || !fake.HasGameInstance(testRID) {
+		t.Fatalf(`FakeBackend.EndGame(Failure) = %v, want %v`, err, failure)
+	}
+
+	fake.SetFailure("EndGame", nil)
+
+	err = fake.EndGame(context.Background(), testRID)
+	if err != nil {
+		t.Fatalf(`FakeBackend.EndGame(Cleared Failure) = %v, want nil`, err)
+	}
+}
+
+func TestFakeInvalidRID(t *testing.T) {
+	fake := NewFakeBackend()
+
+	err := fake.StartGame(context.Background(), badRID)
+	if !errors.As(err, &sErr.MNF_ERR) {
+		t.Fatalf(`FakeBackend.StartGame(InvalidRID) = %v, want MatchNotFoundError`, err)
+	}
+}
@@ -1,131 +1,149 @@
 package gameclient
 
 import (
+	"Engee-Server/gameClient/payload"
+	"Engee-Server/metrics"
 	sErr "Engee-Server/stockErrors"
+	"Engee-Server/tracing"
 	"Engee-Server/utils"
-	"bytes"
+	"context"
 	"fmt"
-	"io/ioutil"
-	"net/http"
+	"time"
 )
 
-var gameURLs = make(map[string]string)
-
-func CreateGameInstance(rid string, url string) error {
-	if rid == "" {
-		return &sErr.EmptyValueError{
-			Field: "RID",
-		}
-	}
+const (
+	opCreateGame     = "CreateGame"
+	opEndGame        = "EndGame"
+	opSetRules       = "SetRules"
+	opStartGame      = "StartGame"
+	opPauseGame      = "PauseGame"
+	opResetGame      = "ResetGame"
+	opRemovePlayer   = "RemovePlayer"
+	opGetState       = "GetState"
+	opExportSnapshot = "ExportSnapshot"
+)
 
-	err := utils.ValidateURL(url)
-	if err != nil {
-		return fmt.Errorf("URL is invalid: %w", err)
-	}
+var gameRequestDuration = metrics.NewHistogram(
+	"engee_game_request_duration_seconds",
+	"Latency of requests sent to game servers, including retries.",
+	metrics.DefaultBuckets,
+	"gamemode", "operation",
+)
 
-	_, found := gameURLs[rid]
-	if found {
-		return &sErr.MatchFoundError[string]{
-			Space: "Games",
-			Field: "RID",
-			Value: rid,
-		}
-	}
+type GameBackend interface {
+	CreateGameInstance(ctx context.Context, setup GameSetup) error
+	RecreateGameInstance(ctx context.Context, rid string) error
+	EndGame(ctx context.Context, rid string) error
+	EndGameInstance(ctx context.Context, game GameInstance) error
+	MigrateGameInstance(ctx context.Context, setup GameSetup) (GameInstance, error)
+	ExportGameSnapshot(ctx context.Context, rid string) ([]byte, error)
+	SetGameRules(ctx context.Context, rid string, rules payload.Rules) error
+	StartGame(ctx context.Context, rid string) error
+	PauseGame(ctx context.Context, rid string) error
+	ResetGame(ctx context.Context, rid string) error
+	RemovePlayer(ctx context.Context, rid string, targetUID string) error
+	GetGameState(ctx context.Context, rid string) (GameState, error)
+	HasGameInstance(rid string) bool
+	GetGameInstance(rid string) (GameInstance, error)
+	GetGameInstances() []GameInstance
+}
 
-	_, err = sendRequest(url+"/games", http.MethodPost, []byte(rid))
-	if err != nil {
-		return err
-	}
+type GameSetup struct {
+	RID         string
+	RoomName    string
+	GameMode    string
+	Addr        string
+	Players     []payload.Player
+	Rules       payload.Rules
+	CallbackURL string
+	Snapshot    []byte
+}
 
-	gameURLs[rid] = url + "/games/" + rid
+type GameInstance struct {
+	RID         string           `json:"rid"`
+	Addr        string           `json:"addr"`
+	URL         string           `json:"url"`
+	GameMode    string           `json:"gamemode"`
+	RoomName    string           `json:"room_name"`
+	Players     []payload.Player `json:"players"`
+	Rules       payload.Rules    `json:"rules"`
+	CallbackURL string           `json:"callback_url"`
+}
 
-	return nil
+type GameState struct {
+	Phase   string         `json:"phase"`
+	Scores  map[string]int `json:"scores"`
+	Players []string       `json:"players"`
 }
 
-func EndGame(rid string) error {
-	err := checkRID(rid)
-	if err != nil {
-		return err
+func newGameInstance(setup GameSetup, url string) GameInstance {
+	if setup.Players == nil {
+		setup.Players = make([]payload.Player, 0)
 	}
 
-	url := gameURLs[rid]
-
-	_, err = sendRequest(url, http.MethodDelete, []byte(rid))
-	if err != nil {
-		return err
+	return GameInstance{
+		RID:         setup.RID,
+		Addr:        setup.Addr,
+		URL:         url,
+		GameMode:    setup.GameMode,
+		RoomName:    setup.RoomName,
+		Players:     setup.Players,
+		Rules:       setup.Rules,
+		CallbackURL: setup.CallbackURL,
 	}
-
-	delete(gameURLs, rid)
-	return nil
 }
 
-func SetGameRules(rid string, rules string) error {
-	err := checkRID(rid)
-	if err != nil {
-		return err
+func (game GameInstance) createRequest(snapshot []byte) payload.CreateGameRequest {
+	return payload.CreateGameRequest{
+		Version:     payload.Version,
+		RID:         game.RID,
+		RoomName:    game.RoomName,
+		GameMode:    game.GameMode,
+		Players:     game.Players,
+		Rules:       game.Rules.Rules,
+		Teams:       game.Rules.Teams,
+		CallbackURL: game.CallbackURL,
+		Snapshot:    snapshot,
 	}
-
-	url := gameURLs[rid] + "/rules"
-
-	_, err = sendRequest(url, http.MethodPut, []byte(rules))
-	return err
 }
 
-func StartGame(rid string) error {
-	err := checkRID(rid)
-	if err != nil {
-		return err
+func commandRequest(rid string, uid string) payload.CommandRequest {
+	return payload.CommandRequest{
+		Version: payload.Version,
+		RID:     rid,
+		UID:     uid,
 	}
-
-	url := gameURLs[rid] + "/start"
-
-	_, err = sendRequest(url, http.MethodPut, []byte{})
-	return err
 }
 
-func PauseGame(rid string) error {
-	err := checkRID(rid)
-	if err != nil {
-		return err
+func validateSetup(setup GameSetup) error {
+	if setup.RID == "" {
+		return &sErr.EmptyValueError{
+			Field: "RID",
+		}
 	}
 
-	url := gameURLs[rid] + "/pause"
-
-	_, err = sendRequest(url, http.MethodPut, []byte{})
-	return err
-}
-
-func ResetGame(rid string) error {
-	err := checkRID(rid)
+	err := utils.ValidateURL(setup.Addr)
 	if err != nil {
-		return err
+		return fmt.Errorf("URL is invalid: %w", err)
 	}
 
-	url := gameURLs[rid] + "/reset"
-
-	_, err = sendRequest(url, http.MethodPut, []byte{})
-	return err
+	return nil
 }
 
-func RemovePlayer(rid string, targetUID string) error {
-	err := checkRID(rid)
-	if err != nil {
-		return err
+func forgetGameInstance(games map[string]GameInstance, game GameInstance) {
+	current, found := games[game.RID]
+	if found && current.URL == game.URL {
+		delete(games, game.RID)
 	}
-
-	url := gameURLs[rid] + "/players/" + targetUID
-
-	_, err = sendRequest(url, http.MethodDelete, []byte{})
-	return err
 }
 
-func checkRID(rid string) error {
+func checkRID[T any](games map[string]T, rid string) error {
 	if rid == "" {
 		return &sErr.EmptyValueError{
 			Field: "RID",
 		}
 	}
-	_, found := gameURLs[rid]
+	_, found := games[rid]
 	if !found {
 		return &sErr.MatchNotFoundError[string]{
 			Space: "Game URLs",
@@ -137,30 +155,19 @@ func checkRID(rid string) error {
 	return nil
 }
 
-func sendRequest(url string, method string, body []byte) (string, error) {
-	reqBody := bytes.NewReader(body)
+// startGameRequest times and traces a call to a game server. The returned
+// function ends both, recording err on the span.
+func startGameRequest(ctx context.Context, game GameInstance, operation string) (context.Context, func(err error)) {
+	start := time.Now()
 
-	request, err := http.NewRequest(method, url, reqBody)
-	if err != nil {
-		return "", err
-	}
+	ctx, span := tracing.StartClient(ctx, "gameclient "+operation)
+	span.SetAttribute("rid", game.RID)
+	span.SetAttribute("gamemode", game.GameMode)
+	span.SetAttribute("server.address", game.Addr)
 
-	response, err := http.DefaultClient.Do(request)
-	if err != nil {
-		return "", fmt.Errorf("failed to carry out http request: %w", err)
+	return ctx, func(err error) {
+		span.RecordError(err)
+		span.End()
+		gameRequestDuration.ObserveSince(start, game.GameMode, operation)
 	}
-
-	resBody, err := ioutil.ReadAll(response.Body)
-	if err != nil {
-		return "", fmt.Errorf("could not read request response body: %w", err)
-	}
-
-	if response.StatusCode != http.StatusOK {
-		return "", &sErr.HttpRequestError{
-			Call: fmt.Sprintf("%s: %q", method, url),
-			Code: response.StatusCode,
-		}
-	}
-
-	return string(resBody), nil
 }
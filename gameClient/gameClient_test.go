@@ -1,18 +1,27 @@
 package gameclient
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 
+	"Engee-Server/gameClient/payload"
 	reg "Engee-Server/gameRegistry"
 	sErr "Engee-Server/stockErrors"
 	"Engee-Server/testDummy"
+	"Engee-Server/utils"
 )
 
+var backend = NewHTTPBackend()
+
 var testRID = uuid.NewString()
 var altRID = uuid.NewString()
 var badRID = uuid.NewString()
@@ -26,6 +35,14 @@ const testURL = "http://localhost:" + testPort
 const altURL = "http://localhost:" + altPort
 
 const updatedRules = "New Rules"
+const testRoomName = "Test Room"
+
+var testPlayers = []payload.Player{
+	{UID: uuid.NewString(), Name: "Test Player"},
+	{UID: uuid.NewString(), Name: "Alt Player"},
+}
+
+const testSecret = "test-secret"
 
 const badURL = "http://notahost:8080"
 
@@ -37,7 +54,7 @@ func TestMain(m *testing.M) {
 }
 
 func TestCreateGame(t *testing.T) {
-	err := CreateGameInstance(testRID, testURL)
+	err := backend.CreateGameInstance(context.Background(), GameSetup{RID: testRID, GameMode: testGameMode, Addr: testURL})
 	if err != nil {
 		t.Fatalf(`TestCreateGame(Valid) = %v, want nil`, err)
 	}
@@ -46,8 +63,8 @@ func TestCreateGame(t *testing.T) {
 }
 
 func TestCreateGameDoubleSameURL(t *testing.T) {
-	CreateGameInstance(testRID, testURL)
-	err := CreateGameInstance(testRID, testURL)
+	backend.CreateGameInstance(context.Background(), GameSetup{RID: testRID, GameMode: testGameMode, Addr: testURL})
+	err := backend.CreateGameInstance(context.Background(), GameSetup{RID: testRID, GameMode: testGameMode, Addr: testURL})
 	if !errors.As(err, &sErr.MF_ERR) {
 		t.Fatalf(`TestCreateGame(Double Same) = %v, want MatchFoundError`, err)
 	}
@@ -56,8 +73,8 @@ func TestCreateGameDoubleSameURL(t *testing.T) {
 }
 
 func TestCreateGameDoubleUniqueURL(t *testing.T) {
-	CreateGameInstance(testRID, testURL)
-	err := CreateGameInstance(testRID, altURL)
+	backend.CreateGameInstance(context.Background(), GameSetup{RID: testRID, GameMode: testGameMode, Addr: testURL})
+	err := backend.CreateGameInstance(context.Background(), GameSetup{RID: testRID, GameMode: testGameMode, Addr: altURL})
 	if !errors.As(err, &sErr.MF_ERR) {
 		t.Fatalf(`TestCreateGame(Double Unique) = %v, want MatchFoundError`, err)
 	}
@@ -66,8 +83,8 @@ func TestCreateGameDoubleUniqueURL(t *testing.T) {
 }
 
 func TestCreateGameMultiSameURL(t *testing.T) {
-	CreateGameInstance(testRID, testURL)
-	err := CreateGameInstance(altRID, testURL)
+	backend.CreateGameInstance(context.Background(), GameSetup{RID: testRID, GameMode: testGameMode, Addr: testURL})
+	err := backend.CreateGameInstance(context.Background(), GameSetup{RID: altRID, GameMode: testGameMode, Addr: testURL})
 	if err != nil {
 		t.Fatalf(`TestCreateGame(Same URL) = %v, want nil`, err)
 	}
@@ -76,8 +93,8 @@ func TestCreateGameMultiSameURL(t *testing.T) {
 }
 
 func TestCreateGameMultiUniqueURL(t *testing.T) {
-	CreateGameInstance(testRID, testURL)
-	err := CreateGameInstance(altRID, altURL)
+	backend.CreateGameInstance(context.Background(), GameSetup{RID: testRID, GameMode: testGameMode, Addr: testURL})
+	err := backend.CreateGameInstance(context.Background(), GameSetup{RID: altRID, GameMode: altGameMode, Addr: altURL})
 	if err != nil {
 		t.Fatalf(`TestCreateGame(Unique URL) = %v, want nil`, err)
 	}
@@ -86,7 +103,7 @@ func TestCreateGameMultiUniqueURL(t *testing.T) {
 }
 
 func TestCreateGameEmptyRID(t *testing.T) {
-	err := CreateGameInstance("", testURL)
+	err := backend.CreateGameInstance(context.Background(), GameSetup{RID: "", GameMode: testGameMode, Addr: testURL})
 	if !errors.As(err, &sErr.EV_ERR) {
 		t.Fatalf(`TestCreateGame(Empty RID) %v, want EmptyValueError`, err)
 	}
@@ -95,7 +112,7 @@ func TestCreateGameEmptyRID(t *testing.T) {
 }
 
 func TestCreateGameEmptyURL(t *testing.T) {
-	err := CreateGameInstance(testRID, "")
+	err := backend.CreateGameInstance(context.Background(), GameSetup{RID: testRID, GameMode: testGameMode, Addr: ""})
 	if !errors.As(err, &sErr.EV_ERR) {
 		t.Fatalf(`TestCreateGame(Empty URL) %v, want EmptyValueError`, err)
 	}
@@ -104,7 +121,7 @@ func TestCreateGameEmptyURL(t *testing.T) {
 }
 
 func TestCreateGameInvalidURL(t *testing.T) {
-	err := CreateGameInstance(testRID, badURL)
+	err := backend.CreateGameInstance(context.Background(), GameSetup{RID: testRID, GameMode: testGameMode, Addr: badURL})
 	if err == nil {
 		t.Fatalf(`TestCreateGame(Valid) %v, want error`, err)
 	}
@@ -114,37 +131,37 @@ func TestCreateGameInvalidURL(t *testing.T) {
 
 func TestEndGame(t *testing.T) {
 	setupGameTest(t)
-	err := EndGame(testRID)
+	err := backend.EndGame(context.Background(), testRID)
 	if err != nil {
 		t.Fatalf(`TestEndGame(Valid) = %v, want nil`, err)
 	}
 }
 func TestEndGameDouble(t *testing.T) {
 	setupGameTest(t)
-	EndGame(testRID)
-	err := EndGame(testRID)
+	backend.EndGame(context.Background(), testRID)
+	err := backend.EndGame(context.Background(), testRID)
 	if !errors.As(err, &sErr.MNF_ERR) {
 		t.Fatalf(`TestEndGame(Double) = %v, want MatchNotFoundError`, err)
 	}
 }
 func TestEndGameMulti(t *testing.T) {
 	setupGameTest(t)
-	EndGame(testRID)
-	err := EndGame(altRID)
+	backend.EndGame(context.Background(), testRID)
+	err := backend.EndGame(context.Background(), altRID)
 	if err != nil {
 		t.Fatalf(`TestEndGame(Multi) = %v, want nil`, err)
 	}
 }
 func TestEndGameInvalidRID(t *testing.T) {
 	setupGameTest(t)
-	err := EndGame(badRID)
+	err := backend.EndGame(context.Background(), badRID)
 	if !errors.As(err, &sErr.MNF_ERR) {
 		t.Fatalf(`TestEndGame(InvalidRID) = %v, want MatchNotFoundError`, err)
 	}
 }
 func TestEndGameEmptyRID(t *testing.T) {
 	setupGameTest(t)
-	err := EndGame("")
+	err := backend.EndGame(context.Background(), "")
 	if !errors.As(err, &sErr.EV_ERR) {
 		t.Fatalf(`TestEndGame(EmptyRID) = %v, want EmptyValueError`, err)
 	}
@@ -153,7 +170,7 @@ func TestEndGameEmptyRID(t *testing.T) {
 func TestSetGameRules(t *testing.T) {
 	setupGameTest(t)
 
-	err := SetGameRules(testRID, updatedRules)
+	err := backend.SetGameRules(context.Background(), testRID, payload.Rules{Rules: updatedRules})
 	if err != nil {
 		t.Fatalf(`TestSetGameRules(Valid) = %v, want nil`, err)
 	}
@@ -162,8 +179,8 @@ func TestSetGameRules(t *testing.T) {
 func TestSetGameRulesDouble(t *testing.T) {
 	setupGameTest(t)
 
-	SetGameRules(testRID, updatedRules)
-	err := SetGameRules(testRID, updatedRules)
+	backend.SetGameRules(context.Background(), testRID, payload.Rules{Rules: updatedRules})
+	err := backend.SetGameRules(context.Background(), testRID, payload.Rules{Rules: updatedRules})
 	if err != nil {
 		t.Fatalf(`TestSetGameRules(Double) = %v, want nil`, err)
 	}
@@ -172,7 +189,7 @@ func TestSetGameRulesDouble(t *testing.T) {
 func TestSetGameRulesInvalidRID(t *testing.T) {
 	setupGameTest(t)
 
-	err := SetGameRules(badRID, updatedRules)
+	err := backend.SetGameRules(context.Background(), badRID, payload.Rules{Rules: updatedRules})
 	if !errors.As(err, &sErr.MNF_ERR) {
 		t.Fatalf(`TestSetGameRules(InvalidRID) = %v, want MatchNotFoundError`, err)
 	}
@@ -181,7 +198,7 @@ func TestSetGameRulesInvalidRID(t *testing.T) {
 func TestStartGame(t *testing.T) {
 	setupGameTest(t)
 
-	err := StartGame(testRID)
+	err := backend.StartGame(context.Background(), testRID)
 	if err != nil {
 		t.Fatalf(`TestStartGame(Valid) = %v, want nil`, err)
 	}
@@ -190,7 +207,7 @@ func TestStartGame(t *testing.T) {
 func TestStartGameInvalidRID(t *testing.T) {
 	setupGameTest(t)
 
-	err := StartGame(badRID)
+	err := backend.StartGame(context.Background(), badRID)
 	if !errors.As(err, &sErr.MNF_ERR) {
 		t.Fatalf(`TestStartGame(Invalid RID) = %v, want MatchNotFoundError`, err)
 	}
@@ -199,7 +216,7 @@ func TestStartGameInvalidRID(t *testing.T) {
 func TestPauseGame(t *testing.T) {
 	setupActiveGameTest(t)
 
-	err := PauseGame(testRID)
+	err := backend.PauseGame(context.Background(), testRID)
 	if err != nil {
 		t.Fatalf(`TestPauseGame(Valid) = %v, want nil`, err)
 	}
@@ -208,7 +225,7 @@ func TestPauseGame(t *testing.T) {
 func TestPauseGameInvalidRID(t *testing.T) {
 	setupActiveGameTest(t)
 
-	err := PauseGame(badRID)
+	err := backend.PauseGame(context.Background(), badRID)
 	if !errors.As(err, &sErr.MNF_ERR) {
 		t.Fatalf(`TestPauseGame(InvalidRID) = %v, want MatchNotFoundError`, err)
 	}
@@ -216,7 +233,7 @@ func TestPauseGameInvalidRID(t *testing.T) {
 func TestResetGame(t *testing.T) {
 	setupActiveGameTest(t)
 
-	err := ResetGame(testRID)
+	err := backend.ResetGame(context.Background(), testRID)
 	if err != nil {
 		t.Fatalf(`TestResetGame(Valid) = %v, want nil`, err)
 	}
@@ -225,12 +242,178 @@ func TestResetGame(t *testing.T) {
 func TestResetGameInvalidRID(t *testing.T) {
 	setupActiveGameTest(t)
 
-	err := ResetGame(badRID)
+	err := backend.ResetGame(context.Background(), badRID)
 	if !errors.As(err, &sErr.MNF_ERR) {
 		t.Fatalf(`TestResetGame(InvalidRID) = %v, want MatchNotFoundError`, err)
 	}
 }
 
+func TestGetGameState(t *testing.T) {
+	setupGameTest(t)
+
+	state, err := backend.GetGameState(context.Background(), testRID)
+	if state.Phase != GameStateCreated || err != nil {
+		t.Fatalf(`TestGetGameState(Valid) = %v, %v, want Created state, nil`, state, err)
+	}
+}
+
+func TestGetGameStateInvalidRID(t *testing.T) {
+	setupGameTest(t)
+
+	_, err := backend.GetGameState(context.Background(), badRID)
+	if !errors.As(err, &sErr.MNF_ERR) {
+		t.Fatalf(`TestGetGameState(InvalidRID) = %v, want MatchNotFoundError`, err)
+	}
+}
+
+func TestCreateGameSigned(t *testing.T) {
+	var verifyErr error
+	signedServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		verifyErr = utils.VerifyRequest(r, testSecret, body)
+	}))
+	defer signedServer.Close()
+
+	reg.SetGameModeSecrets(map[string]string{testGameMode: testSecret})
+	t.Cleanup(func() {
+		reg.SetGameModeSecrets(nil)
+	})
+
+	err := backend.CreateGameInstance(context.Background(), GameSetup{RID: testRID, GameMode: testGameMode, Addr: signedServer.URL})
+	if err != nil || verifyErr != nil {
+		t.Fatalf(`TestCreateGame(Signed) = %v, %v, want nil, nil`, err, verifyErr)
+	}
+
+	t.Cleanup(func() {
+		backend = NewHTTPBackend()
+	})
+}
+
+func TestRecreateGame(t *testing.T) {
+	requests := make([]string, 0)
+	var recreated payload.CreateGameRequest
+	gameServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.Method == http.MethodPost {
+			json.NewDecoder(r.Body).Decode(&recreated)
+		}
+	}))
+	defer gameServer.Close()
+
+	backend.CreateGameInstance(context.Background(), GameSetup{RID: testRID, RoomName: testRoomName, GameMode: testGameMode, Addr: gameServer.URL, Players: testPlayers})
+	backend.SetGameRules(context.Background(), testRID, payload.Rules{Rules: updatedRules})
+	t.Cleanup(cleanUpAfterTest)
+
+	err := backend.RecreateGameInstance(context.Background(), testRID)
+	if err != nil {
+		t.Fatalf(`TestRecreateGame(Valid) = %v, want nil`, err)
+	}
+
+	expected := []string{
+		"POST /games",
+		"PUT /games/" + testRID + "/rules",
+		"POST /games",
+	}
+
+	if len(requests) != len(expected) {
+		t.Fatalf(`TestRecreateGame(Valid) sent %v, want %v`, requests, expected)
+	}
+
+	for i, request := range requests {
+		if request != expected[i] {
+			t.Fatalf(`TestRecreateGame(Valid) sent %q, want %q`, request, expected[i])
+		}
+	}
+
+	if recreated.RID != testRID || recreated.RoomName != testRoomName || recreated.Rules != updatedRules || len(recreated.Players) != len(testPlayers) {
+		t.Fatalf(`TestRecreateGame(Valid) sent %v, want setup with room name, players and rules`, recreated)
+	}
+}
+
+func TestCreateGamePayload(t *testing.T) {
+	setupGameTest(t)
+
+	err := backend.CreateGameInstance(context.Background(), GameSetup{
+		RID:         badRID,
+		RoomName:    testRoomName,
+		GameMode:    testGameMode,
+		Addr:        testURL,
+		Players:     testPlayers,
+		Rules:       payload.Rules{Rules: updatedRules, Teams: map[string][]string{"red": {testPlayers[0].UID}}},
+		CallbackURL: testURL + "/games/" + badRID + "/events",
+	})
+	if err != nil {
+		t.Fatalf(`TestCreateGame(Payload) = %v, want nil`, err)
+	}
+
+	backend.EndGame(context.Background(), badRID)
+}
+
+func TestCreateGameRejectedReply(t *testing.T) {
+	gameServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(payload.Reply{Version: payload.Version, RID: testRID, Status: payload.StatusError, Message: "full"})
+	}))
+	defer gameServer.Close()
+	t.Cleanup(cleanUpAfterTest)
+
+	err := backend.CreateGameInstance(context.Background(), GameSetup{RID: testRID, GameMode: testGameMode, Addr: gameServer.URL})
+	if err == nil || backend.HasGameInstance(testRID) {
+		t.Fatalf(`TestCreateGame(RejectedReply) = %v, want error`, err)
+	}
+}
+
+func TestMigrateGame(t *testing.T) {
+	oldRequests := make([]string, 0)
+	oldServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		oldRequests = append(oldRequests, r.Method+" "+r.URL.Path)
+		if r.URL.Path == "/games/"+testRID+"/snapshot" {
+			w.Write([]byte(`{"phase":"Running"}`))
+		}
+	}))
+	defer oldServer.Close()
+
+	var migrated payload.CreateGameRequest
+	newServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&migrated)
+	}))
+	defer newServer.Close()
+
+	backend.CreateGameInstance(context.Background(), GameSetup{RID: testRID, GameMode: testGameMode, Addr: oldServer.URL})
+	t.Cleanup(cleanUpAfterTest)
+
+	snapshot, err := backend.ExportGameSnapshot(context.Background(), testRID)
+	if string(snapshot) != `{"phase":"Running"}` || err != nil {
+		t.Fatalf(`ExportGameSnapshot(Valid) = %s, %v, want snapshot, nil`, snapshot, err)
+	}
+
+	previous, err := backend.MigrateGameInstance(context.Background(), GameSetup{RID: testRID, GameMode: testGameMode, Addr: newServer.URL, Snapshot: snapshot})
+	if err != nil || previous.Addr != oldServer.URL || string(migrated.Snapshot) != string(snapshot) {
+		t.Fatalf(`MigrateGameInstance(Valid) = %v, %v, want previous instance at %s with snapshot sent`, previous, err, oldServer.URL)
+	}
+
+	err = backend.EndGameInstance(context.Background(), previous)
+	game, _ := backend.GetGameInstance(testRID)
+	if err != nil || game.Addr != newServer.URL || oldRequests[len(oldRequests)-1] != "DELETE /games/"+testRID {
+		t.Fatalf(`EndGameInstance(Previous) = %v, want old instance ended and game at %s`, err, newServer.URL)
+	}
+}
+
+func TestMigrateGameSameURL(t *testing.T) {
+	setupGameTest(t)
+
+	_, err := backend.MigrateGameInstance(context.Background(), GameSetup{RID: testRID, GameMode: testGameMode, Addr: testURL})
+	if !errors.As(err, &sErr.MF_ERR) {
+		t.Fatalf(`MigrateGameInstance(SameURL) = %v, want MatchFoundError`, err)
+	}
+}
+
+func TestRecreateGameInvalidRID(t *testing.T) {
+	err := backend.RecreateGameInstance(context.Background(), badRID)
+	if !errors.As(err, &sErr.MNF_ERR) {
+		t.Fatalf(`TestRecreateGame(InvalidRID) = %v, want MatchNotFoundError`, err)
+	}
+}
+
 func setupGameSuite() {
 	go testDummy.Serve(testPort)
 	go testDummy.Serve(altPort)
@@ -244,8 +427,8 @@ func setupGameSuite() {
 
 func setupGameTest(t *testing.T) {
 
-	CreateGameInstance(testRID, testURL)
-	CreateGameInstance(altRID, altURL)
+	backend.CreateGameInstance(context.Background(), GameSetup{RID: testRID, GameMode: testGameMode, Addr: testURL})
+	backend.CreateGameInstance(context.Background(), GameSetup{RID: altRID, GameMode: altGameMode, Addr: altURL})
 
 	t.Cleanup(cleanUpAfterTest)
 }
@@ -253,15 +436,15 @@ func setupGameTest(t *testing.T) {
 func setupActiveGameTest(t *testing.T) {
 	setupGameTest(t)
 
-	StartGame(testRID)
-	StartGame(altRID)
+	backend.StartGame(context.Background(), testRID)
+	backend.StartGame(context.Background(), altRID)
 }
 
 func cleanUpAfterTest() {
-	EndGame(testRID)
-	EndGame(altRID)
+	backend.EndGame(context.Background(), testRID)
+	backend.EndGame(context.Background(), altRID)
 
-	gameURLs = make(map[string]string)
+	backend = NewHTTPBackend()
 }
 
 func cleanUpAfterSuite() {
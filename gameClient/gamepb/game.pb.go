@@ -0,0 +1,656 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: game.proto
+
+package gamepb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GameRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rid           string                 `protobuf:"bytes,1,opt,name=rid,proto3" json:"rid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GameRequest) Reset() {
+	*x = GameRequest{}
+	mi := &file_game_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GameRequest) ProtoMessage() {}
+
+func (x *GameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GameRequest.ProtoReflect.Descriptor instead.
+func (*GameRequest) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GameRequest) GetRid() string {
+	if x != nil {
+		return x.Rid
+	}
+	return ""
+}
+
+type Player struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uid           string                 `protobuf:"bytes,1,opt,name=uid,proto3" json:"uid,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Player) Reset() {
+	*x = Player{}
+	mi := &file_game_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Player) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Player) ProtoMessage() {}
+
+func (x *Player) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Player.ProtoReflect.Descriptor instead.
+func (*Player) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Player) GetUid() string {
+	if x != nil {
+		return x.Uid
+	}
+	return ""
+}
+
+func (x *Player) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type Team struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uids          []string               `protobuf:"bytes,1,rep,name=uids,proto3" json:"uids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Team) Reset() {
+	*x = Team{}
+	mi := &file_game_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Team) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Team) ProtoMessage() {}
+
+func (x *Team) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Team.ProtoReflect.Descriptor instead.
+func (*Team) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Team) GetUids() []string {
+	if x != nil {
+		return x.Uids
+	}
+	return nil
+}
+
+type CreateGameRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rid           string                 `protobuf:"bytes,1,opt,name=rid,proto3" json:"rid,omitempty"`
+	GameMode      string                 `protobuf:"bytes,2,opt,name=game_mode,json=gameMode,proto3" json:"game_mode,omitempty"`
+	RoomName      string                 `protobuf:"bytes,3,opt,name=room_name,json=roomName,proto3" json:"room_name,omitempty"`
+	Players       []*Player              `protobuf:"bytes,4,rep,name=players,proto3" json:"players,omitempty"`
+	Rules         string                 `protobuf:"bytes,5,opt,name=rules,proto3" json:"rules,omitempty"`
+	Teams         map[string]*Team       `protobuf:"bytes,6,rep,name=teams,proto3" json:"teams,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	CallbackUrl   string                 `protobuf:"bytes,7,opt,name=callback_url,json=callbackUrl,proto3" json:"callback_url,omitempty"`
+	Snapshot      []byte                 `protobuf:"bytes,8,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateGameRequest) Reset() {
+	*x = CreateGameRequest{}
+	mi := &file_game_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateGameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateGameRequest) ProtoMessage() {}
+
+func (x *CreateGameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateGameRequest.ProtoReflect.Descriptor instead.
+func (*CreateGameRequest) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateGameRequest) GetRid() string {
+	if x != nil {
+		return x.Rid
+	}
+	return ""
+}
+
+func (x *CreateGameRequest) GetGameMode() string {
+	if x != nil {
+		return x.GameMode
+	}
+	return ""
+}
+
+func (x *CreateGameRequest) GetRoomName() string {
+	if x != nil {
+		return x.RoomName
+	}
+	return ""
+}
+
+func (x *CreateGameRequest) GetPlayers() []*Player {
+	if x != nil {
+		return x.Players
+	}
+	return nil
+}
+
+func (x *CreateGameRequest) GetRules() string {
+	if x != nil {
+		return x.Rules
+	}
+	return ""
+}
+
+func (x *CreateGameRequest) GetTeams() map[string]*Team {
+	if x != nil {
+		return x.Teams
+	}
+	return nil
+}
+
+func (x *CreateGameRequest) GetCallbackUrl() string {
+	if x != nil {
+		return x.CallbackUrl
+	}
+	return ""
+}
+
+func (x *CreateGameRequest) GetSnapshot() []byte {
+	if x != nil {
+		return x.Snapshot
+	}
+	return nil
+}
+
+type SetRulesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rid           string                 `protobuf:"bytes,1,opt,name=rid,proto3" json:"rid,omitempty"`
+	Rules         string                 `protobuf:"bytes,2,opt,name=rules,proto3" json:"rules,omitempty"`
+	Teams         map[string]*Team       `protobuf:"bytes,3,rep,name=teams,proto3" json:"teams,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetRulesRequest) Reset() {
+	*x = SetRulesRequest{}
+	mi := &file_game_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetRulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetRulesRequest) ProtoMessage() {}
+
+func (x *SetRulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetRulesRequest.ProtoReflect.Descriptor instead.
+func (*SetRulesRequest) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SetRulesRequest) GetRid() string {
+	if x != nil {
+		return x.Rid
+	}
+	return ""
+}
+
+func (x *SetRulesRequest) GetRules() string {
+	if x != nil {
+		return x.Rules
+	}
+	return ""
+}
+
+func (x *SetRulesRequest) GetTeams() map[string]*Team {
+	if x != nil {
+		return x.Teams
+	}
+	return nil
+}
+
+type RemovePlayerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rid           string                 `protobuf:"bytes,1,opt,name=rid,proto3" json:"rid,omitempty"`
+	Uid           string                 `protobuf:"bytes,2,opt,name=uid,proto3" json:"uid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemovePlayerRequest) Reset() {
+	*x = RemovePlayerRequest{}
+	mi := &file_game_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemovePlayerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemovePlayerRequest) ProtoMessage() {}
+
+func (x *RemovePlayerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemovePlayerRequest.ProtoReflect.Descriptor instead.
+func (*RemovePlayerRequest) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *RemovePlayerRequest) GetRid() string {
+	if x != nil {
+		return x.Rid
+	}
+	return ""
+}
+
+func (x *RemovePlayerRequest) GetUid() string {
+	if x != nil {
+		return x.Uid
+	}
+	return ""
+}
+
+type GameReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GameReply) Reset() {
+	*x = GameReply{}
+	mi := &file_game_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GameReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GameReply) ProtoMessage() {}
+
+func (x *GameReply) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GameReply.ProtoReflect.Descriptor instead.
+func (*GameReply) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{6}
+}
+
+type GameState struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Phase         string                 `protobuf:"bytes,1,opt,name=phase,proto3" json:"phase,omitempty"`
+	Scores        map[string]int64       `protobuf:"bytes,2,rep,name=scores,proto3" json:"scores,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	Players       []string               `protobuf:"bytes,3,rep,name=players,proto3" json:"players,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GameState) Reset() {
+	*x = GameState{}
+	mi := &file_game_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GameState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GameState) ProtoMessage() {}
+
+func (x *GameState) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GameState.ProtoReflect.Descriptor instead.
+func (*GameState) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GameState) GetPhase() string {
+	if x != nil {
+		return x.Phase
+	}
+	return ""
+}
+
+func (x *GameState) GetScores() map[string]int64 {
+	if x != nil {
+		return x.Scores
+	}
+	return nil
+}
+
+func (x *GameState) GetPlayers() []string {
+	if x != nil {
+		return x.Players
+	}
+	return nil
+}
+
+type Snapshot struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	State         []byte                 `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Snapshot) Reset() {
+	*x = Snapshot{}
+	mi := &file_game_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Snapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Snapshot) ProtoMessage() {}
+
+func (x *Snapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Snapshot.ProtoReflect.Descriptor instead.
+func (*Snapshot) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Snapshot) GetState() []byte {
+	if x != nil {
+		return x.State
+	}
+	return nil
+}
+
+var File_game_proto protoreflect.FileDescriptor
+
+const file_game_proto_rawDesc = "" +
+	"\n" +
+	"\n" +
+	"game.proto\x12\rengee.game.v1\"\x1f\n" +
+	"\vGameRequest\x12\x10\n" +
+	"\x03rid\x18\x01 \x01(\tR\x03rid\".\n" +
+	"\x06Player\x12\x10\n" +
+	"\x03uid\x18\x01 \x01(\tR\x03uid\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"\x1a\n" +
+	"\x04Team\x12\x12\n" +
+	"\x04uids\x18\x01 \x03(\tR\x04uids\"\xf7\x02\n" +
+	"\x11CreateGameRequest\x12\x10\n" +
+	"\x03rid\x18\x01 \x01(\tR\x03rid\x12\x1b\n" +
+	"\tgame_mode\x18\x02 \x01(\tR\bgameMode\x12\x1b\n" +
+	"\troom_name\x18\x03 \x01(\tR\broomName\x12/\n" +
+	"\aplayers\x18\x04 \x03(\v2\x15.engee.game.v1.PlayerR\aplayers\x12\x14\n" +
+	"\x05rules\x18\x05 \x01(\tR\x05rules\x12A\n" +
+	"\x05teams\x18\x06 \x03(\v2+.engee.game.v1.CreateGameRequest.TeamsEntryR\x05teams\x12!\n" +
+	"\fcallback_url\x18\a \x01(\tR\vcallbackUrl\x12\x1a\n" +
+	"\bsnapshot\x18\b \x01(\fR\bsnapshot\x1aM\n" +
+	"\n" +
+	"TeamsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12)\n" +
+	"\x05value\x18\x02 \x01(\v2\x13.engee.game.v1.TeamR\x05value:\x028\x01\"\xc9\x01\n" +
+	"\x0fSetRulesRequest\x12\x10\n" +
+	"\x03rid\x18\x01 \x01(\tR\x03rid\x12\x14\n" +
+	"\x05rules\x18\x02 \x01(\tR\x05rules\x12?\n" +
+	"\x05teams\x18\x03 \x03(\v2).engee.game.v1.SetRulesRequest.TeamsEntryR\x05teams\x1aM\n" +
+	"\n" +
+	"TeamsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12)\n" +
+	"\x05value\x18\x02 \x01(\v2\x13.engee.game.v1.TeamR\x05value:\x028\x01\"9\n" +
+	"\x13RemovePlayerRequest\x12\x10\n" +
+	"\x03rid\x18\x01 \x01(\tR\x03rid\x12\x10\n" +
+	"\x03uid\x18\x02 \x01(\tR\x03uid\"\v\n" +
+	"\tGameReply\"\xb4\x01\n" +
+	"\tGameState\x12\x14\n" +
+	"\x05phase\x18\x01 \x01(\tR\x05phase\x12<\n" +
+	"\x06scores\x18\x02 \x03(\v2$.engee.game.v1.GameState.ScoresEntryR\x06scores\x12\x18\n" +
+	"\aplayers\x18\x03 \x03(\tR\aplayers\x1a9\n" +
+	"\vScoresEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01\" \n" +
+	"\bSnapshot\x12\x14\n" +
+	"\x05state\x18\x01 \x01(\fR\x05state2\xfe\x04\n" +
+	"\vGameService\x12H\n" +
+	"\n" +
+	"CreateGame\x12 .engee.game.v1.CreateGameRequest\x1a\x18.engee.game.v1.GameReply\x12?\n" +
+	"\aEndGame\x12\x1a.engee.game.v1.GameRequest\x1a\x18.engee.game.v1.GameReply\x12D\n" +
+	"\bSetRules\x12\x1e.engee.game.v1.SetRulesRequest\x1a\x18.engee.game.v1.GameReply\x12A\n" +
+	"\tStartGame\x12\x1a.engee.game.v1.GameRequest\x1a\x18.engee.game.v1.GameReply\x12A\n" +
+	"\tPauseGame\x12\x1a.engee.game.v1.GameRequest\x1a\x18.engee.game.v1.GameReply\x12A\n" +
+	"\tResetGame\x12\x1a.engee.game.v1.GameRequest\x1a\x18.engee.game.v1.GameReply\x12L\n" +
+	"\fRemovePlayer\x12\".engee.game.v1.RemovePlayerRequest\x1a\x18.engee.game.v1.GameReply\x12@\n" +
+	"\bGetState\x12\x1a.engee.game.v1.GameRequest\x1a\x18.engee.game.v1.GameState\x12E\n" +
+	"\x0eExportSnapshot\x12\x1a.engee.game.v1.GameRequest\x1a\x17.engee.game.v1.SnapshotB Z\x1eEngee-Server/gameClient/gamepbb\x06proto3"
+
+var (
+	file_game_proto_rawDescOnce sync.Once
+	file_game_proto_rawDescData []byte
+)
+
+func file_game_proto_rawDescGZIP() []byte {
+	file_game_proto_rawDescOnce.Do(func() {
+		file_game_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_game_proto_rawDesc), len(file_game_proto_rawDesc)))
+	})
+	return file_game_proto_rawDescData
+}
+
+var file_game_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_game_proto_goTypes = []any{
+	(*GameRequest)(nil),         // 0: engee.game.v1.GameRequest
+	(*Player)(nil),              // 1: engee.game.v1.Player
+	(*Team)(nil),                // 2: engee.game.v1.Team
+	(*CreateGameRequest)(nil),   // 3: engee.game.v1.CreateGameRequest
+	(*SetRulesRequest)(nil),     // 4: engee.game.v1.SetRulesRequest
+	(*RemovePlayerRequest)(nil), // 5: engee.game.v1.RemovePlayerRequest
+	(*GameReply)(nil),           // 6: engee.game.v1.GameReply
+	(*GameState)(nil),           // 7: engee.game.v1.GameState
+	(*Snapshot)(nil),            // 8: engee.game.v1.Snapshot
+	nil,                         // 9: engee.game.v1.CreateGameRequest.TeamsEntry
+	nil,                         // 10: engee.game.v1.SetRulesRequest.TeamsEntry
+	nil,                         // 11: engee.game.v1.GameState.ScoresEntry
+}
+var file_game_proto_depIdxs = []int32{
+	1,  // 0: engee.game.v1.CreateGameRequest.players:type_name -> engee.game.v1.Player
+	9,  // 1: engee.game.v1.CreateGameRequest.teams:type_name -> engee.game.v1.CreateGameRequest.TeamsEntry
+	10, // 2: engee.game.v1.SetRulesRequest.teams:type_name -> engee.game.v1.SetRulesRequest.TeamsEntry
+	11, // 3: engee.game.v1.GameState.scores:type_name -> engee.game.v1.GameState.ScoresEntry
+	2,  // 4: engee.game.v1.CreateGameRequest.TeamsEntry.value:type_name -> engee.game.v1.Team
+	2,  // 5: engee.game.v1.SetRulesRequest.TeamsEntry.value:type_name -> engee.game.v1.Team
+	3,  // 6: engee.game.v1.GameService.CreateGame:input_type -> engee.game.v1.CreateGameRequest
+	0,  // 7: engee.game.v1.GameService.EndGame:input_type -> engee.game.v1.GameRequest
+	4,  // 8: engee.game.v1.GameService.SetRules:input_type -> engee.game.v1.SetRulesRequest
+	0,  // 9: engee.game.v1.GameService.StartGame:input_type -> engee.game.v1.GameRequest
+	0,  // 10: engee.game.v1.GameService.PauseGame:input_type -> engee.game.v1.GameRequest
+	0,  // 11: engee.game.v1.GameService.ResetGame:input_type -> engee.game.v1.GameRequest
+	5,  // 12: engee.game.v1.GameService.RemovePlayer:input_type -> engee.game.v1.RemovePlayerRequest
+	0,  // 13: engee.game.v1.GameService.GetState:input_type -> engee.game.v1.GameRequest
+	0,  // 14: engee.game.v1.GameService.ExportSnapshot:input_type -> engee.game.v1.GameRequest
+	6,  // 15: engee.game.v1.GameService.CreateGame:output_type -> engee.game.v1.GameReply
+	6,  // 16: engee.game.v1.GameService.EndGame:output_type -> engee.game.v1.GameReply
+	6,  // 17: engee.game.v1.GameService.SetRules:output_type -> engee.game.v1.GameReply
+	6,  // 18: engee.game.v1.GameService.StartGame:output_type -> engee.game.v1.GameReply
+	6,  // 19: engee.game.v1.GameService.PauseGame:output_type -> engee.game.v1.GameReply
+	6,  // 20: engee.game.v1.GameService.ResetGame:output_type -> engee.game.v1.GameReply
+	6,  // 21: engee.game.v1.GameService.RemovePlayer:output_type -> engee.game.v1.GameReply
+	7,  // 22: engee.game.v1.GameService.GetState:output_type -> engee.game.v1.GameState
+	8,  // 23: engee.game.v1.GameService.ExportSnapshot:output_type -> engee.game.v1.Snapshot
+	15, // [15:24] is the sub-list for method output_type
+	6,  // [6:15] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_game_proto_init() }
+func file_game_proto_init() {
+	if File_game_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_game_proto_rawDesc), len(file_game_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_game_proto_goTypes,
+		DependencyIndexes: file_game_proto_depIdxs,
+		MessageInfos:      file_game_proto_msgTypes,
+	}.Build()
+	File_game_proto = out.File
+	file_game_proto_goTypes = nil
+	file_game_proto_depIdxs = nil
+}
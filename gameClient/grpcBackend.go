@@ -0,0 +1,478 @@
+package gameclient
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/exp/maps"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"Engee-Server/gameClient/gamepb"
+	"Engee-Server/gameClient/payload"
+	registry "Engee-Server/gameRegistry"
+	"Engee-Server/logging"
+	sErr "Engee-Server/stockErrors"
+	"Engee-Server/tracing"
+	"Engee-Server/utils"
+)
+
+type GRPCBackend struct {
+	lock        sync.Mutex
+	games       map[string]GameInstance
+	conns       map[string]*grpc.ClientConn
+	breakers    map[string]*circuitBreaker
+	dialOptions []grpc.DialOption
+	tlsConfig   *tls.Config
+
+	callTimeout      time.Duration
+	maxAttempts      int
+	retryBackoff     time.Duration
+	breakerThreshold int
+	breakerCooldown  time.Duration
+}
+
+// NewGRPCBackend connects to each game server with TLS when its URL scheme
+// is https or grpcs, and in plain text when it is http or grpc. Options are
+// applied after the derived credentials, so they can override them.
+func NewGRPCBackend(options ...grpc.DialOption) *GRPCBackend {
+	return &GRPCBackend{
+		games:       make(map[string]GameInstance),
+		conns:       make(map[string]*grpc.ClientConn),
+		breakers:    make(map[string]*circuitBreaker),
+		dialOptions: options,
+
+		callTimeout:      defaultCallTimeout,
+		maxAttempts:      defaultMaxAttempts,
+		retryBackoff:     defaultRetryBackoff,
+		breakerThreshold: defaultBreakerThreshold,
+		breakerCooldown:  defaultBreakerCooldown,
+	}
+}
+
+func (b *GRPCBackend) CreateGameInstance(ctx context.Context, setup GameSetup) error {
+	err := validateSetup(setup)
+	if err != nil {
+		return err
+	}
+
+	if b.HasGameInstance(setup.RID) {
+		return &sErr.MatchFoundError[string]{
+			Space: "Games",
+			Field: "RID",
+			Value: setup.RID,
+		}
+	}
+
+	game := newGameInstance(setup, setup.Addr)
+
+	err = b.createGame(ctx, game, setup.Snapshot)
+	if err != nil {
+		return err
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.games[setup.RID] = game
+
+	return nil
+}
+
+func (b *GRPCBackend) MigrateGameInstance(ctx context.Context, setup GameSetup) (GameInstance, error) {
+	err := validateSetup(setup)
+	if err != nil {
+		return GameInstance{}, err
+	}
+
+	previous, err := b.GetGameInstance(setup.RID)
+	if err != nil {
+		return GameInstance{}, err
+	}
+
+	game := newGameInstance(setup, setup.Addr)
+	if game.URL == previous.URL {
+		return GameInstance{}, &sErr.MatchFoundError[string]{
+			Space: "Games",
+			Field: "URL",
+			Value: game.URL,
+		}
+	}
+
+	err = b.createGame(ctx, game, setup.Snapshot)
+	if err != nil {
+		return GameInstance{}, fmt.Errorf("could not create migrated game instance: %w", err)
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.games[setup.RID] = game
+
+	return previous, nil
+}
+
+func (b *GRPCBackend) RecreateGameInstance(ctx context.Context, rid string) error {
+	game, err := b.GetGameInstance(rid)
+	if err != nil {
+		return err
+	}
+
+	err = b.createGame(ctx, game, nil)
+	if err != nil {
+		return fmt.Errorf("could not recreate game instance: %w", err)
+	}
+
+	return nil
+}
+
+func (b *GRPCBackend) EndGame(ctx context.Context, rid string) error {
+	game, err := b.GetGameInstance(rid)
+	if err != nil {
+		return err
+	}
+
+	return b.EndGameInstance(ctx, game)
+}
+
+func (b *GRPCBackend) EndGameInstance(ctx context.Context, game GameInstance) error {
+	request := &gamepb.GameRequest{Rid: game.RID}
+	err := b.invoke(ctx, game, gamepb.GameService_EndGame_FullMethodName, true, request, func(ctx context.Context, client gamepb.GameServiceClient) error {
+		_, err := client.EndGame(ctx, request)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	forgetGameInstance(b.games, game)
+	return nil
+}
+
+func (b *GRPCBackend) SetGameRules(ctx context.Context, rid string, rules payload.Rules) error {
+	game, err := b.GetGameInstance(rid)
+	if err != nil {
+		return err
+	}
+
+	request := &gamepb.SetRulesRequest{Rid: rid, Rules: rules.Rules, Teams: toProtoTeams(rules.Teams)}
+	err = b.invoke(ctx, game, gamepb.GameService_SetRules_FullMethodName, true, request, func(ctx context.Context, client gamepb.GameServiceClient) error {
+		_, err := client.SetRules(ctx, request)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	game, found := b.games[rid]
+	if found {
+		game.Rules = rules
+		b.games[rid] = game
+	}
+
+	return nil
+}
+
+func (b *GRPCBackend) StartGame(ctx context.Context, rid string) error {
+	return b.sendGameCommand(ctx, rid, gamepb.GameService_StartGame_FullMethodName, gamepb.GameServiceClient.StartGame)
+}
+
+func (b *GRPCBackend) PauseGame(ctx context.Context, rid string) error {
+	return b.sendGameCommand(ctx, rid, gamepb.GameService_PauseGame_FullMethodName, gamepb.GameServiceClient.PauseGame)
+}
+
+func (b *GRPCBackend) ResetGame(ctx context.Context, rid string) error {
+	return b.sendGameCommand(ctx, rid, gamepb.GameService_ResetGame_FullMethodName, gamepb.GameServiceClient.ResetGame)
+}
+
+func (b *GRPCBackend) RemovePlayer(ctx context.Context, rid string, targetUID string) error {
+	game, err := b.GetGameInstance(rid)
+	if err != nil {
+		return err
+	}
+
+	request := &gamepb.RemovePlayerRequest{Rid: rid, Uid: targetUID}
+	return b.invoke(ctx, game, gamepb.GameService_RemovePlayer_FullMethodName, true, request, func(ctx context.Context, client gamepb.GameServiceClient) error {
+		_, err := client.RemovePlayer(ctx, request)
+		return err
+	})
+}
+
+func (b *GRPCBackend) GetGameState(ctx context.Context, rid string) (GameState, error) {
+	game, err := b.GetGameInstance(rid)
+	if err != nil {
+		return GameState{}, err
+	}
+
+	var reply *gamepb.GameState
+	request := &gamepb.GameRequest{Rid: rid}
+	err = b.invoke(ctx, game, gamepb.GameService_GetState_FullMethodName, true, request, func(ctx context.Context, client gamepb.GameServiceClient) error {
+		var err error
+		reply, err = client.GetState(ctx, request)
+		return err
+	})
+	if err != nil {
+		return GameState{}, err
+	}
+
+	scores := make(map[string]int, len(reply.GetScores()))
+	for uid, score := range reply.GetScores() {
+		scores[uid] = int(score)
+	}
+
+	return GameState{
+		Phase:   reply.GetPhase(),
+		Scores:  scores,
+		Players: reply.GetPlayers(),
+	}, nil
+}
+
+func (b *GRPCBackend) ExportGameSnapshot(ctx context.Context, rid string) ([]byte, error) {
+	game, err := b.GetGameInstance(rid)
+	if err != nil {
+		return nil, err
+	}
+
+	var reply *gamepb.Snapshot
+	request := &gamepb.GameRequest{Rid: rid}
+	err = b.invoke(ctx, game, gamepb.GameService_ExportSnapshot_FullMethodName, true, request, func(ctx context.Context, client gamepb.GameServiceClient) error {
+		var err error
+		reply, err = client.ExportSnapshot(ctx, request)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not export game snapshot: %w", err)
+	}
+
+	return reply.GetState(), nil
+}
+
+func (b *GRPCBackend) HasGameInstance(rid string) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	_, found := b.games[rid]
+	return found
+}
+
+func (b *GRPCBackend) GetGameInstance(rid string) (GameInstance, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	err := checkRID(b.games, rid)
+	if err != nil {
+		return GameInstance{}, err
+	}
+
+	return b.games[rid], nil
+}
+
+func (b *GRPCBackend) GetGameInstances() []GameInstance {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return maps.Values(b.games)
+}
+
+func (b *GRPCBackend) Close() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for addr, conn := range b.conns {
+		conn.Close()
+		delete(b.conns, addr)
+	}
+}
+
+func (b *GRPCBackend) createGame(ctx context.Context, game GameInstance, snapshot []byte) error {
+	players := make([]*gamepb.Player, 0, len(game.Players))
+	for _, player := range game.Players {
+		players = append(players, &gamepb.Player{Uid: player.UID, Name: player.Name})
+	}
+
+	request := &gamepb.CreateGameRequest{
+		Rid:         game.RID,
+		GameMode:    game.GameMode,
+		RoomName:    game.RoomName,
+		Players:     players,
+		Rules:       game.Rules.Rules,
+		Teams:       toProtoTeams(game.Rules.Teams),
+		CallbackUrl: game.CallbackURL,
+		Snapshot:    snapshot,
+	}
+	return b.invoke(ctx, game, gamepb.GameService_CreateGame_FullMethodName, false, request, func(ctx context.Context, client gamepb.GameServiceClient) error {
+		_, err := client.CreateGame(ctx, request)
+		return err
+	})
+}
+
+type gameCommand func(client gamepb.GameServiceClient, ctx context.Context, request *gamepb.GameRequest, options ...grpc.CallOption) (*gamepb.GameReply, error)
+
+func (b *GRPCBackend) sendGameCommand(ctx context.Context, rid string, method string, command gameCommand) error {
+	game, err := b.GetGameInstance(rid)
+	if err != nil {
+		return err
+	}
+
+	request := &gamepb.GameRequest{Rid: rid}
+	return b.invoke(ctx, game, method, true, request, func(ctx context.Context, client gamepb.GameServiceClient) error {
+		_, err := command(client, ctx, request)
+		return err
+	})
+}
+
+func (b *GRPCBackend) invoke(ctx context.Context, game GameInstance, method string, idempotent bool, request proto.Message, call func(ctx context.Context, client gamepb.GameServiceClient) error) error {
+	client, err := b.client(game.Addr)
+	if err != nil {
+		return err
+	}
+
+	body, err := proto.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("could not marshal %s request: %w", method, err)
+	}
+
+	ctx, finish := startGameRequest(ctx, game, path.Base(method))
+
+	attempts := 1
+	if idempotent {
+		attempts = b.maxAttempts
+	}
+
+	ctx = forwardRequestContext(ctx)
+
+	err = callWithRetry(ctx, b.breaker(game.Addr), game, attempts, b.retryBackoff, func() error {
+		callCtx, cancel := context.WithTimeout(ctx, b.callTimeout)
+		defer cancel()
+
+		err := call(signCall(callCtx, game.GameMode, method, body), client)
+		if err != nil {
+			return fmt.Errorf("failed to carry out %s: %w", method, err)
+		}
+
+		return nil
+	}, isUnavailable)
+	finish(err)
+
+	return err
+}
+
+func (b *GRPCBackend) client(addr string) (gamepb.GameServiceClient, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	conn, found := b.conns[addr]
+	if !found {
+		target, err := url.Parse(addr)
+		if err != nil {
+			return nil, fmt.Errorf("URL is invalid: %w", err)
+		}
+
+		creds, err := transportCredentials(target.Scheme, b.tlsConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		options := append([]grpc.DialOption{grpc.WithTransportCredentials(creds)}, b.dialOptions...)
+		conn, err = grpc.NewClient("passthrough:///"+target.Host, options...)
+		if err != nil {
+			return nil, fmt.Errorf("could not connect to game server: %w", err)
+		}
+
+		b.conns[addr] = conn
+	}
+
+	return gamepb.NewGameServiceClient(conn), nil
+}
+
+func transportCredentials(scheme string, tlsConfig *tls.Config) (credentials.TransportCredentials, error) {
+	switch scheme {
+	case "https", "grpcs":
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+
+		return credentials.NewTLS(tlsConfig.Clone()), nil
+	case "http", "grpc":
+		return insecure.NewCredentials(), nil
+	default:
+		return nil, &sErr.InvalidValueError[string]{
+			Field: "Scheme",
+			Value: scheme,
+		}
+	}
+}
+
+func (b *GRPCBackend) breaker(addr string) *circuitBreaker {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	breaker, found := b.breakers[addr]
+	if !found {
+		breaker = newCircuitBreaker(addr, b.breakerThreshold, b.breakerCooldown)
+		b.breakers[addr] = breaker
+	}
+
+	return breaker
+}
+
+func toProtoTeams(teams map[string][]string) map[string]*gamepb.Team {
+	protoTeams := make(map[string]*gamepb.Team, len(teams))
+	for name, uids := range teams {
+		protoTeams[name] = &gamepb.Team{Uids: uids}
+	}
+
+	return protoTeams
+}
+
+func forwardRequestContext(ctx context.Context) context.Context {
+	for header, value := range logging.Headers(ctx) {
+		ctx = metadata.AppendToOutgoingContext(ctx, strings.ToLower(header), value)
+	}
+
+	traceParent := tracing.TraceParent(ctx)
+	if traceParent != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, tracing.TraceParentHeader, traceParent)
+	}
+
+	return ctx
+}
+
+func signCall(ctx context.Context, gameMode string, method string, body []byte) context.Context {
+	secret := registry.GetGameModeSecret(gameMode)
+	if secret == "" {
+		return ctx
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	signature := utils.ComputeSignature(secret, "POST", method, timestamp, body)
+
+	return metadata.AppendToOutgoingContext(ctx, utils.TimestampHeader, timestamp, utils.SignatureHeader, signature)
+}
+
+func isUnavailable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Internal, codes.Unknown, codes.ResourceExhausted:
+		return true
+	}
+
+	return false
+}
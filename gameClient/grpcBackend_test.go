@@ -0,0 +1,240 @@
+package gameclient
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+
+	"Engee-Server/gameClient/gamepb"
+	"Engee-Server/gameClient/payload"
+	reg "Engee-Server/gameRegistry"
+	sErr "Engee-Server/stockErrors"
+	"Engee-Server/testDummy"
+	"Engee-Server/utils"
+)
+
+const grpcGameMode = "GRPC"
+const grpcURL = "grpc://bufnet"
+
+func TestGRPCGameLifecycle(t *testing.T) {
+	grpcBackend, dummy := setupGRPCTest(t)
+
+	err := grpcBackend.CreateGameInstance(context.Background(), GameSetup{RID: testRID, GameMode: grpcGameMode, Addr: grpcURL})
+	if err != nil {
+		t.Fatalf(`GRPCBackend.CreateGameInstance(Valid) = %v, want nil`, err)
+	}
+
+	grpcBackend.SetGameRules(context.Background(), testRID, payload.Rules{Rules: updatedRules})
+	grpcBackend.StartGame(context.Background(), testRID)
+
+	state, err := grpcBackend.GetGameState(context.Background(), testRID)
+	if state.Phase != GameStateRunning || dummy.GetRules(testRID) != updatedRules || err != nil {
+		t.Fatalf(`GRPCBackend(Lifecycle) = %v, %v, want running game with rules, nil`, state, err)
+	}
+
+	err = grpcBackend.EndGame(context.Background(), testRID)
+	if err != nil || grpcBackend.HasGameInstance(testRID) {
+		t.Fatalf(`GRPCBackend.EndGame(Valid) = %v, want nil`, err)
+	}
+}
+
+func TestGRPCCreateGameDouble(t *testing.T) {
+	grpcBackend, _ := setupGRPCTest(t)
+
+	grpcBackend.CreateGameInstance(context.Background(), GameSetup{RID: testRID, GameMode: grpcGameMode, Addr: grpcURL})
+	err := grpcBackend.CreateGameInstance(context.Background(), GameSetup{RID: testRID, GameMode: grpcGameMode, Addr: grpcURL})
+	if !errors.As(err, &sErr.MF_ERR) {
+		t.Fatalf(`GRPCBackend.CreateGameInstance(Double) = %v, want MatchFoundError`, err)
+	}
+}
+
+func TestGRPCRecreateGame(t *testing.T) {
+	grpcBackend, dummy := setupGRPCTest(t)
+
+	grpcBackend.CreateGameInstance(context.Background(), GameSetup{RID: testRID, GameMode: grpcGameMode, Addr: grpcURL})
+	grpcBackend.SetGameRules(context.Background(), testRID, payload.Rules{Rules: updatedRules})
+	dummy.EndGame(context.Background(), &gamepb.GameRequest{Rid: testRID})
+
+	err := grpcBackend.RecreateGameInstance(context.Background(), testRID)
+	if err != nil || dummy.GetRules(testRID) != updatedRules {
+		t.Fatalf(`GRPCBackend.RecreateGameInstance(Valid) = %v, want nil with rules replayed`, err)
+	}
+}
+
+func TestGRPCServerError(t *testing.T) {
+	grpcBackend, _ := setupGRPCTest(t)
+
+	grpcBackend.games[testRID] = GameInstance{RID: testRID, Addr: grpcURL, URL: grpcURL, GameMode: grpcGameMode}
+
+	err := grpcBackend.StartGame(context.Background(), testRID)
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf(`GRPCBackend.StartGame(UnknownToServer) = %v, want NotFound`, err)
+	}
+}
+
+func TestGRPCSigned(t *testing.T) {
+	var verifyErr error
+	verify := func(ctx context.Context, request any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		body, _ := proto.Marshal(request.(proto.Message))
+		verifyErr = utils.VerifySignature(testSecret, "POST", info.FullMethod, first(md, utils.TimestampHeader), first(md, utils.SignatureHeader), body)
+		return handler(ctx, request)
+	}
+
+	grpcBackend, _ := setupGRPCTest(t, grpc.UnaryInterceptor(verify))
+
+	reg.SetGameModeSecrets(map[string]string{grpcGameMode: testSecret})
+	t.Cleanup(func() {
+		reg.SetGameModeSecrets(nil)
+	})
+
+	err := grpcBackend.CreateGameInstance(context.Background(), GameSetup{RID: testRID, GameMode: grpcGameMode, Addr: grpcURL})
+	if err != nil || verifyErr != nil {
+		t.Fatalf(`GRPCBackend.CreateGameInstance(Signed) = %v, %v, want nil, nil`, err, verifyErr)
+	}
+}
+
+func TestRoutingBackendByTransport(t *testing.T) {
+	grpcBackend, _ := setupGRPCTest(t)
+	fake := NewFakeBackend()
+
+	router := NewRoutingBackend(map[string]GameBackend{
+		reg.TransportHTTP: fake,
+		reg.TransportGRPC: grpcBackend,
+	})
+
+	router.CreateGameInstance(context.Background(), GameSetup{RID: testRID, GameMode: grpcGameMode, Addr: grpcURL})
+	router.CreateGameInstance(context.Background(), GameSetup{RID: altRID, GameMode: testGameMode, Addr: testURL})
+
+	if !grpcBackend.HasGameInstance(testRID) || !fake.HasGameInstance(altRID) {
+		t.Fatalf(`RoutingBackend.CreateGameInstance(ByTransport) did not route games to their transports`)
+	}
+
+	err := router.StartGame(context.Background(), testRID)
+	if err != nil {
+		t.Fatalf(`RoutingBackend.StartGame(GRPC) = %v, want nil`, err)
+	}
+
+	err = router.StartGame(context.Background(), badRID)
+	if !errors.As(err, &sErr.MNF_ERR) {
+		t.Fatalf(`RoutingBackend.StartGame(InvalidRID) = %v, want MatchNotFoundError`, err)
+	}
+}
+
+func TestRoutingBackendMigrateTransport(t *testing.T) {
+	grpcBackend, dummy := setupGRPCTest(t)
+	fake := NewFakeBackend()
+
+	router := NewRoutingBackend(map[string]GameBackend{
+		reg.TransportHTTP: fake,
+		reg.TransportGRPC: grpcBackend,
+	})
+
+	reg.RegisterGameModeInstance(reg.GameMode{Name: testGameMode, URL: grpcURL, Transport: reg.TransportGRPC})
+	t.Cleanup(func() {
+		reg.RemoveGameModeInstance(testGameMode, "", grpcURL)
+	})
+
+	router.CreateGameInstance(context.Background(), GameSetup{RID: testRID, GameMode: testGameMode, Addr: testURL})
+	snapshot, _ := router.ExportGameSnapshot(context.Background(), testRID)
+
+	previous, err := router.MigrateGameInstance(context.Background(), GameSetup{RID: testRID, GameMode: testGameMode, Addr: grpcURL, Snapshot: snapshot})
+	if err != nil || previous.Addr != testURL || string(dummy.GetSnapshot(testRID)) != string(snapshot) {
+		t.Fatalf(`RoutingBackend.MigrateGameInstance(Transport) = %v, %v, want previous instance at %s with snapshot sent`, previous, err, testURL)
+	}
+
+	err = router.EndGameInstance(context.Background(), previous)
+	if err != nil || fake.HasGameInstance(testRID) || !router.HasGameInstance(testRID) {
+		t.Fatalf(`RoutingBackend.EndGameInstance(Previous) = %v, want old instance ended and game routed to gRPC`, err)
+	}
+
+	err = router.StartGame(context.Background(), testRID)
+	if err != nil {
+		t.Fatalf(`RoutingBackend.StartGame(Migrated) = %v, want nil`, err)
+	}
+}
+
+func TestGRPCTransportCredentials(t *testing.T) {
+	tests := map[string]string{
+		"https": "tls",
+		"grpcs": "tls",
+		"http":  "insecure",
+		"grpc":  "insecure",
+	}
+
+	for scheme, want := range tests {
+		creds, err := transportCredentials(scheme, nil)
+		if err != nil || creds.Info().SecurityProtocol != want {
+			t.Fatalf(`transportCredentials(%s) = %v, %v, want %s, nil`, scheme, creds, err, want)
+		}
+	}
+
+	_, err := transportCredentials("ftp", nil)
+	if !errors.As(err, &sErr.IV_ERR) {
+		t.Fatalf(`transportCredentials(ftp) = %v, want InvalidValueError`, err)
+	}
+}
+
+func TestGRPCTLS(t *testing.T) {
+	tlsServer := httptest.NewTLSServer(http.NotFoundHandler())
+	t.Cleanup(tlsServer.Close)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(tlsServer.Certificate())
+
+	grpcBackend, _ := setupGRPCTest(t, grpc.Creds(credentials.NewServerTLSFromCert(&tlsServer.TLS.Certificates[0])))
+	grpcBackend.tlsConfig = &tls.Config{RootCAs: roots, ServerName: "example.com"}
+
+	err := grpcBackend.CreateGameInstance(context.Background(), GameSetup{RID: altRID, GameMode: grpcGameMode, Addr: "grpcs://bufnet"})
+	if err != nil {
+		t.Fatalf(`GRPCBackend.CreateGameInstance(TLS) = %v, want nil`, err)
+	}
+
+	err = grpcBackend.CreateGameInstance(context.Background(), GameSetup{RID: testRID, GameMode: grpcGameMode, Addr: grpcURL})
+	if err == nil {
+		t.Fatalf(`GRPCBackend.CreateGameInstance(PlainToTLS) = nil, want error`)
+	}
+}
+
+func setupGRPCTest(t *testing.T, options ...grpc.ServerOption) (*GRPCBackend, *testDummy.GRPCGameDummy) {
+	listener := bufconn.Listen(1024 * 1024)
+	server, dummy := testDummy.ServeGRPC(listener, options...)
+
+	dialer := func(ctx context.Context, addr string) (net.Conn, error) {
+		return listener.DialContext(ctx)
+	}
+
+	reg.RegisterGameModeInstance(reg.GameMode{Name: grpcGameMode, URL: grpcURL, Transport: reg.TransportGRPC})
+
+	grpcBackend := NewGRPCBackend(grpc.WithContextDialer(dialer))
+
+	t.Cleanup(func() {
+		grpcBackend.Close()
+		server.Stop()
+		reg.RemoveGameMode(grpcGameMode)
+	})
+
+	return grpcBackend, dummy
+}
+
+func first(md metadata.MD, key string) string {
+	values := md.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
@@ -0,0 +1,387 @@
+package gameclient
+
+import (
+	"Engee-Server/gameClient/payload"
+	registry "Engee-Server/gameRegistry"
+	"Engee-Server/logging"
+	sErr "Engee-Server/stockErrors"
+	"Engee-Server/tracing"
+	"Engee-Server/utils"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/exp/maps"
+)
+
+const defaultCallTimeout = 5 * time.Second
+const defaultMaxAttempts = 3
+const defaultRetryBackoff = 100 * time.Millisecond
+const defaultBreakerThreshold = 5
+const defaultBreakerCooldown = 30 * time.Second
+
+type HTTPBackend struct {
+	lock     sync.Mutex
+	games    map[string]GameInstance
+	breakers map[string]*circuitBreaker
+	client   *http.Client
+
+	callTimeout      time.Duration
+	maxAttempts      int
+	retryBackoff     time.Duration
+	breakerThreshold int
+	breakerCooldown  time.Duration
+}
+
+func NewHTTPBackend() *HTTPBackend {
+	return &HTTPBackend{
+		games:    make(map[string]GameInstance),
+		breakers: make(map[string]*circuitBreaker),
+		client:   &http.Client{},
+
+		callTimeout:      defaultCallTimeout,
+		maxAttempts:      defaultMaxAttempts,
+		retryBackoff:     defaultRetryBackoff,
+		breakerThreshold: defaultBreakerThreshold,
+		breakerCooldown:  defaultBreakerCooldown,
+	}
+}
+
+func (b *HTTPBackend) CreateGameInstance(ctx context.Context, setup GameSetup) error {
+	err := validateSetup(setup)
+	if err != nil {
+		return err
+	}
+
+	if b.HasGameInstance(setup.RID) {
+		return &sErr.MatchFoundError[string]{
+			Space: "Games",
+			Field: "RID",
+			Value: setup.RID,
+		}
+	}
+
+	game := newGameInstance(setup, setup.Addr+"/games/"+setup.RID)
+
+	err = b.sendPayload(ctx, opCreateGame, game, game.Addr+"/games", http.MethodPost, game.createRequest(setup.Snapshot))
+	if err != nil {
+		return err
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.games[setup.RID] = game
+
+	return nil
+}
+
+func (b *HTTPBackend) MigrateGameInstance(ctx context.Context, setup GameSetup) (GameInstance, error) {
+	err := validateSetup(setup)
+	if err != nil {
+		return GameInstance{}, err
+	}
+
+	previous, err := b.GetGameInstance(setup.RID)
+	if err != nil {
+		return GameInstance{}, err
+	}
+
+	game := newGameInstance(setup, setup.Addr+"/games/"+setup.RID)
+	if game.URL == previous.URL {
+		return GameInstance{}, &sErr.MatchFoundError[string]{
+			Space: "Games",
+			Field: "URL",
+			Value: game.URL,
+		}
+	}
+
+	err = b.sendPayload(ctx, opCreateGame, game, game.Addr+"/games", http.MethodPost, game.createRequest(setup.Snapshot))
+	if err != nil {
+		return GameInstance{}, fmt.Errorf("could not create migrated game instance: %w", err)
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.games[setup.RID] = game
+
+	return previous, nil
+}
+
+func (b *HTTPBackend) RecreateGameInstance(ctx context.Context, rid string) error {
+	game, err := b.GetGameInstance(rid)
+	if err != nil {
+		return err
+	}
+
+	err = b.sendPayload(ctx, opCreateGame, game, game.Addr+"/games", http.MethodPost, game.createRequest(nil))
+	if err != nil {
+		return fmt.Errorf("could not recreate game instance: %w", err)
+	}
+
+	return nil
+}
+
+func (b *HTTPBackend) EndGame(ctx context.Context, rid string) error {
+	game, err := b.GetGameInstance(rid)
+	if err != nil {
+		return err
+	}
+
+	return b.EndGameInstance(ctx, game)
+}
+
+func (b *HTTPBackend) EndGameInstance(ctx context.Context, game GameInstance) error {
+	err := b.sendPayload(ctx, opEndGame, game, game.URL, http.MethodDelete, commandRequest(game.RID, ""))
+	if err != nil {
+		return err
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	forgetGameInstance(b.games, game)
+	return nil
+}
+
+func (b *HTTPBackend) SetGameRules(ctx context.Context, rid string, rules payload.Rules) error {
+	game, err := b.GetGameInstance(rid)
+	if err != nil {
+		return err
+	}
+
+	request := payload.RulesRequest{
+		Version: payload.Version,
+		RID:     rid,
+		Rules:   rules.Rules,
+		Teams:   rules.Teams,
+	}
+
+	err = b.sendPayload(ctx, opSetRules, game, game.URL+"/rules", http.MethodPut, request)
+	if err != nil {
+		return err
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	game, found := b.games[rid]
+	if found {
+		game.Rules = rules
+		b.games[rid] = game
+	}
+
+	return nil
+}
+
+func (b *HTTPBackend) StartGame(ctx context.Context, rid string) error {
+	return b.sendGameCommand(ctx, rid, opStartGame, "/start", http.MethodPut)
+}
+
+func (b *HTTPBackend) PauseGame(ctx context.Context, rid string) error {
+	return b.sendGameCommand(ctx, rid, opPauseGame, "/pause", http.MethodPut)
+}
+
+func (b *HTTPBackend) ResetGame(ctx context.Context, rid string) error {
+	return b.sendGameCommand(ctx, rid, opResetGame, "/reset", http.MethodPut)
+}
+
+func (b *HTTPBackend) RemovePlayer(ctx context.Context, rid string, targetUID string) error {
+	game, err := b.GetGameInstance(rid)
+	if err != nil {
+		return err
+	}
+
+	return b.sendPayload(ctx, opRemovePlayer, game, game.URL+"/players/"+targetUID, http.MethodDelete, commandRequest(rid, targetUID))
+}
+
+func (b *HTTPBackend) GetGameState(ctx context.Context, rid string) (GameState, error) {
+	game, err := b.GetGameInstance(rid)
+	if err != nil {
+		return GameState{}, err
+	}
+
+	response, err := b.sendRequest(ctx, opGetState, game, game.URL, http.MethodGet, []byte{})
+	if err != nil {
+		return GameState{}, err
+	}
+
+	var state GameState
+	err = json.Unmarshal([]byte(response), &state)
+	if err != nil {
+		return GameState{}, fmt.Errorf("could not unmarshal game state: %w", err)
+	}
+
+	return state, nil
+}
+
+func (b *HTTPBackend) ExportGameSnapshot(ctx context.Context, rid string) ([]byte, error) {
+	game, err := b.GetGameInstance(rid)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := b.sendRequest(ctx, opExportSnapshot, game, game.URL+"/snapshot", http.MethodGet, []byte{})
+	if err != nil {
+		return nil, fmt.Errorf("could not export game snapshot: %w", err)
+	}
+
+	return []byte(response), nil
+}
+
+func (b *HTTPBackend) HasGameInstance(rid string) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	_, found := b.games[rid]
+	return found
+}
+
+func (b *HTTPBackend) GetGameInstance(rid string) (GameInstance, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	err := checkRID(b.games, rid)
+	if err != nil {
+		return GameInstance{}, err
+	}
+
+	return b.games[rid], nil
+}
+
+func (b *HTTPBackend) GetGameInstances() []GameInstance {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return maps.Values(b.games)
+}
+
+func (b *HTTPBackend) sendGameCommand(ctx context.Context, rid string, operation string, path string, method string) error {
+	game, err := b.GetGameInstance(rid)
+	if err != nil {
+		return err
+	}
+
+	return b.sendPayload(ctx, operation, game, game.URL+path, method, commandRequest(rid, ""))
+}
+
+func (b *HTTPBackend) sendPayload(ctx context.Context, operation string, game GameInstance, url string, method string, request any) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("could not marshal game server request: %w", err)
+	}
+
+	response, err := b.sendRequest(ctx, operation, game, url, method, body)
+	if err != nil {
+		return err
+	}
+
+	if response == "" {
+		return nil
+	}
+
+	_, err = payload.ParseReply(game.RID, []byte(response))
+	return err
+}
+
+func (b *HTTPBackend) sendRequest(ctx context.Context, operation string, game GameInstance, url string, method string, body []byte) (string, error) {
+	ctx, finish := startGameRequest(ctx, game, operation)
+
+	attempts := 1
+	if isIdempotent(method) {
+		attempts = b.maxAttempts
+	}
+
+	var response string
+	err := callWithRetry(ctx, b.breaker(game.Addr), game, attempts, b.retryBackoff, func() error {
+		var err error
+		response, err = b.sendAttempt(ctx, game, url, method, body)
+		return err
+	}, isServerFailure)
+	finish(err)
+	if err != nil {
+		return "", err
+	}
+
+	return response, nil
+}
+
+func (b *HTTPBackend) sendAttempt(ctx context.Context, game GameInstance, url string, method string, body []byte) (string, error) {
+	callCtx, cancel := context.WithTimeout(ctx, b.callTimeout)
+	defer cancel()
+
+	reqBody := bytes.NewReader(body)
+
+	request, err := http.NewRequestWithContext(callCtx, method, url, reqBody)
+	if err != nil {
+		return "", err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	for header, value := range logging.Headers(ctx) {
+		request.Header.Set(header, value)
+	}
+
+	traceParent := tracing.TraceParent(ctx)
+	if traceParent != "" {
+		request.Header.Set(tracing.TraceParentHeader, traceParent)
+	}
+
+	secret := registry.GetGameModeSecret(game.GameMode)
+	if secret != "" {
+		utils.SignRequest(request, secret, body)
+	}
+
+	response, err := b.client.Do(request)
+	if err != nil {
+		return "", fmt.Errorf("failed to carry out http request: %w", err)
+	}
+	defer response.Body.Close()
+
+	resBody, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return "", fmt.Errorf("could not read request response body: %w", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return "", &sErr.HttpRequestError{
+			Call: fmt.Sprintf("%s: %q", method, url),
+			Code: response.StatusCode,
+		}
+	}
+
+	return string(resBody), nil
+}
+
+func (b *HTTPBackend) breaker(addr string) *circuitBreaker {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	breaker, found := b.breakers[addr]
+	if !found {
+		breaker = newCircuitBreaker(addr, b.breakerThreshold, b.breakerCooldown)
+		b.breakers[addr] = breaker
+	}
+
+	return breaker
+}
+
+func isIdempotent(method string) bool {
+	return method == http.MethodGet || method == http.MethodPut || method == http.MethodDelete
+}
+
+func isServerFailure(err error) bool {
+	var requestErr *sErr.HttpRequestError
+	if errors.As(err, &requestErr) {
+		return requestErr.Code >= http.StatusInternalServerError
+	}
+
+	return true
+}
@@ -0,0 +1,251 @@
+package gameclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"Engee-Server/gameClient/payload"
+	reg "Engee-Server/gameRegistry"
+	"Engee-Server/logging"
+	sErr "Engee-Server/stockErrors"
+	"Engee-Server/tracing"
+)
+
+const flakyGameMode = "Flaky"
+
+type flakyServer struct {
+	lock        sync.Mutex
+	calls       map[string]int
+	failures    int
+	code        int
+	delay       time.Duration
+	requestID   string
+	traceParent string
+	server      *httptest.Server
+}
+
+func TestEndGameRetriesServerError(t *testing.T) {
+	flaky, backend := setupFlakyTest(t)
+	flaky.fail(2, http.StatusServiceUnavailable)
+	backend.breakerThreshold = 5
+
+	err := backend.EndGame(context.Background(), testRID)
+	if err != nil || flaky.count(http.MethodDelete) != 3 {
+		t.Fatalf(`EndGame(Retry) = %v after %d calls, want nil after 3`, err, flaky.count(http.MethodDelete))
+	}
+}
+
+func TestCreateGameDoesNotRetry(t *testing.T) {
+	flaky, backend := setupFlakyTest(t)
+	flaky.fail(1, http.StatusServiceUnavailable)
+
+	err := backend.CreateGameInstance(context.Background(), GameSetup{RID: altRID, GameMode: flakyGameMode, Addr: flaky.server.URL})
+	if !errors.As(err, &sErr.HR_ERR) || flaky.count(http.MethodPost) != 1 {
+		t.Fatalf(`CreateGameInstance(NoRetry) = %v after %d calls, want HttpRequestError after 1`, err, flaky.count(http.MethodPost))
+	}
+}
+
+func TestClientErrorDoesNotRetry(t *testing.T) {
+	flaky, backend := setupFlakyTest(t)
+	flaky.fail(1, http.StatusBadRequest)
+
+	err := backend.SetGameRules(context.Background(), testRID, payload.Rules{Rules: updatedRules})
+	if !errors.As(err, &sErr.HR_ERR) || flaky.count(http.MethodPut) != 1 {
+		t.Fatalf(`SetGameRules(ClientError) = %v after %d calls, want HttpRequestError after 1`, err, flaky.count(http.MethodPut))
+	}
+}
+
+func TestCallTimeout(t *testing.T) {
+	flaky, backend := setupFlakyTest(t)
+	flaky.slow(200 * time.Millisecond)
+	backend.callTimeout = 20 * time.Millisecond
+	backend.maxAttempts = 1
+
+	start := time.Now()
+	err := backend.StartGame(context.Background(), testRID)
+	if err == nil || time.Since(start) > 150*time.Millisecond {
+		t.Fatalf(`StartGame(Timeout) = %v after %v, want error within 150ms`, err, time.Since(start))
+	}
+}
+
+func TestRequestContextDeadline(t *testing.T) {
+	flaky, backend := setupFlakyTest(t)
+	flaky.slow(200 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+
+	err := backend.StartGame(ctx, testRID)
+	if !errors.Is(err, context.DeadlineExceeded) || flaky.count(http.MethodPut) != 1 {
+		t.Fatalf(`StartGame(Deadline) = %v after %d calls, want DeadlineExceeded after 1`, err, flaky.count(http.MethodPut))
+	}
+}
+
+func TestCircuitBreakerOpens(t *testing.T) {
+	flaky, backend := setupFlakyTest(t)
+	flaky.fail(100, http.StatusInternalServerError)
+
+	backend.StartGame(context.Background(), testRID)
+	backend.StartGame(context.Background(), testRID)
+
+	err := backend.StartGame(context.Background(), testRID)
+	if !errors.As(err, &sErr.UA_ERR) || flaky.count(http.MethodPut) != 2 {
+		t.Fatalf(`StartGame(BreakerOpen) = %v after %d calls, want UnavailableError after 2`, err, flaky.count(http.MethodPut))
+	}
+
+	mode, _ := reg.ResolveGameMode(flakyGameMode, "")
+	if mode.Health != reg.HealthUnhealthy {
+		t.Fatalf(`StartGame(BreakerOpen) health = %q, want %q`, mode.Health, reg.HealthUnhealthy)
+	}
+}
+
+func TestCircuitBreakerRecovers(t *testing.T) {
+	flaky, backend := setupFlakyTest(t)
+	flaky.fail(2, http.StatusInternalServerError)
+	backend.breakerCooldown = 10 * time.Millisecond
+
+	backend.StartGame(context.Background(), testRID)
+	backend.StartGame(context.Background(), testRID)
+	time.Sleep(20 * time.Millisecond)
+
+	err := backend.StartGame(context.Background(), testRID)
+	if err != nil {
+		t.Fatalf(`StartGame(BreakerRecovered) = %v, want nil`, err)
+	}
+
+	mode, _ := reg.ResolveGameMode(flakyGameMode, "")
+	if mode.Health != reg.HealthHealthy {
+		t.Fatalf(`StartGame(BreakerRecovered) health = %q, want %q`, mode.Health, reg.HealthHealthy)
+	}
+}
+
+func TestCircuitBreakerCancelledProbe(t *testing.T) {
+	flaky, backend := setupFlakyTest(t)
+	flaky.fail(2, http.StatusInternalServerError)
+	backend.breakerCooldown = 10 * time.Millisecond
+
+	backend.StartGame(context.Background(), testRID)
+	backend.StartGame(context.Background(), testRID)
+	time.Sleep(20 * time.Millisecond)
+
+	flaky.slow(200 * time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	backend.StartGame(ctx, testRID)
+	flaky.slow(0)
+	time.Sleep(20 * time.Millisecond)
+
+	err := backend.StartGame(context.Background(), testRID)
+	if err != nil {
+		t.Fatalf(`StartGame(CancelledProbe) = %v, want nil`, err)
+	}
+}
+
+func TestForwardsRequestID(t *testing.T) {
+	flaky, backend := setupFlakyTest(t)
+
+	ctx := logging.WithRequestID(context.Background(), "test-request")
+
+	err := backend.StartGame(ctx, testRID)
+	if err != nil || flaky.lastRequestID() != "test-request" {
+		t.Fatalf(`StartGame(RequestID) = %v with %q, want nil with "test-request"`, err, flaky.lastRequestID())
+	}
+}
+
+func TestForwardsTraceParent(t *testing.T) {
+	flaky, backend := setupFlakyTest(t)
+
+	ctx, span := tracing.Start(context.Background(), "test")
+
+	err := backend.StartGame(ctx, testRID)
+	if err != nil || !strings.HasPrefix(flaky.lastTraceParent(), "00-"+span.TraceID()+"-") {
+		t.Fatalf(`StartGame(TraceParent) = %v with %q, want nil with trace %s`, err, flaky.lastTraceParent(), span.TraceID())
+	}
+}
+
+func setupFlakyTest(t *testing.T) (*flakyServer, *HTTPBackend) {
+	flaky := &flakyServer{calls: make(map[string]int)}
+	flaky.server = httptest.NewServer(http.HandlerFunc(flaky.handle))
+
+	reg.RegisterGameMode(flakyGameMode, flaky.server.URL)
+
+	flakyBackend := NewHTTPBackend()
+	flakyBackend.retryBackoff = time.Millisecond
+	flakyBackend.breakerThreshold = 2
+	flakyBackend.games[testRID] = GameInstance{
+		RID:      testRID,
+		Addr:     flaky.server.URL,
+		URL:      flaky.server.URL + "/games/" + testRID,
+		GameMode: flakyGameMode,
+	}
+
+	t.Cleanup(func() {
+		flaky.server.Close()
+		reg.RemoveGameMode(flakyGameMode)
+	})
+
+	return flaky, flakyBackend
+}
+
+func (f *flakyServer) handle(w http.ResponseWriter, r *http.Request) {
+	f.lock.Lock()
+	f.calls[r.Method]++
+	f.requestID = r.Header.Get(logging.RequestIDHeader)
+	f.traceParent = r.Header.Get(tracing.TraceParentHeader)
+	failing := f.failures > 0
+	if failing {
+		f.failures--
+	}
+	code := f.code
+	delay := f.delay
+	f.lock.Unlock()
+
+	time.Sleep(delay)
+
+	if failing {
+		w.WriteHeader(code)
+	}
+}
+
+func (f *flakyServer) fail(times int, code int) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.failures = times
+	f.code = code
+}
+
+func (f *flakyServer) slow(delay time.Duration) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.delay = delay
+}
+
+func (f *flakyServer) count(method string) int {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return f.calls[method]
+}
+
+func (f *flakyServer) lastRequestID() string {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return f.requestID
+}
+
+func (f *flakyServer) lastTraceParent() string {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return f.traceParent
+}
@@ -0,0 +1,94 @@
+package payload
+
+import (
+	"encoding/json"
+	"fmt"
+
+	sErr "Engee-Server/stockErrors"
+)
+
+const Version = 1
+
+const StatusOK = "ok"
+const StatusError = "error"
+
+type Player struct {
+	UID  string `json:"uid"`
+	Name string `json:"name"`
+}
+
+type Rules struct {
+	Rules string              `json:"rules"`
+	Teams map[string][]string `json:"teams,omitempty"`
+}
+
+type CreateGameRequest struct {
+	Version     int                 `json:"version"`
+	RID         string              `json:"rid"`
+	RoomName    string              `json:"room_name"`
+	GameMode    string              `json:"gamemode"`
+	Players     []Player            `json:"players"`
+	Rules       string              `json:"rules"`
+	Teams       map[string][]string `json:"teams,omitempty"`
+	CallbackURL string              `json:"callback_url,omitempty"`
+	Snapshot    []byte              `json:"snapshot,omitempty"`
+}
+
+type RulesRequest struct {
+	Version int                 `json:"version"`
+	RID     string              `json:"rid"`
+	Rules   string              `json:"rules"`
+	Teams   map[string][]string `json:"teams,omitempty"`
+}
+
+type CommandRequest struct {
+	Version int    `json:"version"`
+	RID     string `json:"rid"`
+	UID     string `json:"uid,omitempty"`
+}
+
+type Reply struct {
+	Version int    `json:"version"`
+	RID     string `json:"rid"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+func ParseReply(rid string, body []byte) (Reply, error) {
+	var reply Reply
+	err := json.Unmarshal(body, &reply)
+	if err != nil {
+		return Reply{}, fmt.Errorf("could not unmarshal game server reply: %w", err)
+	}
+
+	if reply.Version != Version {
+		return reply, &sErr.InvalidValueError[int]{
+			Field: "Reply version",
+			Value: reply.Version,
+		}
+	}
+
+	if reply.RID != rid {
+		return reply, &sErr.InvalidValueError[string]{
+			Field: "Reply RID",
+			Value: reply.RID,
+		}
+	}
+
+	if reply.Status != StatusOK {
+		return reply, fmt.Errorf("game server reported %s: %s", reply.Status, reply.Message)
+	}
+
+	return reply, nil
+}
+
+func CheckVersion(version int) error {
+	if version != Version {
+		return &sErr.InvalidValueError[int]{
+			Field: "Payload version",
+			Value: version,
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package payload
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	sErr "Engee-Server/stockErrors"
+)
+
+const testRID = "test-rid"
+const altRID = "alt-rid"
+
+func TestParseReply(t *testing.T) {
+	reply, err := ParseReply(testRID, marshalReply(t, Version, testRID, StatusOK))
+	if err != nil || reply.RID != testRID {
+		t.Fatalf(`ParseReply(Valid) = %v, %v, want %s, nil`, reply, err, testRID)
+	}
+}
+
+func TestParseReplyWrongRID(t *testing.T) {
+	_, err := ParseReply(testRID, marshalReply(t, Version, altRID, StatusOK))
+	if !errors.As(err, &sErr.IV_ERR) {
+		t.Fatalf(`ParseReply(WrongRID) = %v, want InvalidValueError`, err)
+	}
+}
+
+func TestParseReplyWrongVersion(t *testing.T) {
+	var ivErr *sErr.InvalidValueError[int]
+
+	_, err := ParseReply(testRID, marshalReply(t, Version+1, testRID, StatusOK))
+	if !errors.As(err, &ivErr) {
+		t.Fatalf(`ParseReply(WrongVersion) = %v, want InvalidValueError`, err)
+	}
+}
+
+func TestParseReplyErrorStatus(t *testing.T) {
+	_, err := ParseReply(testRID, marshalReply(t, Version, testRID, StatusError))
+	if err == nil {
+		t.Fatalf(`ParseReply(ErrorStatus) = nil, want error`)
+	}
+}
+
+func TestParseReplyMalformed(t *testing.T) {
+	_, err := ParseReply(testRID, []byte("not json"))
+	if err == nil {
+		t.Fatalf(`ParseReply(Malformed) = nil, want error`)
+	}
+}
+
+func TestCheckVersion(t *testing.T) {
+	err := CheckVersion(Version)
+	if err != nil {
+		t.Fatalf(`CheckVersion(Valid) = %v, want nil`, err)
+	}
+
+	err = CheckVersion(0)
+	if err == nil {
+		t.Fatalf(`CheckVersion(Invalid) = nil, want InvalidValueError`)
+	}
+}
+
+func marshalReply(t *testing.T, version int, rid string, status string) []byte {
+	body, err := json.Marshal(Reply{
+		Version: version,
+		RID:     rid,
+		Status:  status,
+	})
+	if err != nil {
+		t.Fatalf("Could not marshal reply: %v", err)
+	}
+
+	return body
+}
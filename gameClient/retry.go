@@ -0,0 +1,70 @@
+package gameclient
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"time"
+
+	registry "Engee-Server/gameRegistry"
+)
+
+func callWithRetry(ctx context.Context, breaker *circuitBreaker, game GameInstance, attempts int, backoff time.Duration, attempt func() error, isFailure func(err error) bool) error {
+	var err error
+	for try := 0; try < attempts; try++ {
+		if try > 0 {
+			err = waitBeforeRetry(ctx, backoff, try)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = breaker.allow()
+		if err != nil {
+			return err
+		}
+
+		err = attempt()
+		if ctx.Err() != nil {
+			breaker.recordAbandoned()
+			return fmt.Errorf("game server call abandoned: %w", ctx.Err())
+		}
+
+		if err == nil || !isFailure(err) {
+			if breaker.recordSuccess() {
+				reportHealth(game, registry.HealthHealthy)
+			}
+
+			return err
+		}
+
+		if breaker.recordFailure() {
+			reportHealth(game, registry.HealthUnhealthy)
+		}
+	}
+
+	return err
+}
+
+func waitBeforeRetry(ctx context.Context, retryBackoff time.Duration, try int) error {
+	backoff := retryBackoff * time.Duration(1<<(try-1))
+	jitter := time.Duration(rand.Int63n(int64(backoff)/2 + 1))
+
+	timer := time.NewTimer(backoff/2 + jitter)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("gave up retrying game server call: %w", ctx.Err())
+	}
+}
+
+func reportHealth(game GameInstance, health string) {
+	err := registry.SetGameModeHealth(game.GameMode, "", game.Addr, health)
+	if err != nil {
+		slog.Error("Reporting game server health", "gamemode", game.GameMode, "addr", game.Addr, "error", err)
+	}
+}
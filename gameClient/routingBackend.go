@@ -0,0 +1,254 @@
+package gameclient
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"Engee-Server/gameClient/payload"
+	registry "Engee-Server/gameRegistry"
+	sErr "Engee-Server/stockErrors"
+)
+
+type RoutingBackend struct {
+	lock     sync.Mutex
+	backends map[string]GameBackend
+	routes   map[string]GameBackend
+}
+
+func NewRoutingBackend(backends map[string]GameBackend) *RoutingBackend {
+	return &RoutingBackend{
+		backends: backends,
+		routes:   make(map[string]GameBackend),
+	}
+}
+
+func NewDefaultBackend() *RoutingBackend {
+	return NewRoutingBackend(map[string]GameBackend{
+		registry.TransportHTTP: NewHTTPBackend(),
+		registry.TransportGRPC: NewGRPCBackend(),
+	})
+}
+
+func (b *RoutingBackend) CreateGameInstance(ctx context.Context, setup GameSetup) error {
+	backend, err := b.transportBackend(setup.GameMode, setup.Addr)
+	if err != nil {
+		return err
+	}
+
+	err = backend.CreateGameInstance(ctx, setup)
+	if err != nil {
+		return err
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.routes[setup.RID] = backend
+
+	return nil
+}
+
+func (b *RoutingBackend) RecreateGameInstance(ctx context.Context, rid string) error {
+	backend, err := b.route(rid)
+	if err != nil {
+		return err
+	}
+
+	return backend.RecreateGameInstance(ctx, rid)
+}
+
+func (b *RoutingBackend) EndGame(ctx context.Context, rid string) error {
+	backend, err := b.route(rid)
+	if err != nil {
+		return err
+	}
+
+	err = backend.EndGame(ctx, rid)
+	if err != nil {
+		return err
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	delete(b.routes, rid)
+	return nil
+}
+
+func (b *RoutingBackend) EndGameInstance(ctx context.Context, game GameInstance) error {
+	backend, err := b.instanceBackend(game)
+	if err != nil {
+		return err
+	}
+
+	err = backend.EndGameInstance(ctx, game)
+	if err != nil {
+		return err
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.routes[game.RID] == backend && !backend.HasGameInstance(game.RID) {
+		delete(b.routes, game.RID)
+	}
+
+	return nil
+}
+
+func (b *RoutingBackend) MigrateGameInstance(ctx context.Context, setup GameSetup) (GameInstance, error) {
+	current, err := b.route(setup.RID)
+	if err != nil {
+		return GameInstance{}, err
+	}
+
+	target, err := b.transportBackend(setup.GameMode, setup.Addr)
+	if err != nil {
+		return GameInstance{}, err
+	}
+
+	if target == current {
+		return current.MigrateGameInstance(ctx, setup)
+	}
+
+	previous, err := current.GetGameInstance(setup.RID)
+	if err != nil {
+		return GameInstance{}, err
+	}
+
+	err = target.CreateGameInstance(ctx, setup)
+	if err != nil {
+		return GameInstance{}, fmt.Errorf("could not create migrated game instance: %w", err)
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.routes[setup.RID] = target
+
+	return previous, nil
+}
+
+func (b *RoutingBackend) ExportGameSnapshot(ctx context.Context, rid string) ([]byte, error) {
+	backend, err := b.route(rid)
+	if err != nil {
+		return nil, err
+	}
+
+	return backend.ExportGameSnapshot(ctx, rid)
+}
+
+func (b *RoutingBackend) SetGameRules(ctx context.Context, rid string, rules payload.Rules) error {
+	backend, err := b.route(rid)
+	if err != nil {
+		return err
+	}
+
+	return backend.SetGameRules(ctx, rid, rules)
+}
+
+func (b *RoutingBackend) StartGame(ctx context.Context, rid string) error {
+	backend, err := b.route(rid)
+	if err != nil {
+		return err
+	}
+
+	return backend.StartGame(ctx, rid)
+}
+
+func (b *RoutingBackend) PauseGame(ctx context.Context, rid string) error {
+	backend, err := b.route(rid)
+	if err != nil {
+		return err
+	}
+
+	return backend.PauseGame(ctx, rid)
+}
+
+func (b *RoutingBackend) ResetGame(ctx context.Context, rid string) error {
+	backend, err := b.route(rid)
+	if err != nil {
+		return err
+	}
+
+	return backend.ResetGame(ctx, rid)
+}
+
+func (b *RoutingBackend) RemovePlayer(ctx context.Context, rid string, targetUID string) error {
+	backend, err := b.route(rid)
+	if err != nil {
+		return err
+	}
+
+	return backend.RemovePlayer(ctx, rid, targetUID)
+}
+
+func (b *RoutingBackend) GetGameState(ctx context.Context, rid string) (GameState, error) {
+	backend, err := b.route(rid)
+	if err != nil {
+		return GameState{}, err
+	}
+
+	return backend.GetGameState(ctx, rid)
+}
+
+func (b *RoutingBackend) HasGameInstance(rid string) bool {
+	_, err := b.route(rid)
+	return err == nil
+}
+
+func (b *RoutingBackend) GetGameInstance(rid string) (GameInstance, error) {
+	backend, err := b.route(rid)
+	if err != nil {
+		return GameInstance{}, err
+	}
+
+	return backend.GetGameInstance(rid)
+}
+
+func (b *RoutingBackend) GetGameInstances() []GameInstance {
+	instances := make([]GameInstance, 0)
+	for _, backend := range b.backends {
+		instances = append(instances, backend.GetGameInstances()...)
+	}
+
+	return instances
+}
+
+func (b *RoutingBackend) transportBackend(gameMode string, addr string) (GameBackend, error) {
+	transport := registry.GetGameModeTransport(gameMode, addr)
+
+	backend, found := b.backends[transport]
+	if !found {
+		return nil, &sErr.InvalidValueError[string]{
+			Field: "Transport",
+			Value: transport,
+		}
+	}
+
+	return backend, nil
+}
+
+func (b *RoutingBackend) instanceBackend(game GameInstance) (GameBackend, error) {
+	for _, backend := range b.backends {
+		current, err := backend.GetGameInstance(game.RID)
+		if err == nil && current.URL == game.URL {
+			return backend, nil
+		}
+	}
+
+	return b.transportBackend(game.GameMode, game.Addr)
+}
+
+func (b *RoutingBackend) route(rid string) (GameBackend, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	err := checkRID(b.routes, rid)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.routes[rid], nil
+}
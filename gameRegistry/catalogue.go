@@ -0,0 +1,32 @@
+package gameRegistry
+
+import "fmt"
+
+type CatalogueEntry struct {
+	Name      string            `json:"name"`
+	Version   string            `json:"version"`
+	Protocol  int               `json:"protocol"`
+	Transport string            `json:"transport"`
+	URL       string            `json:"url"`
+	Metadata  map[string]string `json:"metadata"`
+	Heartbeat bool              `json:"heartbeat"`
+}
+
+func LoadCatalogue(entries []CatalogueEntry) error {
+	for _, entry := range entries {
+		err := registerInstance(GameMode{
+			Name:      entry.Name,
+			Version:   entry.Version,
+			Protocol:  entry.Protocol,
+			Transport: entry.Transport,
+			URL:       entry.URL,
+			Static:    !entry.Heartbeat,
+			Metadata:  entry.Metadata,
+		})
+		if err != nil {
+			return fmt.Errorf("could not load catalogue entry %q: %w", entry.Name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package gameRegistry
+
+import (
+	"errors"
+	"testing"
+
+	sErr "Engee-Server/stockErrors"
+)
+
+var testCatalogue = []CatalogueEntry{
+	{
+		Name:     testGameMode,
+		Version:  "1.0.0",
+		URL:      testAddress,
+		Metadata: map[string]string{"players": "2-8"},
+	},
+	{
+		Name:      altGameMode,
+		URL:       testAddress,
+		Heartbeat: true,
+	},
+}
+
+func TestLoadCatalogue(t *testing.T) {
+	err := LoadCatalogue(testCatalogue)
+	if err != nil {
+		t.Fatalf(`LoadCatalogue(Valid) = %v, want nil`, err)
+	}
+
+	t.Cleanup(cleanUpAfterTest)
+
+	mode, err := ResolveGameMode(testGameMode, "")
+	if !mode.Static || mode.Metadata["players"] != "2-8" || err != nil {
+		t.Fatalf(`ResolveGameMode(Catalogue) = %v, %v, want static entry with metadata, nil`, mode, err)
+	}
+}
+
+func TestLoadCatalogueStaticNotMonitored(t *testing.T) {
+	LoadCatalogue(testCatalogue)
+	t.Cleanup(cleanUpAfterTest)
+
+	Heartbeat(testGameMode, "", testAddress, "")
+
+	lock.Lock()
+	_, staticMonitored := heartbeats[instanceKey(testGameMode, "1.0.0", testAddress)]
+	_, dynamicMonitored := heartbeats[instanceKey(altGameMode, DefaultVersion, testAddress)]
+	lock.Unlock()
+
+	if staticMonitored || !dynamicMonitored {
+		t.Fatalf(`LoadCatalogue(Monitoring) = static %v, heartbeat %v, want false, true`, staticMonitored, dynamicMonitored)
+	}
+}
+
+func TestLoadCatalogueDuplicate(t *testing.T) {
+	LoadCatalogue(testCatalogue)
+	t.Cleanup(cleanUpAfterTest)
+
+	err := LoadCatalogue(testCatalogue)
+	if !errors.As(err, &sErr.MF_ERR) {
+		t.Fatalf(`LoadCatalogue(Duplicate) = %v, want MatchFoundError`, err)
+	}
+}
+
+func TestLoadCatalogueInvalidURL(t *testing.T) {
+	err := LoadCatalogue([]CatalogueEntry{{Name: testGameMode}})
+	if !errors.As(err, &sErr.EV_ERR) {
+		t.Fatalf(`LoadCatalogue(InvalidURL) = %v, want EmptyValueError`, err)
+	}
+
+	t.Cleanup(cleanUpAfterTest)
+}
+
+func TestRegisterGameCannotClaimStatic(t *testing.T) {
+	RegisterGameModeInstance(GameMode{Name: testGameMode, URL: testAddress, Static: true})
+	t.Cleanup(cleanUpAfterTest)
+
+	mode, _ := ResolveGameMode(testGameMode, "")
+	if mode.Static {
+		t.Fatalf(`RegisterGameModeInstance(Static) registered static entry, want dynamic`)
+	}
+}
@@ -0,0 +1,111 @@
+package gameRegistry
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	sErr "Engee-Server/stockErrors"
+	"Engee-Server/utils"
+)
+
+const EventRegistered = "registered"
+const EventRestarted = "restarted"
+const EventHeartbeatLost = "heartbeat_lost"
+const EventRemoved = "removed"
+const EventHealthChanged = "health_changed"
+
+const webhookTimeout = 5 * time.Second
+
+type RegistryEvent struct {
+	Type     string    `json:"type"`
+	GameMode GameMode  `json:"gamemode"`
+	Time     time.Time `json:"time"`
+}
+
+var registryEvents = utils.NewBroadcaster[RegistryEvent]()
+var webhooks []string
+var webhookSecret string
+var webhookClient = &http.Client{Timeout: webhookTimeout}
+
+func SubscribeToRegistry() chan RegistryEvent {
+	return registryEvents.Subscribe()
+}
+
+func UnsubscribeFromRegistry(subscriber chan RegistryEvent) {
+	registryEvents.Unsubscribe(subscriber)
+}
+
+func CloseRegistryEvents() {
+	registryEvents.Close()
+}
+
+func SetWebhooks(urls []string, secret string) error {
+	for _, url := range urls {
+		err := utils.ValidateURL(url)
+		if err != nil {
+			return fmt.Errorf("webhook URL is invalid: %w", err)
+		}
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	webhooks = append([]string{}, urls...)
+	webhookSecret = secret
+
+	return nil
+}
+
+// publishEvent expects the caller to hold lock, which also guards the
+// webhook URLs and secret handed to each delivery.
+func publishEvent(eventType string, mode GameMode) {
+	event := RegistryEvent{
+		Type:     eventType,
+		GameMode: mode.Public(),
+		Time:     time.Now(),
+	}
+
+	registryEvents.Publish(event)
+
+	for _, url := range webhooks {
+		go sendWebhook(url, webhookSecret, event)
+	}
+}
+
+func sendWebhook(url string, secret string, event RegistryEvent) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		slog.Error("Marshalling registry event", "error", err)
+		return
+	}
+
+	request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		slog.Error("Creating registry webhook request", "url", url, "error", err)
+		return
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	if secret != "" {
+		utils.SignRequest(request, secret, body)
+	}
+
+	response, err := webhookClient.Do(request)
+	if err != nil {
+		slog.Error("Sending registry webhook", "url", url, "error", err)
+		return
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		err = &sErr.HttpRequestError{
+			Call: fmt.Sprintf("%s: %q", http.MethodPost, url),
+			Code: response.StatusCode,
+		}
+		slog.Error("Sending registry webhook", "url", url, "error", err)
+	}
+}
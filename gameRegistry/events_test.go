@@ -0,0 +1,170 @@
+package gameRegistry
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"Engee-Server/utils"
+)
+
+func TestRegistryEventRegistered(t *testing.T) {
+	events := setupEventTest(t)
+
+	RegisterGameMode(testGameMode, testAddress)
+
+	event := confirmRegistryEvent(t, events, EventRegistered)
+	if event.GameMode.Name != testGameMode || event.GameMode.URL != "" {
+		t.Fatalf(`RegistryEvent(Registered) = %v, want %q without its URL`, event.GameMode, testGameMode)
+	}
+}
+
+func TestRegistryEventRemoved(t *testing.T) {
+	events := setupEventTest(t)
+	RegisterGameMode(testGameMode, testAddress)
+	confirmRegistryEvent(t, events, EventRegistered)
+
+	RemoveGameMode(testGameMode)
+
+	confirmRegistryEvent(t, events, EventRemoved)
+}
+
+func TestRegistryEventHeartbeatLost(t *testing.T) {
+	events := setupEventTest(t)
+	RegisterGameMode(testGameMode, testAddress)
+	confirmRegistryEvent(t, events, EventRegistered)
+
+	expireInstance(instanceKey(testGameMode, DefaultVersion, testAddress))
+
+	confirmRegistryEvent(t, events, EventHeartbeatLost)
+	confirmRegistryEvent(t, events, EventRemoved)
+}
+
+func TestRegistryEventHealthChanged(t *testing.T) {
+	events := setupEventTest(t)
+	RegisterGameMode(testGameMode, testAddress)
+	confirmRegistryEvent(t, events, EventRegistered)
+
+	SetGameModeHealth(testGameMode, "", "", HealthUnhealthy)
+	event := confirmRegistryEvent(t, events, EventHealthChanged)
+	if event.GameMode.Health != HealthUnhealthy {
+		t.Fatalf(`RegistryEvent(HealthChanged) health = %q, want %q`, event.GameMode.Health, HealthUnhealthy)
+	}
+
+	Heartbeat(testGameMode, "", testAddress, "")
+	event = confirmRegistryEvent(t, events, EventHealthChanged)
+	if event.GameMode.Health != HealthHealthy {
+		t.Fatalf(`RegistryEvent(HealthRestored) health = %q, want %q`, event.GameMode.Health, HealthHealthy)
+	}
+}
+
+func TestRegistryEventWebhook(t *testing.T) {
+	received := make(chan RegistryEvent, 1)
+	webhookServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if utils.VerifyRequest(r, testSecret, body) != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var event RegistryEvent
+		json.Unmarshal(body, &event)
+		received <- event
+	}))
+	defer webhookServer.Close()
+
+	err := SetWebhooks([]string{webhookServer.URL}, testSecret)
+	if err != nil {
+		t.Fatalf(`SetWebhooks(Valid) = %v, want nil`, err)
+	}
+
+	t.Cleanup(func() {
+		SetWebhooks(nil, "")
+	})
+	t.Cleanup(cleanUpAfterTest)
+
+	RegisterGameMode(testGameMode, testAddress)
+
+	select {
+	case event := <-received:
+		if event.Type != EventRegistered || event.GameMode.Name != testGameMode || event.GameMode.URL != "" {
+			t.Fatalf(`Webhook(Registered) = %v, want %q for %q without its URL`, event, EventRegistered, testGameMode)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf(`Webhook(Registered) not received`)
+	}
+}
+
+func TestRegistryEventWebhookSecretChanged(t *testing.T) {
+	webhookServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer webhookServer.Close()
+
+	t.Cleanup(func() {
+		SetWebhooks(nil, "")
+	})
+	t.Cleanup(cleanUpAfterTest)
+
+	SetWebhooks([]string{webhookServer.URL}, testSecret)
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+				SetWebhooks([]string{webhookServer.URL}, fmt.Sprintf("secret-%d", i))
+			}
+		}
+	}()
+
+	for i := 0; i < 20; i++ {
+		RegisterGameMode(testGameMode, testAddress)
+		RemoveGameMode(testGameMode)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	close(stop)
+	wg.Wait()
+}
+
+func TestSetWebhooksInvalidURL(t *testing.T) {
+	err := SetWebhooks([]string{"not a url"}, "")
+	if err == nil {
+		t.Fatalf(`SetWebhooks(InvalidURL) = nil, want error`)
+	}
+}
+
+func setupEventTest(t *testing.T) chan RegistryEvent {
+	events := SubscribeToRegistry()
+
+	t.Cleanup(cleanUpAfterTest)
+	t.Cleanup(func() {
+		UnsubscribeFromRegistry(events)
+	})
+
+	return events
+}
+
+func confirmRegistryEvent(t *testing.T, events chan RegistryEvent, eventType string) RegistryEvent {
+	select {
+	case event := <-events:
+		if event.Type != eventType {
+			t.Fatalf(`RegistryEvent = %q, want %q`, event.Type, eventType)
+		}
+
+		return event
+	case <-time.After(time.Second):
+		t.Fatalf(`RegistryEvent not received, want %q`, eventType)
+	}
+
+	return RegistryEvent{}
+}
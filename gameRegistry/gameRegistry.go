@@ -2,95 +2,615 @@ package gameRegistry
 
 import (
 	"fmt"
+	"net/http"
+	"sort"
+	"sync"
 	"time"
 
+	"golang.org/x/exp/maps"
+
+	"Engee-Server/metrics"
 	sErr "Engee-Server/stockErrors"
 	"Engee-Server/utils"
 )
 
-var urlRegistry = make(map[string]string)
+const DefaultVersion = "0.0.0"
+const DefaultProtocol = 1
+
+const TransportHTTP = "http"
+const TransportGRPC = "grpc"
+
+const HealthHealthy = "healthy"
+const HealthUnhealthy = "unhealthy"
+
+const healthCheckPeriod = 3 * time.Second
+const staleThreshold = 6 * time.Second
+
+type GameMode struct {
+	Name      string            `json:"name"`
+	Version   string            `json:"version"`
+	Protocol  int               `json:"protocol"`
+	Transport string            `json:"transport"`
+	URL       string            `json:"url,omitempty"`
+	BootID    string            `json:"boot_id"`
+	Health    string            `json:"health"`
+	Static    bool              `json:"static"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+}
+
+// Public returns the game mode as clients see it, without the address of the
+// instance serving it.
+func (mode GameMode) Public() GameMode {
+	mode.URL = ""
+	return mode
+}
+
+var lock sync.Mutex
+var instances = make(map[string]GameMode)
 var heartbeats map[string]time.Time
+var secrets = make(map[string]string)
+var restartHandler func(mode GameMode)
+
+var heartbeatExpiries = metrics.NewCounter(
+	"engee_gamemode_heartbeat_expiries_total",
+	"Game mode instances removed after missing their heartbeat.",
+	"gamemode",
+)
 
 func RegisterGameMode(name string, url string) error {
-	if name == "" {
+	return RegisterGameModeInstance(GameMode{
+		Name: name,
+		URL:  url,
+	})
+}
+
+func RegisterGameModeInstance(mode GameMode) error {
+	mode.Static = false
+
+	return registerInstance(mode)
+}
+
+func registerInstance(mode GameMode) error {
+	if mode.Name == "" {
 		return &sErr.EmptyValueError{
 			Field: "Gamemode name",
 		}
 	}
 
-	err := utils.ValidateURL(url)
+	err := utils.ValidateURL(mode.URL)
 	if err != nil {
 		return fmt.Errorf("URL is invalid: %w", err)
 	}
 
-	_, found := urlRegistry[name]
+	if mode.Version == "" {
+		mode.Version = DefaultVersion
+	}
+
+	version, err := utils.ParseSemVer(mode.Version)
+	if err != nil {
+		return fmt.Errorf("version is invalid: %w", err)
+	}
+
+	mode.Version = version.String()
+
+	if mode.Protocol == 0 {
+		mode.Protocol = DefaultProtocol
+	}
+
+	if mode.Transport == "" {
+		mode.Transport = TransportHTTP
+	}
+
+	if mode.Transport != TransportHTTP && mode.Transport != TransportGRPC {
+		return &sErr.InvalidValueError[string]{
+			Field: "Transport",
+			Value: mode.Transport,
+		}
+	}
+
+	key := instanceKey(mode.Name, mode.Version, mode.URL)
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	existing, found := instances[key]
+	if found && isRestart(existing, mode.BootID) {
+		recordRestart(key, existing, mode.BootID)
+		return nil
+	}
+
 	if found {
 		return &sErr.MatchFoundError[string]{
 			Space: "Gamemodes",
-			Field: "Name",
-			Value: name,
+			Field: "Instance",
+			Value: key,
 		}
 	}
 
-	urlRegistry[name] = url
+	mode.Health = HealthHealthy
+	instances[key] = mode
 
-	if heartbeats == nil {
-		heartbeats = make(map[string]time.Time)
-		go utils.MonitorHeartbeats(&heartbeats, RemoveGameMode)
+	beat(key)
+
+	publishEvent(EventRegistered, mode)
+
+	return nil
+}
+
+// Heartbeat keeps exactly one instance alive. The URL is required, and the
+// version must be given when one server hosts several versions of the mode.
+func Heartbeat(name string, version string, url string, bootID string) error {
+	if url == "" {
+		return &sErr.EmptyValueError{
+			Field: "URL",
+		}
 	}
 
-	heartbeats[name] = time.Now()
+	lock.Lock()
+	defer lock.Unlock()
+
+	matches, err := findInstances(name, version, url)
+	if err != nil {
+		return err
+	}
+
+	if len(matches) > 1 {
+		return &sErr.InvalidValueError[string]{
+			Field: "Heartbeat instance",
+			Value: name,
+		}
+	}
+
+	for key, mode := range matches {
+		if isRestart(mode, bootID) {
+			recordRestart(key, mode, bootID)
+			continue
+		}
+
+		if mode.BootID == "" && bootID != "" {
+			mode.BootID = bootID
+			instances[key] = mode
+		}
+
+		beat(key)
+		updateHealth(key, HealthHealthy)
+	}
 
 	return nil
 }
 
-func Heartbeat(name string) error {
-	_, err := GetGamemodeURL(name)
+func SetRestartHandler(handler func(mode GameMode)) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	restartHandler = handler
+}
+
+func SetGameModeHealth(name string, version string, url string, health string) error {
+	if health != HealthHealthy && health != HealthUnhealthy {
+		return &sErr.InvalidValueError[string]{
+			Field: "Health",
+			Value: health,
+		}
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	matches, err := findInstances(name, version, url)
 	if err != nil {
 		return err
 	}
 
-	heartbeats[name] = time.Now()
+	for key := range matches {
+		updateHealth(key, health)
+	}
 
 	return nil
 }
 
 func RemoveGameMode(name string) error {
-	_, err := GetGamemodeURL(name)
+	return RemoveGameModeInstance(name, "", "")
+}
+
+func RemoveGameModeInstance(name string, version string, url string) error {
+	lock.Lock()
+	defer lock.Unlock()
+
+	matches, err := findInstances(name, version, url)
 	if err != nil {
 		return err
 	}
 
-	delete(urlRegistry, name)
-	delete(heartbeats, name)
+	for key := range matches {
+		removeInstance(key)
+	}
 
 	return nil
 }
 
 func GetGameModes() []string {
+	lock.Lock()
+	defer lock.Unlock()
+
 	var gameModes []string
-	for name := range urlRegistry {
-		gameModes = append(gameModes, name)
+	found := make(map[string]bool)
+	for _, mode := range instances {
+		if !found[mode.Name] {
+			found[mode.Name] = true
+			gameModes = append(gameModes, mode.Name)
+		}
 	}
 
 	return gameModes
 }
 
+func CountGameModeInstances() int {
+	lock.Lock()
+	defer lock.Unlock()
+
+	return len(instances)
+}
+
+func GetGameModeVersions(name string) ([]GameMode, error) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	return gameModeVersions(name)
+}
+
+func gameModeVersions(name string) ([]GameMode, error) {
+	matches, err := findInstances(name, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]GameMode, 0, len(matches))
+	for _, mode := range matches {
+		versions = append(versions, mode)
+	}
+
+	sortByVersion(versions)
+
+	return versions, nil
+}
+
+func GetGameModeInstance(name string, version string, url string) (GameMode, error) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	matches, err := findInstances(name, version, url)
+	if err != nil {
+		return GameMode{}, err
+	}
+
+	modes := maps.Values(matches)
+	sortByVersion(modes)
+
+	return modes[0], nil
+}
+
 func GetGamemodeURL(name string) (string, error) {
+	mode, err := ResolveGameMode(name, "")
+	if err != nil {
+		return "", err
+	}
+
+	return mode.URL, nil
+}
+
+func GetGameModeTransport(name string, url string) string {
+	lock.Lock()
+	defer lock.Unlock()
+
+	matches, err := findInstances(name, "", url)
+	if err != nil {
+		return TransportHTTP
+	}
+
+	for _, mode := range matches {
+		return mode.Transport
+	}
+
+	return TransportHTTP
+}
+
+func ResolveGameMode(name string, version string) (GameMode, error) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	versions, err := gameModeVersions(name)
+	if err != nil {
+		return GameMode{}, err
+	}
+
+	if version == "" {
+		return versions[0], nil
+	}
+
+	requested, err := utils.ParseSemVer(version)
+	if err != nil {
+		return GameMode{}, fmt.Errorf("requested version is invalid: %w", err)
+	}
+
+	for _, mode := range versions {
+		if mode.Version == requested.String() {
+			return mode, nil
+		}
+	}
+
+	for _, mode := range versions {
+		available, _ := utils.ParseSemVer(mode.Version)
+		if available.Major == requested.Major && available.Compare(requested) >= 0 {
+			return mode, nil
+		}
+	}
+
+	return GameMode{}, &sErr.MatchNotFoundError[string]{
+		Space: "Gamemode " + name,
+		Field: "Version",
+		Value: version,
+	}
+}
+
+func ResolveMigrationTarget(current GameMode, url string) (GameMode, error) {
+	if url != "" && url == current.URL {
+		return GameMode{}, &sErr.InvalidValueError[string]{
+			Field: "Migration URL",
+			Value: url,
+		}
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	versions, err := gameModeVersions(current.Name)
+	if err != nil {
+		return GameMode{}, err
+	}
+
+	candidates := make([]GameMode, 0)
+	for _, mode := range versions {
+		if mode.URL == current.URL || mode.Health != HealthHealthy {
+			continue
+		}
+
+		if url != "" && mode.URL != url {
+			continue
+		}
+
+		if CheckCompatibility(current, mode) != nil {
+			continue
+		}
+
+		candidates = append(candidates, mode)
+	}
+
+	if len(candidates) == 0 {
+		return GameMode{}, &sErr.UnavailableError{
+			Space:  "Gamemode " + current.Name,
+			Reason: "no other healthy compatible instance to migrate to",
+		}
+	}
+
+	for _, mode := range candidates {
+		if mode.Version == current.Version {
+			return mode, nil
+		}
+	}
+
+	return candidates[0], nil
+}
+
+func CheckCompatibility(current GameMode, requested GameMode) error {
+	incompatible := &sErr.IncompatibleVersionError{
+		Current:   current.Name + "@" + current.Version,
+		Requested: requested.Name + "@" + requested.Version,
+	}
+
+	if current.Name != requested.Name || current.Protocol != requested.Protocol {
+		return incompatible
+	}
+
+	currentVersion, err := utils.ParseSemVer(current.Version)
+	if err != nil {
+		return fmt.Errorf("current version is invalid: %w", err)
+	}
+
+	requestedVersion, err := utils.ParseSemVer(requested.Version)
+	if err != nil {
+		return fmt.Errorf("requested version is invalid: %w", err)
+	}
+
+	if currentVersion.Major != requestedVersion.Major {
+		return incompatible
+	}
+
+	return nil
+}
+
+func SetGameModeSecrets(modeSecrets map[string]string) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	secrets = make(map[string]string)
+	for name, secret := range modeSecrets {
+		secrets[name] = secret
+	}
+}
+
+func GetGameModeSecret(name string) string {
+	lock.Lock()
+	defer lock.Unlock()
+
+	return secrets[name]
+}
+
+func VerifyGameModeRequest(name string, request *http.Request, body []byte) error {
 	if name == "" {
-		return "", &sErr.EmptyValueError{
+		return &sErr.EmptyValueError{
 			Field: "Name",
 		}
 	}
 
-	url, found := urlRegistry[name]
-	if !found {
-		return "", &sErr.MatchNotFoundError[string]{
+	err := utils.VerifyRequest(request, GetGameModeSecret(name), body)
+	if err != nil {
+		return fmt.Errorf("could not verify request for gamemode %q: %w", name, err)
+	}
+
+	return nil
+}
+
+func findInstances(name string, version string, url string) (map[string]GameMode, error) {
+	if name == "" {
+		return nil, &sErr.EmptyValueError{
+			Field: "Name",
+		}
+	}
+
+	if version != "" {
+		parsed, err := utils.ParseSemVer(version)
+		if err != nil {
+			return nil, fmt.Errorf("version is invalid: %w", err)
+		}
+
+		version = parsed.String()
+	}
+
+	matches := make(map[string]GameMode)
+	for key, mode := range instances {
+		if mode.Name != name {
+			continue
+		}
+
+		if version != "" && mode.Version != version {
+			continue
+		}
+
+		if url != "" && mode.URL != url {
+			continue
+		}
+
+		matches[key] = mode
+	}
+
+	if len(matches) == 0 {
+		return nil, &sErr.MatchNotFoundError[string]{
 			Space: "Gamemodes",
 			Field: "Name",
 			Value: name,
 		}
 	}
 
-	return url, nil
+	return matches, nil
+}
+
+func isRestart(mode GameMode, bootID string) bool {
+	return bootID != "" && mode.BootID != "" && bootID != mode.BootID
+}
+
+func recordRestart(key string, mode GameMode, bootID string) {
+	mode.BootID = bootID
+	mode.Health = HealthHealthy
+	instances[key] = mode
+	beat(key)
+
+	publishEvent(EventRestarted, mode)
+
+	if restartHandler != nil {
+		go restartHandler(mode)
+	}
+}
+
+func beat(key string) {
+	if instances[key].Static {
+		return
+	}
+
+	if heartbeats == nil {
+		heartbeats = make(map[string]time.Time)
+		go utils.MonitorHeartbeats(&heartbeats, &lock, expireInstance)
+		go monitorHealth()
+	}
+
+	heartbeats[key] = time.Now()
+}
+
+func updateHealth(key string, health string) {
+	mode, found := instances[key]
+	if !found || mode.Health == health {
+		return
+	}
+
+	mode.Health = health
+	instances[key] = mode
+
+	publishEvent(EventHealthChanged, mode)
+}
+
+func monitorHealth() {
+	for {
+		now := time.Now()
+
+		lock.Lock()
+		for key, lastBeat := range heartbeats {
+			if now.Sub(lastBeat) > staleThreshold {
+				updateHealth(key, HealthUnhealthy)
+			}
+		}
+		lock.Unlock()
+
+		if !utils.WaitForNextCheck(healthCheckPeriod) {
+			return
+		}
+	}
+}
+
+func expireInstance(key string) error {
+	lock.Lock()
+	defer lock.Unlock()
+
+	mode, found := instances[key]
+	if found {
+		publishEvent(EventHeartbeatLost, mode)
+		heartbeatExpiries.Inc(mode.Name)
+	}
+
+	return removeInstance(key)
+}
+
+func removeInstance(key string) error {
+	mode, found := instances[key]
+	if !found {
+		return &sErr.MatchNotFoundError[string]{
+			Space: "Gamemodes",
+			Field: "Instance",
+			Value: key,
+		}
+	}
+
+	delete(instances, key)
+	delete(heartbeats, key)
+
+	publishEvent(EventRemoved, mode)
+
+	return nil
+}
+
+func sortByVersion(modes []GameMode) {
+	sort.SliceStable(modes, func(i, j int) bool {
+		first, _ := utils.ParseSemVer(modes[i].Version)
+		second, _ := utils.ParseSemVer(modes[j].Version)
+
+		comparison := first.Compare(second)
+		if comparison == 0 {
+			return modes[i].URL < modes[j].URL
+		}
+
+		return comparison > 0
+	})
+}
+
+func instanceKey(name string, version string, url string) string {
+	return name + "@" + version + "@" + url
 }
@@ -2,17 +2,27 @@ package gameRegistry
 
 import (
 	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	sErr "Engee-Server/stockErrors"
+	"Engee-Server/utils"
 )
 
 const testAddress = "http://localhost:8091"
+const altAddress = "http://localhost:8092"
 
 const testGameMode = "Test"
 const altGameMode = "Alt"
 const badGameMode = "Invalid"
 
+const testSecret = "test-secret"
+const testBody = `{"first":"Test","second":"http://localhost:8091"}`
+
 func TestRegisterGame(t *testing.T) {
 	err := RegisterGameMode(testGameMode, testAddress)
 	if err != nil {
@@ -87,6 +97,389 @@ func TestRemoveGameFromRegistryDouble(t *testing.T) {
 	}
 }
 
+func TestRegisterGameMultipleVersions(t *testing.T) {
+	RegisterGameModeInstance(GameMode{Name: testGameMode, Version: "1.0.0", URL: testAddress})
+
+	err := RegisterGameModeInstance(GameMode{Name: testGameMode, Version: "1.1.0", URL: testAddress})
+	if err != nil {
+		t.Fatalf(`TestRegisterGame(Multiple Versions) = %v, want nil`, err)
+	}
+
+	t.Cleanup(cleanUpAfterTest)
+}
+
+func TestRegisterGameSameVersion(t *testing.T) {
+	RegisterGameModeInstance(GameMode{Name: testGameMode, Version: "1.0.0", URL: testAddress})
+
+	err := RegisterGameModeInstance(GameMode{Name: testGameMode, Version: "1.0", URL: testAddress})
+	if !errors.As(err, &sErr.MF_ERR) {
+		t.Fatalf(`TestRegisterGame(Same Version) = %v, want MatchFoundError`, err)
+	}
+
+	t.Cleanup(cleanUpAfterTest)
+}
+
+func TestRegisterGameInvalidVersion(t *testing.T) {
+	err := RegisterGameModeInstance(GameMode{Name: testGameMode, Version: "one", URL: testAddress})
+	if !errors.As(err, &sErr.IV_ERR) {
+		t.Fatalf(`TestRegisterGame(Invalid Version) = %v, want InvalidValueError`, err)
+	}
+
+	t.Cleanup(cleanUpAfterTest)
+}
+
+func TestResolveGameModeLatest(t *testing.T) {
+	setupVersionTest(t)
+
+	mode, err := ResolveGameMode(testGameMode, "")
+	if mode.Version != "2.0.0" || err != nil {
+		t.Fatalf(`ResolveGameMode(Latest) = %v, %v, want 2.0.0, nil`, mode, err)
+	}
+}
+
+func TestResolveGameModeExact(t *testing.T) {
+	setupVersionTest(t)
+
+	mode, err := ResolveGameMode(testGameMode, "1.0.0")
+	if mode.Version != "1.0.0" || err != nil {
+		t.Fatalf(`ResolveGameMode(Exact) = %v, %v, want 1.0.0, nil`, mode, err)
+	}
+}
+
+func TestResolveGameModeCompatible(t *testing.T) {
+	setupVersionTest(t)
+
+	mode, err := ResolveGameMode(testGameMode, "1.1.0")
+	if mode.Version != "1.2.0" || err != nil {
+		t.Fatalf(`ResolveGameMode(Compatible) = %v, %v, want 1.2.0, nil`, mode, err)
+	}
+}
+
+func TestResolveGameModeNoCompatible(t *testing.T) {
+	setupVersionTest(t)
+
+	_, err := ResolveGameMode(testGameMode, "3.0.0")
+	if !errors.As(err, &sErr.MNF_ERR) {
+		t.Fatalf(`ResolveGameMode(No Compatible) = %v, want MatchNotFoundError`, err)
+	}
+}
+
+func TestCheckCompatibility(t *testing.T) {
+	current := GameMode{Name: testGameMode, Version: "1.0.0", Protocol: 1}
+	requested := GameMode{Name: testGameMode, Version: "1.4.2", Protocol: 1}
+
+	err := CheckCompatibility(current, requested)
+	if err != nil {
+		t.Fatalf(`CheckCompatibility(Valid) = %v, want nil`, err)
+	}
+}
+
+func TestCheckCompatibilityMajor(t *testing.T) {
+	current := GameMode{Name: testGameMode, Version: "1.0.0", Protocol: 1}
+	requested := GameMode{Name: testGameMode, Version: "2.0.0", Protocol: 1}
+
+	err := CheckCompatibility(current, requested)
+	if !errors.As(err, &sErr.IC_ERR) {
+		t.Fatalf(`CheckCompatibility(Major) = %v, want IncompatibleVersionError`, err)
+	}
+}
+
+func TestCheckCompatibilityProtocol(t *testing.T) {
+	current := GameMode{Name: testGameMode, Version: "1.0.0", Protocol: 1}
+	requested := GameMode{Name: testGameMode, Version: "1.1.0", Protocol: 2}
+
+	err := CheckCompatibility(current, requested)
+	if !errors.As(err, &sErr.IC_ERR) {
+		t.Fatalf(`CheckCompatibility(Protocol) = %v, want IncompatibleVersionError`, err)
+	}
+}
+
+func TestResolveMigrationTarget(t *testing.T) {
+	current := setupMigrationTest(t)
+
+	target, err := ResolveMigrationTarget(current, "")
+	if target.URL != altAddress || target.Version != "1.0.0" || err != nil {
+		t.Fatalf(`ResolveMigrationTarget(Valid) = %v, %v, want 1.0.0 at %s, nil`, target, err, altAddress)
+	}
+}
+
+func TestResolveMigrationTargetUnhealthy(t *testing.T) {
+	current := setupMigrationTest(t)
+	SetGameModeHealth(testGameMode, "", altAddress, HealthUnhealthy)
+
+	_, err := ResolveMigrationTarget(current, altAddress)
+	if !errors.As(err, &sErr.UA_ERR) {
+		t.Fatalf(`ResolveMigrationTarget(Unhealthy) = %v, want UnavailableError`, err)
+	}
+}
+
+func TestResolveMigrationTargetSameURL(t *testing.T) {
+	current := setupMigrationTest(t)
+
+	_, err := ResolveMigrationTarget(current, testAddress)
+	if !errors.As(err, &sErr.IV_ERR) {
+		t.Fatalf(`ResolveMigrationTarget(SameURL) = %v, want InvalidValueError`, err)
+	}
+}
+
+func TestRegisterGameTransport(t *testing.T) {
+	err := RegisterGameModeInstance(GameMode{Name: testGameMode, URL: testAddress, Transport: TransportGRPC})
+	if err != nil {
+		t.Fatalf(`TestRegisterGame(Transport) = %v, want nil`, err)
+	}
+
+	t.Cleanup(cleanUpAfterTest)
+
+	transport := GetGameModeTransport(testGameMode, testAddress)
+	if transport != TransportGRPC {
+		t.Fatalf(`GetGameModeTransport(Registered) = %q, want %q`, transport, TransportGRPC)
+	}
+
+	transport = GetGameModeTransport(badGameMode, testAddress)
+	if transport != TransportHTTP {
+		t.Fatalf(`GetGameModeTransport(Unknown) = %q, want %q`, transport, TransportHTTP)
+	}
+}
+
+func TestRegisterGameInvalidTransport(t *testing.T) {
+	err := RegisterGameModeInstance(GameMode{Name: testGameMode, URL: testAddress, Transport: "carrier-pigeon"})
+	if !errors.As(err, &sErr.IV_ERR) {
+		t.Fatalf(`TestRegisterGame(InvalidTransport) = %v, want InvalidValueError`, err)
+	}
+
+	t.Cleanup(cleanUpAfterTest)
+}
+
+func TestRemoveGameModeInstance(t *testing.T) {
+	setupVersionTest(t)
+
+	err := RemoveGameModeInstance(testGameMode, "2.0.0", "")
+	if err != nil {
+		t.Fatalf(`RemoveGameModeInstance(Valid) = %v, want nil`, err)
+	}
+
+	mode, err := ResolveGameMode(testGameMode, "")
+	if mode.Version != "1.2.0" || err != nil {
+		t.Fatalf(`ResolveGameMode(After Remove) = %v, %v, want 1.2.0, nil`, mode, err)
+	}
+}
+
+func TestRegisterGameRestart(t *testing.T) {
+	restarted := setupRestartTest(t)
+
+	err := RegisterGameModeInstance(GameMode{Name: testGameMode, URL: testAddress, BootID: "second"})
+	if err != nil {
+		t.Fatalf(`TestRegisterGame(Restart) = %v, want nil`, err)
+	}
+
+	confirmRestart(t, restarted, "second")
+}
+
+func TestRegisterGameSameBoot(t *testing.T) {
+	setupRestartTest(t)
+
+	err := RegisterGameModeInstance(GameMode{Name: testGameMode, URL: testAddress, BootID: "first"})
+	if !errors.As(err, &sErr.MF_ERR) {
+		t.Fatalf(`TestRegisterGame(Same Boot) = %v, want MatchFoundError`, err)
+	}
+}
+
+func TestHeartbeatRestart(t *testing.T) {
+	restarted := setupRestartTest(t)
+
+	err := Heartbeat(testGameMode, "", testAddress, "second")
+	if err != nil {
+		t.Fatalf(`Heartbeat(Restart) = %v, want nil`, err)
+	}
+
+	confirmRestart(t, restarted, "second")
+}
+
+func TestHeartbeatSameBoot(t *testing.T) {
+	restarted := setupRestartTest(t)
+
+	err := Heartbeat(testGameMode, "", testAddress, "first")
+	if err != nil {
+		t.Fatalf(`Heartbeat(Same Boot) = %v, want nil`, err)
+	}
+
+	select {
+	case mode := <-restarted:
+		t.Fatalf(`Heartbeat(Same Boot) restarted %v, want no restart`, mode)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestHeartbeatBootAmbiguous(t *testing.T) {
+	setupRestartTest(t)
+	RegisterGameModeInstance(GameMode{Name: testGameMode, Version: "1.0.0", URL: testAddress, BootID: "other"})
+
+	err := Heartbeat(testGameMode, "", testAddress, "second")
+	if !errors.As(err, &sErr.IV_ERR) {
+		t.Fatalf(`Heartbeat(Ambiguous Boot) = %v, want InvalidValueError`, err)
+	}
+}
+
+func TestHeartbeatAmbiguous(t *testing.T) {
+	RegisterGameMode(testGameMode, testAddress)
+	RegisterGameModeInstance(GameMode{Name: testGameMode, Version: "1.0.0", URL: testAddress})
+	t.Cleanup(cleanUpAfterTest)
+
+	err := Heartbeat(testGameMode, "", testAddress, "")
+	if !errors.As(err, &sErr.IV_ERR) {
+		t.Fatalf(`Heartbeat(Ambiguous) = %v, want InvalidValueError`, err)
+	}
+
+	err = Heartbeat(testGameMode, "1.0.0", testAddress, "")
+	if err != nil {
+		t.Fatalf(`Heartbeat(Versioned) = %v, want nil`, err)
+	}
+}
+
+func TestHeartbeatNoURL(t *testing.T) {
+	RegisterGameMode(testGameMode, testAddress)
+	t.Cleanup(cleanUpAfterTest)
+
+	err := Heartbeat(testGameMode, "", "", "")
+	if !errors.As(err, &sErr.EV_ERR) {
+		t.Fatalf(`Heartbeat(NoURL) = %v, want EmptyValueError`, err)
+	}
+}
+
+func TestVerifyGameModeRequest(t *testing.T) {
+	setupSecretTest(t)
+
+	request := newSignedRequest(testSecret, testBody)
+
+	err := VerifyGameModeRequest(testGameMode, request, []byte(testBody))
+	if err != nil {
+		t.Fatalf(`VerifyGameModeRequest(Valid) = %v, want nil`, err)
+	}
+}
+
+func TestVerifyGameModeRequestUnsigned(t *testing.T) {
+	setupSecretTest(t)
+
+	request := httptest.NewRequest(http.MethodPost, "/gameModes", strings.NewReader(testBody))
+
+	err := VerifyGameModeRequest(testGameMode, request, []byte(testBody))
+	if !errors.As(err, &sErr.AU_ERR) {
+		t.Fatalf(`VerifyGameModeRequest(Unsigned) = %v, want AuthorizationError`, err)
+	}
+}
+
+func TestVerifyGameModeRequestWrongSecret(t *testing.T) {
+	setupSecretTest(t)
+
+	request := newSignedRequest("wrong-secret", testBody)
+
+	err := VerifyGameModeRequest(testGameMode, request, []byte(testBody))
+	if !errors.As(err, &sErr.AU_ERR) {
+		t.Fatalf(`VerifyGameModeRequest(WrongSecret) = %v, want AuthorizationError`, err)
+	}
+}
+
+func TestVerifyGameModeRequestTamperedBody(t *testing.T) {
+	setupSecretTest(t)
+
+	request := newSignedRequest(testSecret, testBody)
+
+	err := VerifyGameModeRequest(testGameMode, request, []byte(`{"first":"Test","second":"http://evil:80"}`))
+	if !errors.As(err, &sErr.AU_ERR) {
+		t.Fatalf(`VerifyGameModeRequest(TamperedBody) = %v, want AuthorizationError`, err)
+	}
+}
+
+func TestVerifyGameModeRequestExpired(t *testing.T) {
+	setupSecretTest(t)
+
+	request := newSignedRequest(testSecret, testBody)
+	timestamp := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+	request.Header.Set(utils.TimestampHeader, timestamp)
+	request.Header.Set(utils.SignatureHeader, utils.ComputeSignature(testSecret, request.Method, request.URL.RequestURI(), timestamp, []byte(testBody)))
+
+	err := VerifyGameModeRequest(testGameMode, request, []byte(testBody))
+	if !errors.As(err, &sErr.AU_ERR) {
+		t.Fatalf(`VerifyGameModeRequest(Expired) = %v, want AuthorizationError`, err)
+	}
+}
+
+func TestVerifyGameModeRequestNoSecret(t *testing.T) {
+	setupSecretTest(t)
+
+	request := newSignedRequest(testSecret, testBody)
+
+	err := VerifyGameModeRequest(altGameMode, request, []byte(testBody))
+	if !errors.As(err, &sErr.AU_ERR) {
+		t.Fatalf(`VerifyGameModeRequest(NoSecret) = %v, want AuthorizationError`, err)
+	}
+}
+
+func setupSecretTest(t *testing.T) {
+	SetGameModeSecrets(map[string]string{
+		testGameMode: testSecret,
+	})
+
+	t.Cleanup(func() {
+		SetGameModeSecrets(nil)
+	})
+}
+
+func newSignedRequest(secret string, body string) *http.Request {
+	request := httptest.NewRequest(http.MethodPost, "/gameModes", strings.NewReader(body))
+	utils.SignRequest(request, secret, []byte(body))
+
+	return request
+}
+
+func setupRestartTest(t *testing.T) chan GameMode {
+	restarted := make(chan GameMode, 1)
+	SetRestartHandler(func(mode GameMode) {
+		restarted <- mode
+	})
+
+	RegisterGameModeInstance(GameMode{Name: testGameMode, URL: testAddress, BootID: "first"})
+
+	t.Cleanup(func() {
+		SetRestartHandler(nil)
+	})
+	t.Cleanup(cleanUpAfterTest)
+
+	return restarted
+}
+
+func confirmRestart(t *testing.T, restarted chan GameMode, bootID string) {
+	select {
+	case mode := <-restarted:
+		if mode.BootID != bootID {
+			t.Fatalf(`Restart handler got boot ID %q, want %q`, mode.BootID, bootID)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf(`Restart handler was not called`)
+	}
+}
+
+func setupVersionTest(t *testing.T) {
+	for _, version := range []string{"1.0.0", "1.2.0", "2.0.0"} {
+		RegisterGameModeInstance(GameMode{Name: testGameMode, Version: version, URL: testAddress})
+	}
+
+	t.Cleanup(cleanUpAfterTest)
+}
+
+func setupMigrationTest(t *testing.T) GameMode {
+	RegisterGameModeInstance(GameMode{Name: testGameMode, Version: "1.0.0", URL: testAddress})
+	RegisterGameModeInstance(GameMode{Name: testGameMode, Version: "1.0.0", URL: altAddress})
+	RegisterGameModeInstance(GameMode{Name: testGameMode, Version: "2.0.0", URL: altAddress})
+
+	t.Cleanup(cleanUpAfterTest)
+
+	current, _ := ResolveGameMode(testGameMode, "1.0.0")
+	current.URL = testAddress
+
+	return current
+}
+
 func setupRegisterTest(t *testing.T) {
 	RegisterGameMode(testGameMode, testAddress)
 	RegisterGameMode(altGameMode, testAddress)
@@ -95,5 +488,8 @@ func setupRegisterTest(t *testing.T) {
 }
 
 func cleanUpAfterTest() {
-	urlRegistry = make(map[string]string)
+	lock.Lock()
+	defer lock.Unlock()
+
+	instances = make(map[string]GameMode)
 }
@@ -0,0 +1,137 @@
+package lobby
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"Engee-Server/room"
+	sErr "Engee-Server/stockErrors"
+	"Engee-Server/tracing"
+)
+
+const GameEventMatchEnded = "match_ended"
+const GameEventPlayerEliminated = "player_eliminated"
+const GameEventPlayerLeft = "player_left"
+const GameEventScoresUpdated = "scores_updated"
+const GameEventStatusChanged = "status_changed"
+
+type GameEvent struct {
+	Kind    string         `json:"kind"`
+	UID     string         `json:"uid,omitempty"`
+	Status  string         `json:"status,omitempty"`
+	Scores  map[string]int `json:"scores,omitempty"`
+	Winners []string       `json:"winners,omitempty"`
+	Message string         `json:"message,omitempty"`
+}
+
+func HandleGameEvent(ctx context.Context, rid string, eventInfo []byte) error {
+	ctx, span := tracing.Start(ctx, "lobby.HandleGameEvent")
+	defer span.End()
+	span.SetAttribute("rid", rid)
+
+	var event GameEvent
+	err := json.Unmarshal(eventInfo, &event)
+	if err != nil {
+		return fmt.Errorf("could not unmarshal game event: %w", err)
+	}
+
+	if event.Kind == "" {
+		return &sErr.EmptyValueError{
+			Field: "Kind",
+		}
+	}
+
+	_, err = room.GetRoom(rid)
+	if err != nil {
+		return fmt.Errorf("could not find room: %w", err)
+	}
+
+	var data any = event
+	switch event.Kind {
+	case GameEventMatchEnded:
+		data, err = endMatch(rid, event)
+	case GameEventPlayerEliminated:
+		data, err = eliminatePlayer(rid, event)
+	case GameEventPlayerLeft:
+		err = removeLeavingPlayer(ctx, rid, event)
+	case GameEventScoresUpdated:
+		data, err = room.UpdateRoomScores(rid, event.Scores)
+	case GameEventStatusChanged:
+		err = room.UpdateRoomStatus(rid, event.Status)
+	default:
+		return &sErr.InvalidValueError[string]{
+			Field: "Kind",
+			Value: event.Kind,
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("could not apply %s event: %w", event.Kind, err)
+	}
+
+	_, err = room.GetRoom(rid)
+	if err != nil {
+		return nil
+	}
+
+	room.InvalidateRoomGameState(rid)
+
+	room.PublishRoomEvent(room.RoomEvent{
+		RID:     rid,
+		Type:    event.Kind,
+		Message: event.Message,
+		Data:    data,
+	})
+
+	return nil
+}
+
+func endMatch(rid string, event GameEvent) (room.RoomResults, error) {
+	if len(event.Scores) > 0 {
+		_, err := room.UpdateRoomScores(rid, event.Scores)
+		if err != nil {
+			return room.RoomResults{}, err
+		}
+	}
+
+	results, err := room.SetRoomWinners(rid, event.Winners)
+	if err != nil {
+		return room.RoomResults{}, err
+	}
+
+	err = room.UpdateRoomStatus(rid, room.StatusEnded)
+	if err != nil {
+		return room.RoomResults{}, err
+	}
+
+	return results, nil
+}
+
+func eliminatePlayer(rid string, event GameEvent) (room.RoomResults, error) {
+	if event.UID == "" {
+		return room.RoomResults{}, &sErr.EmptyValueError{
+			Field: "UID",
+		}
+	}
+
+	if !checkRoomContainsUser(event.UID, rid) {
+		return room.RoomResults{}, &sErr.MatchNotFoundError[string]{
+			Space: "Room Users",
+			Field: "UID",
+			Value: event.UID,
+		}
+	}
+
+	return room.EliminatePlayer(rid, event.UID)
+}
+
+func removeLeavingPlayer(ctx context.Context, rid string, event GameEvent) error {
+	if event.UID == "" {
+		return &sErr.EmptyValueError{
+			Field: "UID",
+		}
+	}
+
+	return RemoveUserFromRoom(ctx, event.UID, rid)
+}
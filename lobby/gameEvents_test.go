@@ -0,0 +1,193 @@
+package lobby
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"Engee-Server/room"
+	sErr "Engee-Server/stockErrors"
+)
+
+func TestHandleGameEventMatchEnded(t *testing.T) {
+	uid, rid := setupLobbyTest(t)
+	events := subscribeToGameEvents(t, rid)
+
+	event := marshalGameEvent(t, GameEvent{
+		Kind:    GameEventMatchEnded,
+		Scores:  map[string]int{uid: 10},
+		Winners: []string{uid},
+	})
+
+	err := HandleGameEvent(context.Background(), rid, event)
+	if err != nil {
+		t.Fatalf(`HandleGameEvent(MatchEnded) = %v, want nil`, err)
+	}
+
+	gameRoom, _ := room.GetRoom(rid)
+	if gameRoom.Status != room.StatusEnded {
+		t.Fatalf(`GetRoom(MatchEnded).Status = %q, want %q`, gameRoom.Status, room.StatusEnded)
+	}
+
+	results, err := room.GetRoomResults(rid)
+	if results.Scores[uid] != 10 || len(results.Winners) != 1 || results.Winners[0] != uid || err != nil {
+		t.Fatalf(`GetRoomResults(MatchEnded) = %v, %v, want winner %q with 10, nil`, results, err, uid)
+	}
+
+	received := <-events
+	if received.Type != GameEventMatchEnded {
+		t.Fatalf(`RoomEvent(MatchEnded).Type = %q, want %q`, received.Type, GameEventMatchEnded)
+	}
+}
+
+func TestHandleGameEventPlayerEliminated(t *testing.T) {
+	uid, rid := setupLobbyTest(t)
+
+	event := marshalGameEvent(t, GameEvent{
+		Kind: GameEventPlayerEliminated,
+		UID:  uid,
+	})
+
+	err := HandleGameEvent(context.Background(), rid, event)
+	if err != nil {
+		t.Fatalf(`HandleGameEvent(PlayerEliminated) = %v, want nil`, err)
+	}
+
+	results, _ := room.GetRoomResults(rid)
+	if len(results.Eliminated) != 1 || results.Eliminated[0] != uid {
+		t.Fatalf(`GetRoomResults(PlayerEliminated).Eliminated = %v, want [%q]`, results.Eliminated, uid)
+	}
+
+	if !checkRoomContainsUser(uid, rid) {
+		t.Fatalf(`checkRoomContainsUser(PlayerEliminated) = false, want true`)
+	}
+}
+
+func TestHandleGameEventPlayerEliminatedNotInRoom(t *testing.T) {
+	_, rid := setupLobbyTest(t)
+
+	event := marshalGameEvent(t, GameEvent{
+		Kind: GameEventPlayerEliminated,
+		UID:  randomID,
+	})
+
+	err := HandleGameEvent(context.Background(), rid, event)
+	if !errors.As(err, &sErr.MNF_ERR) {
+		t.Fatalf(`HandleGameEvent(EliminatedNotInRoom) = %v, want MatchNotFoundError`, err)
+	}
+}
+
+func TestHandleGameEventPlayerLeft(t *testing.T) {
+	uid, rid := setupLobbyTest(t)
+	addMoreUsersToLobby(t, rid)
+
+	event := marshalGameEvent(t, GameEvent{
+		Kind: GameEventPlayerLeft,
+		UID:  uid,
+	})
+
+	err := HandleGameEvent(context.Background(), rid, event)
+	if err != nil {
+		t.Fatalf(`HandleGameEvent(PlayerLeft) = %v, want nil`, err)
+	}
+
+	if checkRoomContainsUser(uid, rid) {
+		t.Fatalf(`checkRoomContainsUser(PlayerLeft) = true, want false`)
+	}
+}
+
+func TestHandleGameEventScoresUpdated(t *testing.T) {
+	uid, rid := setupLobbyTest(t)
+
+	for _, score := range []int{5, 7} {
+		event := marshalGameEvent(t, GameEvent{
+			Kind:   GameEventScoresUpdated,
+			Scores: map[string]int{uid: score},
+		})
+
+		err := HandleGameEvent(context.Background(), rid, event)
+		if err != nil {
+			t.Fatalf(`HandleGameEvent(ScoresUpdated) = %v, want nil`, err)
+		}
+	}
+
+	results, _ := room.GetRoomResults(rid)
+	if results.Scores[uid] != 7 {
+		t.Fatalf(`GetRoomResults(ScoresUpdated).Scores = %v, want %q: 7`, results.Scores, uid)
+	}
+}
+
+func TestHandleGameEventStatusChanged(t *testing.T) {
+	_, rid := setupLobbyTest(t)
+
+	event := marshalGameEvent(t, GameEvent{
+		Kind:   GameEventStatusChanged,
+		Status: room.StatusRunning,
+	})
+
+	err := HandleGameEvent(context.Background(), rid, event)
+	if err != nil {
+		t.Fatalf(`HandleGameEvent(StatusChanged) = %v, want nil`, err)
+	}
+
+	gameRoom, _ := room.GetRoom(rid)
+	if gameRoom.Status != room.StatusRunning {
+		t.Fatalf(`GetRoom(StatusChanged).Status = %q, want %q`, gameRoom.Status, room.StatusRunning)
+	}
+}
+
+func TestHandleGameEventUnknownKind(t *testing.T) {
+	_, rid := setupLobbyTest(t)
+
+	event := marshalGameEvent(t, GameEvent{
+		Kind: "unknown",
+	})
+
+	err := HandleGameEvent(context.Background(), rid, event)
+	if !errors.As(err, &sErr.IV_ERR) {
+		t.Fatalf(`HandleGameEvent(UnknownKind) = %v, want InvalidValueError`, err)
+	}
+}
+
+func TestHandleGameEventEmptyKind(t *testing.T) {
+	_, rid := setupLobbyTest(t)
+
+	err := HandleGameEvent(context.Background(), rid, marshalGameEvent(t, GameEvent{}))
+	if !errors.As(err, &sErr.EV_ERR) {
+		t.Fatalf(`HandleGameEvent(EmptyKind) = %v, want EmptyValueError`, err)
+	}
+}
+
+func TestHandleGameEventInvalidRID(t *testing.T) {
+	event := marshalGameEvent(t, GameEvent{
+		Kind: GameEventScoresUpdated,
+	})
+
+	err := HandleGameEvent(context.Background(), randomID, event)
+	if !errors.As(err, &sErr.MNF_ERR) {
+		t.Fatalf(`HandleGameEvent(InvalidRID) = %v, want MatchNotFoundError`, err)
+	}
+}
+
+func subscribeToGameEvents(t *testing.T, rid string) chan room.RoomEvent {
+	events, err := room.SubscribeToRoom(rid)
+	if err != nil {
+		t.Fatalf("Could not subscribe to room: %v", err)
+	}
+
+	t.Cleanup(func() {
+		room.UnsubscribeFromRoom(rid, events)
+	})
+
+	return events
+}
+
+func marshalGameEvent(t *testing.T, event GameEvent) []byte {
+	eventInfo, err := json.Marshal(event)
+	if err != nil {
+		t.Fatalf("Could not marshal game event: %v", err)
+	}
+
+	return eventInfo
+}
@@ -1,41 +1,76 @@
 package lobby
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"sync"
 
+	"Engee-Server/gameClient/payload"
+	"Engee-Server/metrics"
 	"Engee-Server/room"
 	sErr "Engee-Server/stockErrors"
+	"Engee-Server/tracing"
 	"Engee-Server/user"
 	"Engee-Server/utils"
 )
 
+var lobbiesLock sync.Mutex
 var lobbies = make(map[string][]string)
 
+var roomJoins = metrics.NewCounter(
+	"engee_room_joins_total",
+	"Users that joined a room.",
+	"gamemode",
+)
+
+var roomLeaves = metrics.NewCounter(
+	"engee_room_leaves_total",
+	"Users that left a room.",
+	"gamemode",
+)
+
 func JoinUserToRoom(uid string, rid string) error {
 	err := checkUserAndRoomExist(uid, rid)
 	if err != nil {
 		return err
 	}
 
-	if checkRoomLobbyExists(rid) {
-		if checkRoomContainsUser(uid, rid) {
-			return &sErr.MatchFoundError[string]{
-				Space: "Room Users",
-				Field: "UID",
-				Value: uid,
-			}
+	joined, err := room.GetRoom(rid)
+	if err != nil {
+		return err
+	}
+
+	lobbiesLock.Lock()
+	defer lobbiesLock.Unlock()
+
+	if lobbyContainsUser(uid, rid) {
+		return &sErr.MatchFoundError[string]{
+			Space: "Room Users",
+			Field: "UID",
+			Value: uid,
+		}
+	}
+
+	if joined.MaxPlayers > 0 && len(lobbies[rid]) >= joined.MaxPlayers {
+		return &sErr.CapacityReachedError{
+			Space:    "Room " + rid,
+			Capacity: joined.MaxPlayers,
 		}
-	} else {
-		lobbies[rid] = make([]string, 0)
 	}
 
 	lobbies[rid] = append(lobbies[rid], uid)
+	roomJoins.Inc(joined.GameMode)
 
 	return nil
 }
 
-func RemoveUserFromRoom(uid string, rid string) error {
+func RemoveUserFromRoom(ctx context.Context, uid string, rid string) error {
+	ctx, span := tracing.Start(ctx, "lobby.RemoveUserFromRoom")
+	defer span.End()
+	span.SetAttribute("rid", rid)
+	span.SetAttribute("uid", uid)
+
 	err := checkUserAndRoomExist(uid, rid)
 	if err != nil {
 		return err
@@ -54,43 +89,73 @@ func RemoveUserFromRoom(uid string, rid string) error {
 		}
 	}
 
-	return removeUIDFromLobby(uid, rid)
+	return removeUIDFromLobby(ctx, uid, rid)
 }
 
-func RemoveUserFromAllRooms(uid string) error {
+func RemoveUserFromAllRooms(ctx context.Context, uid string) error {
+	ctx, span := tracing.Start(ctx, "lobby.RemoveUserFromAllRooms")
+	defer span.End()
+	span.SetAttribute("uid", uid)
+
 	_, err := user.GetUser(uid)
 	if err != nil {
 		return fmt.Errorf("could not get user: %w", err)
 	}
 
+	lobbiesLock.Lock()
+	joined := make([]string, 0)
 	for rid := range lobbies {
-		if checkRoomContainsUser(uid, rid) {
-			err = removeUIDFromLobby(uid, rid)
-			if err != nil {
-				return err
-			}
+		if lobbyContainsUser(uid, rid) {
+			joined = append(joined, rid)
+		}
+	}
+	lobbiesLock.Unlock()
+
+	for _, rid := range joined {
+		err = removeUIDFromLobby(ctx, uid, rid)
+		if err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
-func removeUIDFromLobby(uid string, rid string) error {
-	var err error = nil
-	lobbies[rid], err = utils.RemoveElementFromSliceOrdered(lobbies[rid], uid)
+// removeUIDFromLobby deletes the room once its last user has left, after
+// releasing the lobby lock so the game server call does not block other
+// lobbies.
+func removeUIDFromLobby(ctx context.Context, uid string, rid string) error {
+	lobbiesLock.Lock()
+	remaining, err := utils.RemoveElementFromSliceOrdered(lobbies[rid], uid)
 	if err != nil {
+		lobbiesLock.Unlock()
 		return fmt.Errorf("could not remove UID from slice: %w", err)
 	}
 
-	if len(lobbies[rid]) == 0 {
-		room.DeleteRoom(rid)
+	lobbies[rid] = remaining
+	empty := len(remaining) == 0
+	if empty {
 		delete(lobbies, rid)
 	}
+	lobbiesLock.Unlock()
+
+	left, err := room.GetRoom(rid)
+	if err == nil {
+		roomLeaves.Inc(left.GameMode)
+	}
+
+	if empty {
+		room.DeleteRoom(ctx, rid)
+	}
 
 	return nil
 }
 
-func GetUsersInRoom(rid string) ([]user.User, error) {
+func GetUsersInRoom(ctx context.Context, rid string) ([]user.User, error) {
+	ctx, span := tracing.Start(ctx, "lobby.GetUsersInRoom")
+	defer span.End()
+	span.SetAttribute("rid", rid)
+
 	_, err := room.GetRoom(rid)
 	if err != nil {
 		return nil, fmt.Errorf("could not find room: %w", err)
@@ -102,11 +167,11 @@ func GetUsersInRoom(rid string) ([]user.User, error) {
 	}
 
 	var users []user.User
-	for _, uid := range lobbies[rid] {
+	for _, uid := range lobbyMembers(rid) {
 		user, err := user.GetUser(uid)
 		if err != nil {
-			log.Printf("[Error] Attempted to get user in lobby room list: %v", err)
-			err = removeUIDFromLobby(uid, rid)
+			slog.ErrorContext(ctx, "Attempted to get user in lobby room list", "uid", uid, "error", err)
+			err = removeUIDFromLobby(ctx, uid, rid)
 			if err != nil {
 				return nil, err
 			}
@@ -128,6 +193,32 @@ func GetUsersInRoom(rid string) ([]user.User, error) {
 	return users, err
 }
 
+func GetRoomPlayers(rid string) []payload.Player {
+	members := lobbyMembers(rid)
+
+	players := make([]payload.Player, 0, len(members))
+	for _, uid := range members {
+		roomUser, err := user.GetUser(uid)
+		if err != nil {
+			continue
+		}
+
+		players = append(players, payload.Player{
+			UID:  uid,
+			Name: roomUser.Name,
+		})
+	}
+
+	return players
+}
+
+func CountLobbies() int {
+	lobbiesLock.Lock()
+	defer lobbiesLock.Unlock()
+
+	return len(lobbies)
+}
+
 func GetRoomUserCount(rid string) (int, error) {
 	_, err := room.GetRoom(rid)
 	if err != nil {
@@ -139,7 +230,24 @@ func GetRoomUserCount(rid string) (int, error) {
 		return 0, err
 	}
 
-	return len(lobbies[rid]), nil
+	return len(lobbyMembers(rid)), nil
+}
+
+func RequireUserInRoom(uid string, rid string) error {
+	err := checkUserAndRoomExist(uid, rid)
+	if err != nil {
+		return err
+	}
+
+	if !checkRoomContainsUser(uid, rid) {
+		return &sErr.MatchNotFoundError[string]{
+			Space: "Room Users",
+			Field: "UID",
+			Value: uid,
+		}
+	}
+
+	return nil
 }
 
 func checkUserAndRoomExist(uid string, rid string) error {
@@ -169,11 +277,22 @@ func requireRoomLobby(rid string) error {
 }
 
 func checkRoomLobbyExists(rid string) bool {
+	lobbiesLock.Lock()
+	defer lobbiesLock.Unlock()
+
 	_, found := lobbies[rid]
 	return found
 }
 
 func checkRoomContainsUser(uid string, rid string) bool {
+	lobbiesLock.Lock()
+	defer lobbiesLock.Unlock()
+
+	return lobbyContainsUser(uid, rid)
+}
+
+// lobbyContainsUser expects the caller to hold lobbiesLock.
+func lobbyContainsUser(uid string, rid string) bool {
 	for _, userID := range lobbies[rid] {
 		if userID == uid {
 			return true
@@ -182,3 +301,15 @@ func checkRoomContainsUser(uid string, rid string) bool {
 
 	return false
 }
+
+// lobbyMembers returns a copy of the room's users that stays safe to read
+// after the lobby lock is released.
+func lobbyMembers(rid string) []string {
+	lobbiesLock.Lock()
+	defer lobbiesLock.Unlock()
+
+	members := make([]string, len(lobbies[rid]))
+	copy(members, lobbies[rid])
+
+	return members
+}
@@ -1,18 +1,19 @@
 package lobby
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"os"
+	"sync"
 	"testing"
-	"time"
 
 	"github.com/google/uuid"
 
+	gameclient "Engee-Server/gameClient"
 	reg "Engee-Server/gameRegistry"
 	"Engee-Server/room"
 	sErr "Engee-Server/stockErrors"
-	"Engee-Server/testDummy"
 	"Engee-Server/user"
 )
 
@@ -79,10 +80,31 @@ func TestJoinUserToRoomDouble(t *testing.T) {
 	}
 }
 
+func TestJoinUserToRoomFull(t *testing.T) {
+	uid, _ := createUserAndRoom(t)
+	otherUID, _ := createUserAndRoom(t)
+
+	fullRoom, _ := json.Marshal(room.Room{
+		Name:       testRoomName,
+		GameMode:   testGameMode,
+		MaxPlayers: 1,
+	})
+
+	rid, _ := room.CreateRoom(context.Background(), fullRoom)
+	t.Cleanup(func() { room.DeleteRoom(context.Background(), rid) })
+
+	JoinUserToRoom(uid, rid)
+
+	err := JoinUserToRoom(otherUID, rid)
+	if !errors.As(err, &sErr.CR_ERR) {
+		t.Fatalf(`TestJoinUserToRoom(Full) = %v, want CapacityReachedError`, err)
+	}
+}
+
 func TestRemoveUserFromRoom(t *testing.T) {
 	uid, rid := setupLobbyTest(t)
 
-	err := RemoveUserFromRoom(uid, rid)
+	err := RemoveUserFromRoom(context.Background(), uid, rid)
 	if err != nil {
 		t.Fatalf(`TestRemoveUserFromRoom(Valid) = %v, want nil`, err)
 	}
@@ -91,7 +113,7 @@ func TestRemoveUserFromRoom(t *testing.T) {
 func TestRemoveUserFromRoomInvalidUID(t *testing.T) {
 	_, rid := setupLobbyTest(t)
 
-	err := RemoveUserFromRoom(randomID, rid)
+	err := RemoveUserFromRoom(context.Background(), randomID, rid)
 	if !errors.As(err, &sErr.MNF_ERR) {
 		t.Fatalf(`TestRemoveUserFromRoom(InvalidUID) = %v, want MatchNotFoundError`, err)
 	}
@@ -100,7 +122,7 @@ func TestRemoveUserFromRoomInvalidUID(t *testing.T) {
 func TestRemoveUserFromRoomInvalidRID(t *testing.T) {
 	uid, _ := setupLobbyTest(t)
 
-	err := RemoveUserFromRoom(uid, randomID)
+	err := RemoveUserFromRoom(context.Background(), uid, randomID)
 	if !errors.As(err, &sErr.MNF_ERR) {
 		t.Fatalf(`TestRemoveUserFromRoom(InvalidRID) = %v, want MatchNotFoundError`, err)
 	}
@@ -109,9 +131,9 @@ func TestRemoveUserFromRoomInvalidRID(t *testing.T) {
 func TestRemoveUserFromRoomDouble(t *testing.T) {
 	uid, rid := setupLobbyTest(t)
 
-	RemoveUserFromRoom(uid, rid)
+	RemoveUserFromRoom(context.Background(), uid, rid)
 
-	err := RemoveUserFromRoom(uid, rid)
+	err := RemoveUserFromRoom(context.Background(), uid, rid)
 	if !errors.As(err, &sErr.MNF_ERR) {
 		t.Fatalf(`TestRemoveUserFromRoom(Double) = %v, want MatchNotFoundError`, err)
 	}
@@ -121,7 +143,7 @@ func TestRemoveUserFromRoomDouble(t *testing.T) {
 func TestGetUsersInRoom(t *testing.T) {
 	uid, rid := setupLobbyTest(t)
 
-	users, err := GetUsersInRoom(rid)
+	users, err := GetUsersInRoom(context.Background(), rid)
 	if len(users) != 1 || err != nil {
 		t.Fatalf(`TestGetUsersInRoom(Valid) = %v, %v, want [%v], nil`, users, err, uid)
 	}
@@ -136,7 +158,7 @@ func TestGetMultiUsersInRoom(t *testing.T) {
 
 	expected = append(expected, addMoreUsersToLobby(t, rid)...)
 
-	users, err := GetUsersInRoom(rid)
+	users, err := GetUsersInRoom(context.Background(), rid)
 	if len(users) != len(expected) || err != nil {
 		t.Fatalf(`TestGetUsersInRoom(Multi) = %v, %v, want %v, nil`, users, err, expected)
 	}
@@ -151,7 +173,7 @@ func TestGetMultiUsersInRoom(t *testing.T) {
 func TestGetUsersInRoomInvalidRID(t *testing.T) {
 	setupLobbyTest(t)
 
-	users, err := GetUsersInRoom(randomID)
+	users, err := GetUsersInRoom(context.Background(), randomID)
 	if len(users) != 0 || !errors.As(err, &sErr.MNF_ERR) {
 		t.Fatalf(`TestGetUsersInRoom(InvalidGID) = %v, %v, want [], MatchNotFoundError`, users, err)
 	}
@@ -160,9 +182,9 @@ func TestGetUsersInRoomInvalidRID(t *testing.T) {
 func TestGetUsersInRoomAfterDelete(t *testing.T) {
 	uid, rid := setupLobbyTest(t)
 
-	RemoveUserFromRoom(uid, rid)
+	RemoveUserFromRoom(context.Background(), uid, rid)
 
-	users, err := GetUsersInRoom(rid)
+	users, err := GetUsersInRoom(context.Background(), rid)
 	if len(users) != 0 || !errors.As(err, &sErr.MNF_ERR) {
 		t.Fatalf(`TestGetUsersInRoom(AfterUserDelete) = %v, %v, want [], MatchNotFoundError`, users, err)
 	}
@@ -200,7 +222,7 @@ func TestGetRoomUserCountInvalidRID(t *testing.T) {
 func TestGetRoomUserCountAfterDelete(t *testing.T) {
 	uid, rid := setupLobbyTest(t)
 
-	RemoveUserFromRoom(uid, rid)
+	RemoveUserFromRoom(context.Background(), uid, rid)
 
 	count, err := GetRoomUserCount(rid)
 	if count != 0 || !errors.As(err, &sErr.MNF_ERR) {
@@ -208,13 +230,88 @@ func TestGetRoomUserCountAfterDelete(t *testing.T) {
 	}
 }
 
+func TestGetRoomPlayers(t *testing.T) {
+	uid, rid := setupLobbyTest(t)
+
+	players := GetRoomPlayers(rid)
+	if len(players) != 1 || players[0].UID != uid || players[0].Name != testUserName {
+		t.Fatalf(`GetRoomPlayers(Valid) = %v, want [{%s %s}]`, players, uid, testUserName)
+	}
+}
+
+func TestGetRoomPlayersEmpty(t *testing.T) {
+	players := GetRoomPlayers(randomID)
+	if players == nil || len(players) != 0 {
+		t.Fatalf(`GetRoomPlayers(Empty) = %v, want []`, players)
+	}
+}
+
+func TestJoinUserToRoomConcurrentCount(t *testing.T) {
+	_, rid := setupLobbyTest(t)
+
+	uids := make([]string, 0)
+	for i := 0; i < moreUserCount; i++ {
+		uid, _ := user.CreateUser(testUserName)
+		uids = append(uids, uid)
+	}
+	t.Cleanup(func() {
+		for _, uid := range uids {
+			user.DeleteUser(uid)
+		}
+	})
+
+	var wg sync.WaitGroup
+	for _, uid := range uids {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			JoinUserToRoom(uid, rid)
+		}()
+		go func() {
+			defer wg.Done()
+			CountLobbies()
+			GetRoomPlayers(rid)
+		}()
+	}
+	wg.Wait()
+
+	count, err := GetRoomUserCount(rid)
+	if count != moreUserCount+1 || err != nil {
+		t.Fatalf(`JoinUserToRoom(Concurrent) count = %d, %v, want %d, nil`, count, err, moreUserCount+1)
+	}
+}
+
+func TestRequireUserInRoom(t *testing.T) {
+	uid, rid := setupLobbyTest(t)
+
+	err := RequireUserInRoom(uid, rid)
+	if err != nil {
+		t.Fatalf(`RequireUserInRoom(Valid) = %v, want nil`, err)
+	}
+}
+
+func TestRequireUserInRoomNotJoined(t *testing.T) {
+	_, rid := setupLobbyTest(t)
+	uid, _ := user.CreateUser(testUserName)
+	t.Cleanup(func() {
+		user.DeleteUser(uid)
+	})
+
+	err := RequireUserInRoom(uid, rid)
+	if !errors.As(err, &sErr.MNF_ERR) {
+		t.Fatalf(`RequireUserInRoom(NotJoined) = %v, want MatchNotFoundError`, err)
+	}
+}
+
 func setupLobbyTest(t *testing.T) (string, string) {
 	uid, rid := createUserAndRoom(t)
 
 	JoinUserToRoom(uid, rid)
 
 	t.Cleanup(func() {
+		lobbiesLock.Lock()
 		lobbies = make(map[string][]string)
+		lobbiesLock.Unlock()
 	})
 
 	return uid, rid
@@ -226,14 +323,14 @@ func createUserAndRoom(t *testing.T) (string, string) {
 		t.Fatalf("Could not create user: %v", err)
 	}
 
-	rid, err := room.CreateRoom(testRoom)
+	rid, err := room.CreateRoom(context.Background(), testRoom)
 	if err != nil {
 		t.Fatalf("Could not create room: %v", err)
 	}
 
 	t.Cleanup(func() {
 		user.DeleteUser(uid)
-		room.DeleteRoom(rid)
+		room.DeleteRoom(context.Background(), rid)
 	})
 
 	return uid, rid
@@ -259,11 +356,9 @@ func addMoreUsersToLobby(t *testing.T, rid string) []string {
 }
 
 func setupLobbySuite() {
-	go testDummy.Serve(testConPort)
+	room.SetGameBackend(gameclient.NewFakeBackend())
 
 	reg.RegisterGameMode(testGameMode, testConURL)
-
-	time.Sleep(200 * time.Millisecond)
 }
 
 func cleanUpLobbySuite() {
@@ -0,0 +1,163 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/google/uuid"
+
+	sErr "Engee-Server/stockErrors"
+	"Engee-Server/tracing"
+)
+
+const RequestIDHeader = "X-Request-ID"
+const CallerUIDHeader = "X-Engee-Caller-UID"
+const RIDHeader = "X-Engee-RID"
+
+const FormatText = "text"
+const FormatJSON = "json"
+
+type contextKey int
+
+const (
+	requestIDKey contextKey = iota
+	uidKey
+	callerUIDKey
+	ridKey
+)
+
+func init() {
+	slog.SetDefault(slog.New(contextHandler{slog.NewTextHandler(os.Stderr, nil)}))
+}
+
+// Configure replaces the default slog logger with one writing format
+// ("text" or "json") at level and above to stderr.
+func Configure(format string, level string) error {
+	return configure(os.Stderr, format, level)
+}
+
+func configure(w io.Writer, format string, level string) error {
+	var minLevel slog.Level
+	if level != "" {
+		err := minLevel.UnmarshalText([]byte(level))
+		if err != nil {
+			return &sErr.InvalidValueError[string]{
+				Field: "Log Level",
+				Value: level,
+			}
+		}
+	}
+
+	options := &slog.HandlerOptions{Level: minLevel}
+
+	var handler slog.Handler
+	switch format {
+	case FormatText, "":
+		handler = slog.NewTextHandler(w, options)
+	case FormatJSON:
+		handler = slog.NewJSONHandler(w, options)
+	default:
+		return &sErr.InvalidValueError[string]{
+			Field: "Log Format",
+			Value: format,
+		}
+	}
+
+	slog.SetDefault(slog.New(contextHandler{handler}))
+	return nil
+}
+
+func NewRequestID() string {
+	return uuid.NewString()
+}
+
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+func WithUID(ctx context.Context, uid string) context.Context {
+	return context.WithValue(ctx, uidKey, uid)
+}
+
+// WithCallerUID carries the UID of the user whose token authenticated the
+// request. Unlike the UID given to WithUID it is forwarded to game servers.
+func WithCallerUID(ctx context.Context, uid string) context.Context {
+	return context.WithValue(ctx, callerUIDKey, uid)
+}
+
+func WithRID(ctx context.Context, rid string) context.Context {
+	return context.WithValue(ctx, ridKey, rid)
+}
+
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+func UID(ctx context.Context) string {
+	uid, _ := ctx.Value(uidKey).(string)
+	return uid
+}
+
+func CallerUID(ctx context.Context) string {
+	uid, _ := ctx.Value(callerUIDKey).(string)
+	return uid
+}
+
+func RID(ctx context.Context) string {
+	rid, _ := ctx.Value(ridKey).(string)
+	return rid
+}
+
+// Headers returns the request ID, caller UID and RID carried by ctx keyed by
+// the headers that forward them to game servers.
+func Headers(ctx context.Context) map[string]string {
+	headers := make(map[string]string)
+	for header, value := range map[string]string{
+		RequestIDHeader: RequestID(ctx),
+		CallerUIDHeader: CallerUID(ctx),
+		RIDHeader:       RID(ctx),
+	} {
+		if value != "" {
+			headers[header] = value
+		}
+	}
+
+	return headers
+}
+
+// contextHandler adds the request ID, UID and RID carried by a context to
+// every record logged with it.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if ctx != nil {
+		addAttr(&record, "request_id", RequestID(ctx))
+		addAttr(&record, "uid", UID(ctx))
+		addAttr(&record, "rid", RID(ctx))
+
+		span := tracing.SpanFromContext(ctx)
+		addAttr(&record, "trace_id", span.TraceID())
+		addAttr(&record, "span_id", span.SpanID())
+	}
+
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+func addAttr(record *slog.Record, key string, value string) {
+	if value != "" {
+		record.AddAttrs(slog.String(key, value))
+	}
+}
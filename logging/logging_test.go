@@ -0,0 +1,76 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	sErr "Engee-Server/stockErrors"
+)
+
+func TestConfigureJSON(t *testing.T) {
+	var out bytes.Buffer
+	setupLoggingTest(t, &out, FormatJSON, "info")
+
+	ctx := WithRID(WithUID(WithRequestID(context.Background(), "request"), "user"), "room")
+	slog.InfoContext(ctx, "Testing")
+
+	var line map[string]any
+	err := json.Unmarshal(out.Bytes(), &line)
+	if err != nil || line["request_id"] != "request" || line["uid"] != "user" || line["rid"] != "room" {
+		t.Fatalf(`InfoContext(JSON) = %q, %v, want request_id, uid and rid`, out.String(), err)
+	}
+}
+
+func TestConfigureLevel(t *testing.T) {
+	var out bytes.Buffer
+	setupLoggingTest(t, &out, FormatText, "warn")
+
+	slog.Info("Hidden")
+	if out.Len() != 0 {
+		t.Fatalf(`Info(LevelWarn) = %q, want nothing logged`, out.String())
+	}
+}
+
+func TestConfigureInvalid(t *testing.T) {
+	err := configure(&bytes.Buffer{}, "xml", "")
+	if !errors.As(err, &sErr.IV_ERR) {
+		t.Fatalf(`configure(InvalidFormat) = %v, want InvalidValueError`, err)
+	}
+
+	err = configure(&bytes.Buffer{}, FormatText, "loud")
+	if !errors.As(err, &sErr.IV_ERR) {
+		t.Fatalf(`configure(InvalidLevel) = %v, want InvalidValueError`, err)
+	}
+}
+
+func TestHeaders(t *testing.T) {
+	headers := Headers(WithRequestID(context.Background(), "request"))
+	if len(headers) != 1 || headers[RequestIDHeader] != "request" {
+		t.Fatalf(`Headers(RequestID) = %v, want only %s`, headers, RequestIDHeader)
+	}
+
+	headers = Headers(WithUID(context.Background(), "user"))
+	if len(headers) != 0 {
+		t.Fatalf(`Headers(UID) = %v, want no headers`, headers)
+	}
+
+	headers = Headers(WithCallerUID(context.Background(), "user"))
+	if len(headers) != 1 || headers[CallerUIDHeader] != "user" {
+		t.Fatalf(`Headers(CallerUID) = %v, want only %s`, headers, CallerUIDHeader)
+	}
+}
+
+func setupLoggingTest(t *testing.T, out *bytes.Buffer, format string, level string) {
+	previous := slog.Default()
+
+	err := configure(out, format, level)
+	if err != nil {
+		t.Fatalf(`configure(%s, %s) = %v, want nil`, format, level, err)
+	}
+
+	t.Cleanup(func() { slog.SetDefault(previous) })
+}
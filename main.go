@@ -1,11 +1,85 @@
 package main
 
 import (
+	"context"
+	"log/slog"
+	"os"
+	"time"
+
 	"Engee-Server/config"
+	registry "Engee-Server/gameRegistry"
+	"Engee-Server/lobby"
+	"Engee-Server/logging"
+	"Engee-Server/room"
 	"Engee-Server/server"
+	"Engee-Server/tracing"
+	"Engee-Server/user"
 )
 
 func main() {
 	config := config.ReadConfig()
+
+	err := logging.Configure(config.LogFormat, config.LogLevel)
+	if err != nil {
+		slog.Error("Configuring logging", "error", err)
+		os.Exit(1)
+	}
+
+	err = tracing.Configure(config.TraceExporter, config.TraceFile)
+	if err != nil {
+		slog.Error("Configuring tracing", "error", err)
+		os.Exit(1)
+	}
+
+	registry.SetGameModeSecrets(config.GameModeSecrets)
+	registry.SetRestartHandler(room.ReconcileGameServer)
+	room.SetPlayerLookup(lobby.GetRoomPlayers)
+	room.SetCallbackBaseURL(config.PublicURL)
+	server.SetAdminToken(config.AdminToken)
+	server.SetReadinessOptions(config.RequireGameMode)
+	server.SetShutdownOptions(time.Duration(config.ShutdownTimeout)*time.Second, config.StateFile)
+
+	if config.CORS != nil {
+		err = server.SetCORSPolicy(*config.CORS)
+		if err != nil {
+			slog.Error("Configuring CORS", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	err = server.SetTrustedProxies(config.TrustedProxies)
+	if err != nil {
+		slog.Error("Configuring trusted proxies", "error", err)
+		os.Exit(1)
+	}
+
+	err = server.SetRateLimits(config.RateLimits)
+	if err != nil {
+		slog.Error("Configuring rate limits", "error", err)
+		os.Exit(1)
+	}
+
+	room.SetMaxRoomsPerOwner(config.RateLimits.MaxRoomsPerUser)
+	user.SetMaxUsersPerIP(config.RateLimits.MaxUsersPerIP)
+
+	err = registry.SetWebhooks(config.RegistryWebhooks, config.RegistryWebhookSecret)
+	if err != nil {
+		slog.Error("Configuring registry webhooks", "error", err)
+		os.Exit(1)
+	}
+
+	err = registry.LoadCatalogue(config.GameModes)
+	if err != nil {
+		slog.Error("Loading game mode catalogue", "error", err)
+		os.Exit(1)
+	}
+
+	if config.StateFile != "" {
+		err = room.RestoreRooms(context.Background(), config.StateFile)
+		if err != nil {
+			slog.Error("Restoring rooms", "error", err)
+		}
+	}
+
 	server.Serve(config.Port)
 }
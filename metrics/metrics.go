@@ -0,0 +1,270 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type collector interface {
+	name() string
+	write(w io.Writer)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+var lock sync.Mutex
+var collectors = make(map[string]collector)
+
+func register(c collector) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	_, found := collectors[c.name()]
+	if found {
+		panic(fmt.Sprintf("metric %s registered twice", c.name()))
+	}
+
+	collectors[c.name()] = c
+}
+
+// WriteText writes every registered metric in the Prometheus text format.
+func WriteText(w io.Writer) {
+	lock.Lock()
+	names := make([]string, 0, len(collectors))
+	for name := range collectors {
+		names = append(names, name)
+	}
+	lock.Unlock()
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		lock.Lock()
+		c := collectors[name]
+		lock.Unlock()
+
+		c.write(w)
+	}
+}
+
+type GaugeFunc struct {
+	metric string
+	help   string
+	value  func() float64
+}
+
+func NewGaugeFunc(name string, help string, value func() float64) *GaugeFunc {
+	g := &GaugeFunc{metric: name, help: help, value: value}
+	register(g)
+
+	return g
+}
+
+func (g *GaugeFunc) name() string {
+	return g.metric
+}
+
+func (g *GaugeFunc) write(w io.Writer) {
+	writeHeader(w, g.metric, g.help, "gauge")
+	fmt.Fprintf(w, "%s %s\n", g.metric, formatValue(g.value()))
+}
+
+type Counter struct {
+	metric string
+	help   string
+	labels []string
+
+	lock   sync.Mutex
+	values map[string]float64
+}
+
+func NewCounter(name string, help string, labels ...string) *Counter {
+	c := &Counter{metric: name, help: help, labels: labels, values: make(map[string]float64)}
+	register(c)
+
+	return c
+}
+
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+func (c *Counter) Add(delta float64, labelValues ...string) {
+	key := formatLabels(c.labels, labelValues)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.values[key] += delta
+}
+
+func (c *Counter) Value(labelValues ...string) float64 {
+	key := formatLabels(c.labels, labelValues)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.values[key]
+}
+
+func (c *Counter) name() string {
+	return c.metric
+}
+
+func (c *Counter) write(w io.Writer) {
+	writeHeader(w, c.metric, c.help, "counter")
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, key := range sortedKeys(c.values) {
+		fmt.Fprintf(w, "%s%s %s\n", c.metric, key, formatValue(c.values[key]))
+	}
+}
+
+type Histogram struct {
+	metric  string
+	help    string
+	labels  []string
+	buckets []float64
+
+	lock   sync.Mutex
+	series map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	labelValues []string
+	counts      []uint64
+	count       uint64
+	sum         float64
+}
+
+func NewHistogram(name string, help string, buckets []float64, labels ...string) *Histogram {
+	h := &Histogram{
+		metric:  name,
+		help:    help,
+		labels:  labels,
+		buckets: buckets,
+		series:  make(map[string]*histogramSeries),
+	}
+	register(h)
+
+	return h
+}
+
+func (h *Histogram) Observe(value float64, labelValues ...string) {
+	key := formatLabels(h.labels, labelValues)
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	series, found := h.series[key]
+	if !found {
+		series = &histogramSeries{
+			labelValues: append([]string{}, labelValues...),
+			counts:      make([]uint64, len(h.buckets)),
+		}
+		h.series[key] = series
+	}
+
+	for i, bound := range h.buckets {
+		if value <= bound {
+			series.counts[i]++
+		}
+	}
+
+	series.count++
+	series.sum += value
+}
+
+// ObserveSince records the seconds elapsed since start.
+func (h *Histogram) ObserveSince(start time.Time, labelValues ...string) {
+	h.Observe(time.Since(start).Seconds(), labelValues...)
+}
+
+func (h *Histogram) Count(labelValues ...string) uint64 {
+	key := formatLabels(h.labels, labelValues)
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	series, found := h.series[key]
+	if !found {
+		return 0
+	}
+
+	return series.count
+}
+
+func (h *Histogram) name() string {
+	return h.metric
+}
+
+func (h *Histogram) write(w io.Writer) {
+	writeHeader(w, h.metric, h.help, "histogram")
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	for _, key := range sortedKeys(h.series) {
+		series := h.series[key]
+		bucketLabels := append(append([]string{}, h.labels...), "le")
+		bucketKey := func(bound string) string {
+			return formatLabels(bucketLabels, append(append([]string{}, series.labelValues...), bound))
+		}
+
+		for i, bound := range h.buckets {
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.metric, bucketKey(formatValue(bound)), series.counts[i])
+		}
+
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.metric, bucketKey("+Inf"), series.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.metric, key, formatValue(series.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.metric, key, series.count)
+	}
+}
+
+func writeHeader(w io.Writer, name string, help string, kind string) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w, "# TYPE %s %s\n", name, kind)
+}
+
+func formatLabels(labels []string, values []string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	pairs := make([]string, 0, len(labels))
+	for i, label := range labels {
+		value := ""
+		if i < len(values) {
+			value = values[i]
+		}
+
+		pairs = append(pairs, label+`="`+labelEscaper.Replace(value)+`"`)
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatValue(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func sortedKeys[T any](values map[string]T) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCounter(t *testing.T) {
+	counter := NewCounter("test_counter_total", "Test counter.", "mode")
+	counter.Inc("chess")
+	counter.Add(2, "chess")
+
+	if counter.Value("chess") != 3 {
+		t.Fatalf(`Counter.Value(chess) = %v, want 3`, counter.Value("chess"))
+	}
+
+	var out bytes.Buffer
+	WriteText(&out)
+	if !strings.Contains(out.String(), `test_counter_total{mode="chess"} 3`) {
+		t.Fatalf(`WriteText(Counter) = %q, want labelled counter`, out.String())
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	histogram := NewHistogram("test_histogram_seconds", "Test histogram.", []float64{0.1, 1}, "op")
+	histogram.Observe(0.05, "get")
+	histogram.Observe(0.5, "get")
+
+	if histogram.Count("get") != 2 {
+		t.Fatalf(`Histogram.Count(get) = %d, want 2`, histogram.Count("get"))
+	}
+
+	var out bytes.Buffer
+	WriteText(&out)
+	for _, line := range []string{
+		`test_histogram_seconds_bucket{op="get",le="0.1"} 1`,
+		`test_histogram_seconds_bucket{op="get",le="1"} 2`,
+		`test_histogram_seconds_bucket{op="get",le="+Inf"} 2`,
+		`test_histogram_seconds_count{op="get"} 2`,
+	} {
+		if !strings.Contains(out.String(), line) {
+			t.Fatalf(`WriteText(Histogram) = %q, want %q`, out.String(), line)
+		}
+	}
+}
+
+func TestGaugeFunc(t *testing.T) {
+	NewGaugeFunc("test_gauge", "Test gauge.", func() float64 { return 7 })
+
+	var out bytes.Buffer
+	WriteText(&out)
+	if !strings.Contains(out.String(), "# TYPE test_gauge gauge\ntest_gauge 7\n") {
+		t.Fatalf(`WriteText(Gauge) = %q, want gauge value`, out.String())
+	}
+}
+
+func TestLabelEscaping(t *testing.T) {
+	counter := NewCounter("test_escaped_total", "Test escaping.", "name")
+	counter.Inc("a\"b\\c")
+
+	var out bytes.Buffer
+	WriteText(&out)
+	if !strings.Contains(out.String(), `test_escaped_total{name="a\"b\\c"} 1`) {
+		t.Fatalf(`WriteText(Escaped) = %q, want escaped label`, out.String())
+	}
+}
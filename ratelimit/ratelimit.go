@@ -0,0 +1,153 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	sErr "Engee-Server/stockErrors"
+)
+
+const pruneInterval = time.Minute
+
+// Budget allows a client Burst requests at once, refilled at Rate requests
+// per second. A Rate of zero leaves requests unlimited.
+type Budget struct {
+	Rate  float64 `json:"requests_per_second"`
+	Burst int     `json:"burst"`
+}
+
+type Config struct {
+	Default         Budget            `json:"default"`
+	Routes          map[string]Budget `json:"routes"`
+	MaxRoomsPerUser int               `json:"max_rooms_per_user"`
+	MaxUsersPerIP   int               `json:"max_users_per_ip"`
+}
+
+func (b Budget) Limited() bool {
+	return b.Rate > 0
+}
+
+func (b Budget) Validate() error {
+	if b.Rate < 0 || math.IsNaN(b.Rate) || math.IsInf(b.Rate, 0) {
+		return &sErr.InvalidValueError[float64]{
+			Field: "Requests Per Second",
+			Value: b.Rate,
+		}
+	}
+
+	if b.Burst < 0 {
+		return &sErr.InvalidValueError[int]{
+			Field: "Burst",
+			Value: b.Burst,
+		}
+	}
+
+	return nil
+}
+
+func (c Config) Validate() error {
+	err := c.Default.Validate()
+	if err != nil {
+		return err
+	}
+
+	for _, budget := range c.Routes {
+		err = budget.Validate()
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.MaxRoomsPerUser < 0 {
+		return &sErr.InvalidValueError[int]{
+			Field: "Max Rooms Per User",
+			Value: c.MaxRoomsPerUser,
+		}
+	}
+
+	if c.MaxUsersPerIP < 0 {
+		return &sErr.InvalidValueError[int]{
+			Field: "Max Users Per IP",
+			Value: c.MaxUsersPerIP,
+		}
+	}
+
+	return nil
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// Limiter keeps a token bucket per client key, all sharing one budget.
+type Limiter struct {
+	rate  float64
+	burst float64
+	now   func() time.Time
+
+	lock    sync.Mutex
+	buckets map[string]*bucket
+	pruned  time.Time
+}
+
+func NewLimiter(budget Budget) *Limiter {
+	burst := float64(budget.Burst)
+	if burst < 1 {
+		burst = math.Max(1, math.Ceil(budget.Rate))
+	}
+
+	return &Limiter{
+		rate:    budget.Rate,
+		burst:   burst,
+		now:     time.Now,
+		buckets: make(map[string]*bucket),
+		pruned:  time.Now(),
+	}
+}
+
+// Allow takes a token from key's bucket. When the bucket is empty it
+// returns false and how long until the next token is available.
+func (l *Limiter) Allow(key string) (bool, time.Duration) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := l.now()
+	if now.Sub(l.pruned) >= pruneInterval {
+		l.prune(now)
+	}
+
+	b, found := l.buckets[key]
+	if !found {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = l.refill(b, now)
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+
+	wait := time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+	return false, wait
+}
+
+func (l *Limiter) refill(b *bucket, now time.Time) float64 {
+	return math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+}
+
+// prune forgets buckets that have refilled completely, since a new bucket
+// would start in the same state.
+func (l *Limiter) prune(now time.Time) {
+	for key, b := range l.buckets {
+		if l.refill(b, now) >= l.burst {
+			delete(l.buckets, key)
+		}
+	}
+
+	l.pruned = now
+}
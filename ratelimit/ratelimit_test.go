@@ -0,0 +1,84 @@
+package ratelimit
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	sErr "Engee-Server/stockErrors"
+)
+
+func TestAllowBurst(t *testing.T) {
+	limiter, _ := setupLimiterTest(Budget{Rate: 1, Burst: 2})
+
+	for i := 0; i < 2; i++ {
+		allowed, _ := limiter.Allow("client")
+		if !allowed {
+			t.Fatalf(`Allow(Burst %d) = false, want true`, i)
+		}
+	}
+
+	allowed, wait := limiter.Allow("client")
+	if allowed || wait != time.Second {
+		t.Fatalf(`Allow(Empty) = %v, %v, want false, 1s`, allowed, wait)
+	}
+}
+
+func TestAllowRefills(t *testing.T) {
+	limiter, clock := setupLimiterTest(Budget{Rate: 2, Burst: 1})
+
+	limiter.Allow("client")
+	*clock = clock.Add(500 * time.Millisecond)
+
+	allowed, _ := limiter.Allow("client")
+	if !allowed {
+		t.Fatalf(`Allow(Refilled) = false, want true`)
+	}
+}
+
+func TestAllowSeparateKeys(t *testing.T) {
+	limiter, _ := setupLimiterTest(Budget{Rate: 1, Burst: 1})
+
+	limiter.Allow("first")
+
+	allowed, _ := limiter.Allow("second")
+	if !allowed {
+		t.Fatalf(`Allow(OtherKey) = false, want true`)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	limiter, clock := setupLimiterTest(Budget{Rate: 1, Burst: 1})
+
+	limiter.Allow("client")
+	*clock = clock.Add(pruneInterval)
+	limiter.Allow("other")
+
+	if _, found := limiter.buckets["client"]; found {
+		t.Fatalf(`Allow(Prune) = %v, want refilled bucket removed`, limiter.buckets)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	err := Config{Default: Budget{Rate: -1}}.Validate()
+	var invalidRate *sErr.InvalidValueError[float64]
+	if !errors.As(err, &invalidRate) {
+		t.Fatalf(`Validate(NegativeRate) = %v, want InvalidValueError`, err)
+	}
+
+	err = Config{Routes: map[string]Budget{"POST /users": {Rate: 1, Burst: -1}}}.Validate()
+	var invalidBurst *sErr.InvalidValueError[int]
+	if !errors.As(err, &invalidBurst) {
+		t.Fatalf(`Validate(NegativeBurst) = %v, want InvalidValueError`, err)
+	}
+}
+
+func setupLimiterTest(budget Budget) (*Limiter, *time.Time) {
+	clock := time.Now()
+
+	limiter := NewLimiter(budget)
+	limiter.now = func() time.Time { return clock }
+	limiter.pruned = clock
+
+	return limiter, &clock
+}
@@ -0,0 +1,89 @@
+package room
+
+import (
+	"sync"
+
+	sErr "Engee-Server/stockErrors"
+	"Engee-Server/utils"
+)
+
+const EventGameRecreated = "game_recreated"
+const EventGameFailed = "game_failed"
+const EventRoomDeleted = "room_deleted"
+const EventGameModeChanged = "gamemode_changed"
+const EventGameMigrated = "game_migrated"
+
+type RoomEvent struct {
+	RID     string `json:"rid"`
+	Type    string `json:"type"`
+	Message string `json:"message,omitempty"`
+	Data    any    `json:"data,omitempty"`
+}
+
+var eventsLock sync.Mutex
+var roomEvents = make(map[string]*utils.Broadcaster[RoomEvent])
+
+func SubscribeToRoom(rid string) (chan RoomEvent, error) {
+	_, err := GetRoom(rid)
+	if err != nil {
+		return nil, err
+	}
+
+	return roomBroadcaster(rid).Subscribe(), nil
+}
+
+func UnsubscribeFromRoom(rid string, subscriber chan RoomEvent) error {
+	if rid == "" {
+		return &sErr.EmptyValueError{
+			Field: "RID",
+		}
+	}
+
+	eventsLock.Lock()
+	broadcaster, found := roomEvents[rid]
+	eventsLock.Unlock()
+
+	if !found {
+		return &sErr.MatchNotFoundError[string]{
+			Space: "Room Events",
+			Field: "RID",
+			Value: rid,
+		}
+	}
+
+	broadcaster.Unsubscribe(subscriber)
+
+	return nil
+}
+
+func PublishRoomEvent(event RoomEvent) {
+	roomBroadcaster(event.RID).Publish(event)
+}
+
+func roomBroadcaster(rid string) *utils.Broadcaster[RoomEvent] {
+	eventsLock.Lock()
+	defer eventsLock.Unlock()
+
+	broadcaster, found := roomEvents[rid]
+	if !found {
+		broadcaster = utils.NewBroadcaster[RoomEvent]()
+		roomEvents[rid] = broadcaster
+	}
+
+	return broadcaster
+}
+
+func closeRoomEvents(rid string) {
+	eventsLock.Lock()
+	broadcaster, found := roomEvents[rid]
+	delete(roomEvents, rid)
+	eventsLock.Unlock()
+
+	if found {
+		broadcaster.Publish(RoomEvent{
+			RID:  rid,
+			Type: EventRoomDeleted,
+		})
+		broadcaster.Close()
+	}
+}
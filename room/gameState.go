@@ -0,0 +1,126 @@
+package room
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	gameclient "Engee-Server/gameClient"
+	"Engee-Server/tracing"
+)
+
+const gameStateTTL = 2 * time.Second
+const gameStateErrorTTL = 500 * time.Millisecond
+
+// cachedGameState holds the last answer from the game server. Its lock only
+// guards the fields: a fetch runs without it, and callers arriving meanwhile
+// wait on fetching and share the result.
+type cachedGameState struct {
+	lock      sync.Mutex
+	state     gameclient.GameState
+	err       error
+	fetchedAt time.Time
+	fetching  chan struct{}
+}
+
+var gameStatesLock sync.Mutex
+var gameStates = make(map[string]*cachedGameState)
+
+func GetRoomGameState(ctx context.Context, rid string) (gameclient.GameState, error) {
+	ctx, span := tracing.Start(ctx, "room.GetRoomGameState")
+	defer span.End()
+	span.SetAttribute("rid", rid)
+
+	_, err := GetRoom(rid)
+	if err != nil {
+		return gameclient.GameState{}, err
+	}
+
+	cached := roomGameStateCache(rid)
+
+	cached.lock.Lock()
+	for !cached.fresh() && cached.fetching != nil {
+		fetching := cached.fetching
+		cached.lock.Unlock()
+
+		select {
+		case <-fetching:
+		case <-ctx.Done():
+			return gameclient.GameState{}, ctx.Err()
+		}
+
+		cached.lock.Lock()
+	}
+
+	if cached.fresh() {
+		state, err := cached.state, cached.err
+		cached.lock.Unlock()
+
+		return state, wrapGameStateError(err)
+	}
+
+	fetching := make(chan struct{})
+	cached.fetching = fetching
+	cached.lock.Unlock()
+
+	state, err := backend.GetGameState(ctx, rid)
+
+	cached.lock.Lock()
+	// A caller giving up is not an answer from the game server, so nothing
+	// is cached and the callers still waiting fetch again.
+	if ctx.Err() == nil {
+		cached.state = state
+		cached.err = err
+		cached.fetchedAt = time.Now()
+	}
+	cached.fetching = nil
+	close(fetching)
+	cached.lock.Unlock()
+
+	return state, wrapGameStateError(err)
+}
+
+// fresh expects the caller to hold the cache entry's lock. Errors are kept
+// for a shorter time than states, so an unreachable game server is not
+// queried on every request but is retried soon.
+func (cached *cachedGameState) fresh() bool {
+	if cached.fetchedAt.IsZero() {
+		return false
+	}
+
+	ttl := gameStateTTL
+	if cached.err != nil {
+		ttl = gameStateErrorTTL
+	}
+
+	return time.Since(cached.fetchedAt) < ttl
+}
+
+func wrapGameStateError(err error) error {
+	if err != nil {
+		return fmt.Errorf("could not get game state: %w", err)
+	}
+
+	return nil
+}
+
+func InvalidateRoomGameState(rid string) {
+	gameStatesLock.Lock()
+	defer gameStatesLock.Unlock()
+
+	delete(gameStates, rid)
+}
+
+func roomGameStateCache(rid string) *cachedGameState {
+	gameStatesLock.Lock()
+	defer gameStatesLock.Unlock()
+
+	cached, found := gameStates[rid]
+	if !found {
+		cached = &cachedGameState{}
+		gameStates[rid] = cached
+	}
+
+	return cached
+}
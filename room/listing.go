@@ -0,0 +1,96 @@
+package room
+
+import (
+	"strings"
+
+	sErr "Engee-Server/stockErrors"
+	"Engee-Server/utils"
+)
+
+const SortCreated = "created"
+const SortPlayers = "players"
+
+type RoomFilter struct {
+	GameMode string
+	Status   string
+	Name     string
+	HasSpace *bool
+	Private  *bool
+}
+
+func ListRooms(filter RoomFilter, page utils.PageRequest) ([]Room, string, error) {
+	if page.Sort == "" {
+		page.Sort = SortCreated
+	}
+
+	var key func(Room) utils.SortKey
+	switch page.Sort {
+	case SortCreated:
+		key = func(room Room) utils.SortKey {
+			return utils.SortKey{Value: utils.NumericSortValue(room.Created.UnixNano()), ID: room.RID}
+		}
+	case SortPlayers:
+		key = func(room Room) utils.SortKey {
+			return utils.SortKey{Value: utils.NumericSortValue(int64(GetRoomPlayerCount(room.RID))), ID: room.RID}
+		}
+	default:
+		return nil, "", &sErr.InvalidValueError[string]{
+			Field: "Sort",
+			Value: page.Sort,
+		}
+	}
+
+	matches := make([]Room, 0)
+	for _, room := range GetRooms() {
+		if filter.matches(room) {
+			matches = append(matches, room)
+		}
+	}
+
+	return utils.Paginate(matches, page, key)
+}
+
+func GetRoomPlayerCount(rid string) int {
+	if playerLookup == nil {
+		return 0
+	}
+
+	return len(playerLookup(rid))
+}
+
+func (filter RoomFilter) matches(room Room) bool {
+	if filter.GameMode != "" && room.GameMode != filter.GameMode {
+		return false
+	}
+
+	if filter.Status != "" && !strings.EqualFold(room.Status, filter.Status) {
+		return false
+	}
+
+	if filter.Name != "" && !strings.Contains(strings.ToLower(room.Name), strings.ToLower(filter.Name)) {
+		return false
+	}
+
+	if filter.Private != nil && room.Private != *filter.Private {
+		return false
+	}
+
+	if filter.HasSpace != nil && hasSpace(room) != *filter.HasSpace {
+		return false
+	}
+
+	return true
+}
+
+func hasSpace(room Room) bool {
+	return room.MaxPlayers == 0 || GetRoomPlayerCount(room.RID) < room.MaxPlayers
+}
+
+func compareRoomsCreated(a Room, b Room) int {
+	order := a.Created.Compare(b.Created)
+	if order == 0 {
+		order = strings.Compare(a.RID, b.RID)
+	}
+
+	return order
+}
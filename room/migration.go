@@ -0,0 +1,92 @@
+package room
+
+import (
+	"context"
+	"fmt"
+
+	registry "Engee-Server/gameRegistry"
+	"Engee-Server/tracing"
+)
+
+func MigrateRoom(ctx context.Context, rid string, url string, transferState bool) (Room, error) {
+	ctx, span := tracing.Start(ctx, "room.MigrateRoom")
+	defer span.End()
+	span.SetAttribute("rid", rid)
+
+	room, err := GetRoom(rid)
+	if err != nil {
+		return Room{}, err
+	}
+
+	current := registry.GameMode{
+		Name:     room.GameMode,
+		Version:  room.Version,
+		Protocol: room.Protocol,
+		URL:      room.Addr,
+	}
+
+	target, err := registry.ResolveMigrationTarget(current, url)
+	if err != nil {
+		return Room{}, fmt.Errorf("could not find migration target: %w", err)
+	}
+
+	gameBackend := backend
+
+	game, err := gameBackend.GetGameInstance(rid)
+	if err != nil {
+		return Room{}, fmt.Errorf("could not get game instance to migrate: %w", err)
+	}
+
+	var snapshot []byte
+	if transferState {
+		snapshot, err = gameBackend.ExportGameSnapshot(ctx, rid)
+		if err != nil {
+			return Room{}, err
+		}
+	}
+
+	setup := gameSetup(migratedRoom(room, target, transferState))
+	setup.Rules = game.Rules
+	setup.Snapshot = snapshot
+
+	previous, err := gameBackend.MigrateGameInstance(ctx, setup)
+	if err != nil {
+		return Room{}, fmt.Errorf("could not migrate game instance: %w", err)
+	}
+
+	migrated, err := updateRoom(rid, func(stored *Room) error {
+		*stored = migratedRoom(*stored, target, transferState)
+		return nil
+	})
+	if err != nil {
+		discardGameInstance(ctx, gameBackend, rid, "room deleted during migration")
+		discardDetachedInstance(ctx, gameBackend, previous, "room deleted during migration")
+		return Room{}, err
+	}
+
+	discardDetachedInstance(ctx, gameBackend, previous, "room migrated")
+	InvalidateRoomGameState(rid)
+
+	PublishRoomEvent(RoomEvent{
+		RID:     rid,
+		Type:    EventGameMigrated,
+		Message: "game migrated to another game server",
+		Data:    migrated.Public(),
+	})
+
+	return migrated, nil
+}
+
+// migratedRoom moves room to target. A game migrated without its state starts
+// over on the new game server, so a running room goes back to created.
+func migratedRoom(room Room, target registry.GameMode, transferState bool) Room {
+	room.Version = target.Version
+	room.Protocol = target.Protocol
+	room.Addr = target.URL
+
+	if !transferState && room.Status == StatusRunning {
+		room.Status = StatusCreated
+	}
+
+	return room
+}
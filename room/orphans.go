@@ -0,0 +1,180 @@
+package room
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"golang.org/x/exp/maps"
+
+	gameclient "Engee-Server/gameClient"
+	sErr "Engee-Server/stockErrors"
+	"Engee-Server/tracing"
+)
+
+const cleanupMaxAttempts = 8
+
+var cleanupRetryInterval = 5 * time.Second
+
+type OrphanedGame struct {
+	Game      gameclient.GameInstance `json:"game"`
+	Reason    string                  `json:"reason"`
+	Attempts  int                     `json:"attempts"`
+	LastError string                  `json:"last_error"`
+	Since     time.Time               `json:"since"`
+	Retrying  bool                    `json:"retrying"`
+}
+
+var orphansLock sync.Mutex
+var orphans = make(map[string]OrphanedGame)
+
+func GetOrphanedGames() []OrphanedGame {
+	orphansLock.Lock()
+	defer orphansLock.Unlock()
+
+	return maps.Values(orphans)
+}
+
+func RetryOrphanedGame(ctx context.Context, rid string) error {
+	ctx, span := tracing.Start(ctx, "room.RetryOrphanedGame")
+	defer span.End()
+	span.SetAttribute("rid", rid)
+
+	orphansLock.Lock()
+	matches := make([]OrphanedGame, 0)
+	for _, orphan := range orphans {
+		if orphan.Game.RID == rid {
+			matches = append(matches, orphan)
+		}
+	}
+	orphansLock.Unlock()
+
+	if len(matches) == 0 {
+		return &sErr.MatchNotFoundError[string]{
+			Space: "Orphaned games",
+			Field: "RID",
+			Value: rid,
+		}
+	}
+
+	var errs []error
+	for _, orphan := range matches {
+		err := cleanUpOrphan(ctx, backend, orphan)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func discardGameInstance(ctx context.Context, gameBackend gameclient.GameBackend, rid string, reason string) error {
+	game, err := gameBackend.GetGameInstance(rid)
+	if err != nil {
+		return nil
+	}
+
+	return discardDetachedInstance(ctx, gameBackend, game, reason)
+}
+
+func discardDetachedInstance(ctx context.Context, gameBackend gameclient.GameBackend, game gameclient.GameInstance, reason string) error {
+	err := gameBackend.EndGameInstance(ctx, game)
+	if err != nil {
+		orphanGame(gameBackend, game, reason, err)
+	}
+
+	return err
+}
+
+func orphanGame(gameBackend gameclient.GameBackend, game gameclient.GameInstance, reason string, err error) {
+	orphan := OrphanedGame{
+		Game:      game,
+		Reason:    reason,
+		LastError: err.Error(),
+		Since:     time.Now(),
+		Retrying:  true,
+	}
+
+	orphansLock.Lock()
+	_, found := orphans[game.URL]
+	orphans[game.URL] = orphan
+	orphansLock.Unlock()
+
+	slog.Error("Game instance orphaned", "rid", game.RID, "url", game.URL, "reason", reason, "error", err)
+
+	if !found {
+		go retryOrphanCleanup(gameBackend, orphan)
+	}
+}
+
+func retryOrphanCleanup(gameBackend gameclient.GameBackend, orphan OrphanedGame) {
+	interval := cleanupRetryInterval
+
+	for attempt := 0; attempt < cleanupMaxAttempts; attempt++ {
+		time.Sleep(interval)
+		interval *= 2
+
+		err := cleanUpOrphan(context.Background(), gameBackend, orphan)
+		if err == nil || !isOrphaned(orphan.Game.URL) {
+			return
+		}
+	}
+
+	orphansLock.Lock()
+	defer orphansLock.Unlock()
+
+	current, found := orphans[orphan.Game.URL]
+	if found {
+		current.Retrying = false
+		orphans[orphan.Game.URL] = current
+	}
+
+	slog.Error("Giving up on cleaning up game instance", "rid", orphan.Game.RID, "url", orphan.Game.URL, "attempts", cleanupMaxAttempts)
+}
+
+func cleanUpOrphan(ctx context.Context, gameBackend gameclient.GameBackend, orphan OrphanedGame) error {
+	if !isOrphaned(orphan.Game.URL) {
+		return &sErr.MatchNotFoundError[string]{
+			Space: "Orphaned games",
+			Field: "URL",
+			Value: orphan.Game.URL,
+		}
+	}
+
+	err := gameBackend.EndGameInstance(ctx, orphan.Game)
+
+	orphansLock.Lock()
+	defer orphansLock.Unlock()
+
+	if err == nil {
+		delete(orphans, orphan.Game.URL)
+		return nil
+	}
+
+	current, found := orphans[orphan.Game.URL]
+	if found {
+		current.Attempts++
+		current.LastError = err.Error()
+		orphans[orphan.Game.URL] = current
+	}
+
+	return err
+}
+
+func isOrphaned(url string) bool {
+	orphansLock.Lock()
+	defer orphansLock.Unlock()
+
+	_, found := orphans[url]
+	return found
+}
+
+func endGameInstance(ctx context.Context, gameBackend gameclient.GameBackend, rid string) error {
+	if !gameBackend.HasGameInstance(rid) {
+		return nil
+	}
+
+	return gameBackend.EndGame(ctx, rid)
+}
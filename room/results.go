@@ -0,0 +1,86 @@
+package room
+
+import (
+	"sync"
+
+	"golang.org/x/exp/slices"
+)
+
+type RoomResults struct {
+	Scores     map[string]int `json:"scores"`
+	Eliminated []string       `json:"eliminated"`
+	Winners    []string       `json:"winners"`
+}
+
+var resultsLock sync.Mutex
+var results = make(map[string]RoomResults)
+
+func GetRoomResults(rid string) (RoomResults, error) {
+	_, err := GetRoom(rid)
+	if err != nil {
+		return RoomResults{}, err
+	}
+
+	resultsLock.Lock()
+	defer resultsLock.Unlock()
+
+	return copyResults(results[rid]), nil
+}
+
+func UpdateRoomScores(rid string, scores map[string]int) (RoomResults, error) {
+	return updateResults(rid, func(roomResults *RoomResults) {
+		for uid, score := range scores {
+			roomResults.Scores[uid] = score
+		}
+	})
+}
+
+func EliminatePlayer(rid string, uid string) (RoomResults, error) {
+	return updateResults(rid, func(roomResults *RoomResults) {
+		if !slices.Contains(roomResults.Eliminated, uid) {
+			roomResults.Eliminated = append(roomResults.Eliminated, uid)
+		}
+	})
+}
+
+func SetRoomWinners(rid string, winners []string) (RoomResults, error) {
+	return updateResults(rid, func(roomResults *RoomResults) {
+		roomResults.Winners = append([]string{}, winners...)
+	})
+}
+
+func ClearRoomResults(rid string) {
+	resultsLock.Lock()
+	defer resultsLock.Unlock()
+
+	delete(results, rid)
+}
+
+func updateResults(rid string, apply func(roomResults *RoomResults)) (RoomResults, error) {
+	_, err := GetRoom(rid)
+	if err != nil {
+		return RoomResults{}, err
+	}
+
+	resultsLock.Lock()
+	defer resultsLock.Unlock()
+
+	roomResults := copyResults(results[rid])
+	apply(&roomResults)
+	results[rid] = roomResults
+
+	return copyResults(roomResults), nil
+}
+
+func copyResults(roomResults RoomResults) RoomResults {
+	scores := make(map[string]int, len(roomResults.Scores))
+	for uid, score := range roomResults.Scores {
+		scores[uid] = score
+	}
+
+	return RoomResults{
+		Scores:     scores,
+		Eliminated: append([]string{}, roomResults.Eliminated...),
+		Winners:    append([]string{}, roomResults.Winners...),
+	}
+}
@@ -1,30 +1,84 @@
 package room
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
+	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 
 	gameclient "Engee-Server/gameClient"
+	"Engee-Server/gameClient/payload"
 	registry "Engee-Server/gameRegistry"
 	sErr "Engee-Server/stockErrors"
+	"Engee-Server/tracing"
 )
 
+const StatusCreated = "Created"
+const StatusRunning = "Running"
+const StatusFailed = "Failed"
+const StatusEnded = "Ended"
+
 type Room struct {
-	RID      string `json:"rid"`
-	Name     string `json:"name"`
-	GameMode string `json:"gamemode"`
-	Status   string `json:"status"`
-	Addr     string `json:"addr"`
+	RID        string    `json:"rid"`
+	Name       string    `json:"name"`
+	GameMode   string    `json:"gamemode"`
+	Version    string    `json:"version"`
+	Protocol   int       `json:"protocol"`
+	Status     string    `json:"status"`
+	Addr       string    `json:"addr,omitempty"`
+	Private    bool      `json:"private"`
+	MaxPlayers int       `json:"max_players"`
+	Owner      string    `json:"owner,omitempty"`
+	Created    time.Time `json:"created"`
+}
+
+// Public returns the room as players see it, without the address of the
+// game server hosting it.
+func (r Room) Public() Room {
+	r.Addr = ""
+	return r
 }
 
+var roomsLock sync.RWMutex
 var rooms = make(map[string]Room)
+var backend gameclient.GameBackend = gameclient.NewDefaultBackend()
+var playerLookup func(rid string) []payload.Player
+var callbackBaseURL string
+var maxRoomsPerOwner int
+
+func SetGameBackend(gameBackend gameclient.GameBackend) {
+	backend = gameBackend
+}
+
+func SetMaxRoomsPerOwner(max int) {
+	maxRoomsPerOwner = max
+}
+
+func SetPlayerLookup(lookup func(rid string) []payload.Player) {
+	playerLookup = lookup
+}
+
+func SetCallbackBaseURL(url string) {
+	callbackBaseURL = url
+}
+
+func CreateRoom(ctx context.Context, roomInfo []byte) (string, error) {
+	ctx, span := tracing.Start(ctx, "room.CreateRoom")
+	defer span.End()
+
+	err := requireAcceptingRooms()
+	if err != nil {
+		return "", err
+	}
 
-func CreateRoom(roomInfo []byte) (string, error) {
 	var newRoom Room
-	err := json.Unmarshal(roomInfo, &newRoom)
+	err = json.Unmarshal(roomInfo, &newRoom)
 	if err != nil {
 		return "", fmt.Errorf("could not unmarshal room info: %w", err)
 	}
@@ -35,28 +89,143 @@ func CreateRoom(roomInfo []byte) (string, error) {
 		}
 	}
 
+	if newRoom.MaxPlayers < 0 {
+		return "", &sErr.InvalidValueError[int]{
+			Field: "MaxPlayers",
+			Value: newRoom.MaxPlayers,
+		}
+	}
+
+	roomsLock.RLock()
+	err = checkOwnedRooms(newRoom.Owner)
+	roomsLock.RUnlock()
+	if err != nil {
+		return "", err
+	}
+
 	id := uuid.NewString()
+	span.SetAttribute("rid", id)
 
 	newRoom.RID = id
 
-	newRoom.Addr, err = registry.GetGamemodeURL(newRoom.GameMode)
+	mode, err := registry.ResolveGameMode(newRoom.GameMode, newRoom.Version)
 	if err != nil {
 		return "", fmt.Errorf("could not get get gamemode info: %w", err)
 	}
 
-	err = gameclient.CreateGameInstance(id, newRoom.Addr)
+	newRoom.Version = mode.Version
+	newRoom.Protocol = mode.Protocol
+	newRoom.Addr = mode.URL
+	newRoom.Status = StatusCreated
+	newRoom.Created = time.Now().UTC()
+
+	gameBackend := backend
+
+	tx := newTransaction("room creation")
+	tx.add("create game instance", func(ctx context.Context) error {
+		return gameBackend.CreateGameInstance(ctx, gameSetup(newRoom))
+	}, func(ctx context.Context) error {
+		return discardGameInstance(ctx, gameBackend, id, "room creation rolled back")
+	})
+	tx.add("store room", func(ctx context.Context) error {
+		return storeNewRoom(ctx, newRoom)
+	}, nil)
+
+	err = tx.run(ctx)
+	if err != nil {
+		return "", fmt.Errorf("could not create room: %w", err)
+	}
+
+	return id, nil
+}
+
+// checkOwnedRooms expects the caller to hold roomsLock. CreateRoom checks
+// before creating the game instance and again when storing the room, so
+// concurrent requests cannot take an owner past the limit.
+func checkOwnedRooms(owner string) error {
+	if owner == "" || maxRoomsPerOwner <= 0 {
+		return nil
+	}
+
+	count := 0
+	for _, room := range rooms {
+		if room.Owner == owner {
+			count++
+		}
+	}
+
+	if count >= maxRoomsPerOwner {
+		return &sErr.LimitExceededError{
+			Space:  "Rooms per user",
+			Reason: fmt.Sprintf("%s already owns %d rooms", owner, maxRoomsPerOwner),
+		}
+	}
+
+	return nil
+}
+
+func storeNewRoom(ctx context.Context, newRoom Room) error {
+	err := ctx.Err()
 	if err != nil {
-		return "", fmt.Errorf("could not create game instance: %w", err)
+		return err
 	}
 
-	newRoom.Status = "Created"
+	roomsLock.Lock()
+	defer roomsLock.Unlock()
 
-	rooms[id] = newRoom
+	err = checkOwnedRooms(newRoom.Owner)
+	if err != nil {
+		return err
+	}
 
-	return id, nil
+	return insertRoom(newRoom)
+}
+
+// insertRoom expects the caller to hold roomsLock.
+func insertRoom(newRoom Room) error {
+	_, found := rooms[newRoom.RID]
+	if found {
+		return &sErr.MatchFoundError[string]{
+			Space: "Rooms",
+			Field: "RID",
+			Value: newRoom.RID,
+		}
+	}
+
+	rooms[newRoom.RID] = newRoom
+	return nil
 }
 
 func GetRoom(rid string) (Room, error) {
+	roomsLock.RLock()
+	defer roomsLock.RUnlock()
+
+	return findRoom(rid)
+}
+
+// updateRoom applies change to the stored room while holding the rooms lock,
+// so concurrent updates to its other fields are not lost. change must not
+// call back into the game server or publish events.
+func updateRoom(rid string, change func(room *Room) error) (Room, error) {
+	roomsLock.Lock()
+	defer roomsLock.Unlock()
+
+	room, err := findRoom(rid)
+	if err != nil {
+		return Room{}, err
+	}
+
+	err = change(&room)
+	if err != nil {
+		return Room{}, err
+	}
+
+	rooms[rid] = room
+	return room, nil
+}
+
+// findRoom expects the caller to hold roomsLock.
+func findRoom(rid string) (Room, error) {
 	if rid == "" {
 		return Room{}, &sErr.EmptyValueError{
 			Field: "RID",
@@ -76,7 +245,20 @@ func GetRoom(rid string) (Room, error) {
 }
 
 func GetRooms() []Room {
-	return maps.Values(rooms)
+	roomsLock.RLock()
+	sorted := maps.Values(rooms)
+	roomsLock.RUnlock()
+
+	slices.SortFunc(sorted, compareRoomsCreated)
+
+	return sorted
+}
+
+func CountRooms() int {
+	roomsLock.RLock()
+	defer roomsLock.RUnlock()
+
+	return len(rooms)
 }
 
 func GetRoomURL(rid string) (string, error) {
@@ -94,6 +276,20 @@ func GetRoomURL(rid string) (string, error) {
 	return room.Addr, nil
 }
 
+func GetRoomGameInstance(rid string) (gameclient.GameInstance, error) {
+	_, err := GetRoom(rid)
+	if err != nil {
+		return gameclient.GameInstance{}, err
+	}
+
+	game, err := backend.GetGameInstance(rid)
+	if err != nil {
+		return gameclient.GameInstance{}, fmt.Errorf("could not get game instance: %w", err)
+	}
+
+	return game, nil
+}
+
 func UpdateRoomName(rid string, name string) error {
 	if name == "" {
 		return &sErr.EmptyValueError{
@@ -101,15 +297,12 @@ func UpdateRoomName(rid string, name string) error {
 		}
 	}
 
-	room, err := GetRoom(rid)
-	if err != nil {
-		return err
-	}
+	_, err := updateRoom(rid, func(room *Room) error {
+		room.Name = name
+		return nil
+	})
 
-	room.Name = name
-	rooms[rid] = room
-
-	return nil
+	return err
 }
 
 func UpdateRoomStatus(rid string, status string) error {
@@ -119,69 +312,345 @@ func UpdateRoomStatus(rid string, status string) error {
 		}
 	}
 
+	_, err := setRoomStatus(rid, status)
+
+	return err
+}
+
+func UpdateRoomGameMode(ctx context.Context, rid string, roomGameMode string, version string) error {
+	ctx, span := tracing.Start(ctx, "room.UpdateRoomGameMode")
+	defer span.End()
+	span.SetAttribute("rid", rid)
+
+	if roomGameMode == "" {
+		return &sErr.EmptyValueError{
+			Field: "Gamemode",
+		}
+	}
+
 	room, err := GetRoom(rid)
 	if err != nil {
 		return err
 	}
 
-	room.Status = status
-	rooms[rid] = room
+	mode, err := registry.ResolveGameMode(roomGameMode, version)
+	if err != nil {
+		return fmt.Errorf("could not get gamemode from registry: %w", err)
+	}
+
+	current := registry.GameMode{
+		Name:     room.GameMode,
+		Version:  room.Version,
+		Protocol: room.Protocol,
+	}
+
+	compatible := registry.CheckCompatibility(current, mode)
+
+	if room.Status == StatusRunning {
+		if compatible != nil {
+			return fmt.Errorf("cannot change gamemode while game is running: %w", compatible)
+		}
+
+		return &sErr.InvalidStateError{
+			Space:  "Room " + rid,
+			State:  "running",
+			Action: "change gamemode",
+		}
+	}
+
+	switched := room
+	switched.GameMode = mode.Name
+	switched.Version = mode.Version
+	switched.Protocol = mode.Protocol
+	switched.Addr = mode.URL
+
+	switched.Status = StatusCreated
+
+	err = switchGameInstance(ctx, room, switched, compatible == nil)
+	if err != nil {
+		return err
+	}
+
+	InvalidateRoomGameState(rid)
+
+	PublishRoomEvent(RoomEvent{
+		RID:     rid,
+		Type:    EventGameModeChanged,
+		Message: fmt.Sprintf("gamemode changed from %s@%s to %s@%s", room.GameMode, room.Version, switched.GameMode, switched.Version),
+		Data:    switched.Public(),
+	})
 
 	return nil
 }
 
-func UpdateRoomGameMode(rid string, roomGameMode string) error {
-	if roomGameMode == "" {
-		return &sErr.EmptyValueError{
-			Field: "Gamemode",
+func switchGameInstance(ctx context.Context, room Room, switched Room, keepRules bool) error {
+	gameBackend := backend
+
+	old, err := gameBackend.GetGameInstance(room.RID)
+	hadInstance := err == nil
+
+	setup := gameSetup(switched)
+	if hadInstance && keepRules {
+		setup.Rules = old.Rules
+	}
+
+	tx := newTransaction("gamemode switch")
+	tx.add("end old game instance", func(ctx context.Context) error {
+		return endGameInstance(ctx, gameBackend, room.RID)
+	}, func(ctx context.Context) error {
+		if !hadInstance {
+			return nil
+		}
+
+		err := gameBackend.CreateGameInstance(ctx, setupFromInstance(old))
+		if err != nil {
+			markRoomFailed(room, "gamemode switch failed and the previous game could not be restored")
 		}
+
+		return err
+	})
+	tx.add("create new game instance", func(ctx context.Context) error {
+		return gameBackend.CreateGameInstance(ctx, setup)
+	}, func(ctx context.Context) error {
+		return discardGameInstance(ctx, gameBackend, room.RID, "gamemode switch rolled back")
+	})
+	tx.add("store room", func(ctx context.Context) error {
+		return storeRoom(ctx, switched)
+	}, nil)
+
+	err = tx.run(ctx)
+	if err != nil {
+		return fmt.Errorf("could not switch gamemode: %w", err)
 	}
 
-	room, err := GetRoom(rid)
+	return nil
+}
+
+func setRoomStatus(rid string, status string) (Room, error) {
+	return updateRoom(rid, func(room *Room) error {
+		room.Status = status
+		return nil
+	})
+}
+
+// storeRoom saves the gamemode and status of a room whose game instance was
+// switched, leaving fields changed in the meantime untouched.
+func storeRoom(ctx context.Context, room Room) error {
+	err := ctx.Err()
 	if err != nil {
 		return err
 	}
 
-	room.GameMode = roomGameMode
+	_, err = updateRoom(room.RID, func(stored *Room) error {
+		stored.GameMode = room.GameMode
+		stored.Version = room.Version
+		stored.Protocol = room.Protocol
+		stored.Addr = room.Addr
+		stored.Status = room.Status
+		return nil
+	})
 
-	room.Addr, err = registry.GetGamemodeURL(roomGameMode)
+	return err
+}
+
+func markRoomFailed(room Room, message string) {
+	_, err := setRoomStatus(room.RID, StatusFailed)
 	if err != nil {
-		return fmt.Errorf("could not get gamemode url from registry: %w", err)
+		return
+	}
+
+	PublishRoomEvent(RoomEvent{
+		RID:     room.RID,
+		Type:    EventGameFailed,
+		Message: message,
+	})
+}
+
+func SetRoomRules(ctx context.Context, rid string, rulesInfo []byte) error {
+	ctx, span := tracing.Start(ctx, "room.SetRoomRules")
+	defer span.End()
+	span.SetAttribute("rid", rid)
+
+	_, err := GetRoom(rid)
+	if err != nil {
+		return err
+	}
+
+	rules, err := parseRules(rulesInfo)
+	if err != nil {
+		return err
+	}
+
+	err = backend.SetGameRules(ctx, rid, rules)
+	if err != nil {
+		return fmt.Errorf("could not set game rules: %w", err)
 	}
 
-	rooms[rid] = room
 	return nil
 }
 
-func InitializeRoomGame(rid string) error {
+func InitializeRoomGame(ctx context.Context, rid string) error {
+	ctx, span := tracing.Start(ctx, "room.InitializeRoomGame")
+	defer span.End()
+	span.SetAttribute("rid", rid)
+
 	room, err := GetRoom(rid)
 	if err != nil {
 		return err
 	}
 
-	err = gameclient.CreateGameInstance(rid, room.Addr)
+	err = backend.CreateGameInstance(ctx, gameSetup(room))
 	if err != nil {
 		return fmt.Errorf("could not creat game instance: %w", err)
 	}
 
-	room.Status = "Created"
-	rooms[rid] = room
+	_, err = setRoomStatus(rid, StatusCreated)
 
-	return nil
+	return err
 }
 
-func DeleteRoom(rid string) error {
+func EndRoomGame(ctx context.Context, rid string) error {
+	ctx, span := tracing.Start(ctx, "room.EndRoomGame")
+	defer span.End()
+	span.SetAttribute("rid", rid)
+
 	_, err := GetRoom(rid)
 	if err != nil {
 		return err
 	}
 
-	err = gameclient.EndGame(rid)
+	err = endGameInstance(ctx, backend, rid)
 	if err != nil {
-		return fmt.Errorf("could not end game: %w", err)
+		return fmt.Errorf("could not end game instance: %w", err)
+	}
+
+	InvalidateRoomGameState(rid)
+
+	_, err = setRoomStatus(rid, StatusEnded)
+
+	return err
+}
+
+func DeleteRoom(ctx context.Context, rid string) error {
+	ctx, span := tracing.Start(ctx, "room.DeleteRoom")
+	defer span.End()
+	span.SetAttribute("rid", rid)
+
+	_, err := GetRoom(rid)
+	if err != nil {
+		return err
 	}
 
+	discardGameInstance(ctx, backend, rid, "room deleted")
+
+	roomsLock.Lock()
 	delete(rooms, rid)
+	roomsLock.Unlock()
+
+	ClearRoomResults(rid)
+	InvalidateRoomGameState(rid)
+	closeRoomEvents(rid)
 
 	return nil
 }
+
+func gameSetup(room Room) gameclient.GameSetup {
+	setup := gameclient.GameSetup{
+		RID:      room.RID,
+		RoomName: room.Name,
+		GameMode: room.GameMode,
+		Addr:     room.Addr,
+		Players:  make([]payload.Player, 0),
+	}
+
+	if playerLookup != nil {
+		setup.Players = playerLookup(room.RID)
+	}
+
+	if callbackBaseURL != "" {
+		setup.CallbackURL = callbackBaseURL + "/games/" + room.RID + "/events"
+	}
+
+	return setup
+}
+
+func setupFromInstance(game gameclient.GameInstance) gameclient.GameSetup {
+	return gameclient.GameSetup{
+		RID:         game.RID,
+		RoomName:    game.RoomName,
+		GameMode:    game.GameMode,
+		Addr:        game.Addr,
+		Players:     game.Players,
+		Rules:       game.Rules,
+		CallbackURL: game.CallbackURL,
+	}
+}
+
+func parseRules(rulesInfo []byte) (payload.Rules, error) {
+	var fields map[string]json.RawMessage
+	err := json.Unmarshal(rulesInfo, &fields)
+	if err != nil || fields["rules"] == nil {
+		return payload.Rules{Rules: string(rulesInfo)}, nil
+	}
+
+	var rules payload.Rules
+	err = json.Unmarshal(rulesInfo, &rules)
+	if err != nil {
+		return payload.Rules{}, fmt.Errorf("could not unmarshal rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+func GetRoomsOnGameServer(mode registry.GameMode) []Room {
+	roomsLock.RLock()
+	defer roomsLock.RUnlock()
+
+	hosted := make([]Room, 0)
+	for _, room := range rooms {
+		if room.GameMode == mode.Name && room.Version == mode.Version && room.Addr == mode.URL {
+			hosted = append(hosted, room)
+		}
+	}
+
+	return hosted
+}
+
+func ReconcileGameServer(mode registry.GameMode) {
+	ctx := context.Background()
+	hosted := GetRoomsOnGameServer(mode)
+	slog.Info("Gamemode restarted, reconciling rooms", "gamemode", mode.Name, "version", mode.Version, "url", mode.URL, "rooms", len(hosted))
+
+	for _, room := range hosted {
+		err := backend.RecreateGameInstance(ctx, room.RID)
+		if err != nil {
+			slog.ErrorContext(ctx, "Recreating game instance", "rid", room.RID, "error", err)
+
+			_, err = setRoomStatus(room.RID, StatusFailed)
+			if err != nil {
+				continue
+			}
+
+			PublishRoomEvent(RoomEvent{
+				RID:     room.RID,
+				Type:    EventGameFailed,
+				Message: "game server restarted and the game could not be recreated",
+			})
+
+			continue
+		}
+
+		_, err = setRoomStatus(room.RID, StatusCreated)
+		if err != nil {
+			continue
+		}
+
+		InvalidateRoomGameState(room.RID)
+
+		PublishRoomEvent(RoomEvent{
+			RID:     room.RID,
+			Type:    EventGameRecreated,
+			Message: "game server restarted and the game was recreated",
+		})
+	}
+}
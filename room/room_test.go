@@ -1,23 +1,30 @@
 package room
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"log"
 	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 
 	gameclient "Engee-Server/gameClient"
+	"Engee-Server/gameClient/payload"
 	reg "Engee-Server/gameRegistry"
 	sErr "Engee-Server/stockErrors"
-	"Engee-Server/testDummy"
+	"Engee-Server/utils"
 )
 
 var randomID = uuid.NewString()
 
+var fakeBackend = gameclient.NewFakeBackend()
+
 const testRoomName = "Test-Room"
 const altRoomName = "Alt-Room"
 
@@ -30,6 +37,7 @@ const testConPort = "8091"
 const altConPort = "8092"
 const testConURL = "http://localhost:" + testConPort
 const altConURL = "http://localhost:" + altConPort
+const migrationURL = "http://localhost:8093"
 
 var testRoom = Room{
 	RID:      "",
@@ -59,7 +67,7 @@ func TestMain(m *testing.M) {
 }
 
 func TestCreateRoom(t *testing.T) {
-	id, err := CreateRoom(testRoomJSON)
+	id, err := CreateRoom(context.Background(), testRoomJSON)
 	if id == "" || err != nil {
 		t.Fatalf(`CreateRoom(Valid) = %q, %v, want "uuid", nil`, id, err)
 	}
@@ -67,10 +75,57 @@ func TestCreateRoom(t *testing.T) {
 	t.Cleanup(cleanUpAfterTest)
 }
 
+func TestCreateRoomOwnerLimit(t *testing.T) {
+	SetMaxRoomsPerOwner(1)
+	t.Cleanup(cleanUpAfterTest)
+
+	owned := testRoom
+	owned.Owner = "owner"
+	ownedJSON, _ := json.Marshal(owned)
+
+	_, err := CreateRoom(context.Background(), ownedJSON)
+	if err != nil {
+		t.Fatalf(`CreateRoom(FirstOwned) = %v, want nil`, err)
+	}
+
+	id, err := CreateRoom(context.Background(), ownedJSON)
+	if id != "" || !errors.As(err, &sErr.LE_ERR) {
+		t.Fatalf(`CreateRoom(OverOwnerLimit) = %q, %v, want "", LimitExceededError`, id, err)
+	}
+
+	_, err = CreateRoom(context.Background(), testRoomJSON)
+	if err != nil {
+		t.Fatalf(`CreateRoom(Unowned) = %v, want nil`, err)
+	}
+}
+
+func TestCreateRoomOwnerLimitConcurrent(t *testing.T) {
+	SetMaxRoomsPerOwner(1)
+	t.Cleanup(cleanUpAfterTest)
+
+	owned := testRoom
+	owned.Owner = "owner"
+	ownedJSON, _ := json.Marshal(owned)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			CreateRoom(context.Background(), ownedJSON)
+		}()
+	}
+	wg.Wait()
+
+	if CountRooms() != 1 {
+		t.Fatalf(`CreateRoom(ConcurrentOwned) stored %d rooms, want 1`, CountRooms())
+	}
+}
+
 func TestCreateUniqueNameRooms(t *testing.T) {
-	CreateRoom(testRoomJSON)
+	CreateRoom(context.Background(), testRoomJSON)
 
-	id, err := CreateRoom(testRoomJSON)
+	id, err := CreateRoom(context.Background(), testRoomJSON)
 
 	if id == "" || err != nil {
 		t.Fatalf(`CreateRoom(Unique Name) = %q, %v, want "uuid", nil`, id, err)
@@ -80,8 +135,8 @@ func TestCreateUniqueNameRooms(t *testing.T) {
 }
 
 func TestCreateSameNameRooms(t *testing.T) {
-	CreateRoom(testRoomJSON)
-	id, err := CreateRoom(testRoomJSON)
+	CreateRoom(context.Background(), testRoomJSON)
+	id, err := CreateRoom(context.Background(), testRoomJSON)
 	if id == "" || err != nil {
 		t.Fatalf(`CreateRoom(Same Name) = %q, %v, want "uuid", nil`, id, err)
 	}
@@ -95,7 +150,7 @@ func TestCreateRoomNoName(t *testing.T) {
 		GameMode: "None",
 	})
 
-	id, err := CreateRoom(namelessRoom)
+	id, err := CreateRoom(context.Background(), namelessRoom)
 
 	if id != "" || !errors.As(err, &sErr.EV_ERR) {
 		t.Fatalf(`CreateRoom(EmptyName) = %q, %v, want "", EmptyValueError`, id, err)
@@ -175,6 +230,74 @@ func TestGetRoomsEmpty(t *testing.T) {
 	}
 }
 
+func TestListRooms(t *testing.T) {
+	setupRoomTest(t)
+	altID, _ := setupAltRoomTest()
+
+	rooms, next, err := ListRooms(RoomFilter{GameMode: altGameMode}, utils.PageRequest{})
+	if len(rooms) != 1 || rooms[0].RID != altID || next != "" || err != nil {
+		t.Fatalf(`ListRooms(GameMode) = %v, %q, %v, want [alt room], "", nil`, rooms, next, err)
+	}
+}
+
+func TestListRoomsPaginated(t *testing.T) {
+	setupRoomTest(t)
+	setupAltRoomTest()
+
+	first, next, err := ListRooms(RoomFilter{}, utils.PageRequest{Limit: 1})
+	if len(first) != 1 || next == "" || err != nil {
+		t.Fatalf(`ListRooms(FirstPage) = %v, %q, %v, want [room], cursor, nil`, first, next, err)
+	}
+
+	second, next, err := ListRooms(RoomFilter{}, utils.PageRequest{Limit: 1, Cursor: next})
+	if len(second) != 1 || second[0].RID == first[0].RID || next != "" || err != nil {
+		t.Fatalf(`ListRooms(LastPage) = %v, %q, %v, want [other room], "", nil`, second, next, err)
+	}
+}
+
+func TestListRoomsHasSpace(t *testing.T) {
+	SetPlayerLookup(func(rid string) []payload.Player {
+		return []payload.Player{{UID: randomID}}
+	})
+	t.Cleanup(func() { SetPlayerLookup(nil) })
+
+	setupRoomTest(t)
+
+	fullRoom := testRoom
+	fullRoom.MaxPlayers = 1
+	fullRoom.Private = true
+	fullRoomJSON, _ := json.Marshal(fullRoom)
+	fullID, _ := CreateRoom(context.Background(), fullRoomJSON)
+
+	hasSpace := false
+	private := true
+	rooms, _, err := ListRooms(RoomFilter{HasSpace: &hasSpace, Private: &private}, utils.PageRequest{Sort: SortPlayers})
+	if len(rooms) != 1 || rooms[0].RID != fullID || err != nil {
+		t.Fatalf(`ListRooms(HasSpace) = %v, %v, want [full room], nil`, rooms, err)
+	}
+}
+
+func TestListRoomsInvalidSort(t *testing.T) {
+	setupRoomTest(t)
+
+	_, _, err := ListRooms(RoomFilter{}, utils.PageRequest{Sort: "name"})
+	if !errors.As(err, &sErr.IV_ERR) {
+		t.Fatalf(`ListRooms(InvalidSort) = %v, want InvalidValueError`, err)
+	}
+}
+
+func TestListRoomsInvalidCursor(t *testing.T) {
+	setupRoomTest(t)
+	setupAltRoomTest()
+
+	_, next, _ := ListRooms(RoomFilter{}, utils.PageRequest{Limit: 1})
+
+	_, _, err := ListRooms(RoomFilter{}, utils.PageRequest{Limit: 1, Cursor: next, Descending: true})
+	if !errors.As(err, &sErr.IV_ERR) {
+		t.Fatalf(`ListRooms(MismatchedCursor) = %v, want InvalidValueError`, err)
+	}
+}
+
 func TestUpdateRoomName(t *testing.T) {
 	id, trInstance := setupRoomTest(t)
 
@@ -281,7 +404,7 @@ func TestUpdateRoomGameMode(t *testing.T) {
 	trInstance.GameMode = altGameMode
 	trInstance.Addr = altConURL
 
-	err := UpdateRoomGameMode(id, altGameMode)
+	err := UpdateRoomGameMode(context.Background(), id, altGameMode, "")
 	if err != nil {
 		t.Fatalf(`UpdateRoomGameMode(Valid) = %v, want nil`, err)
 	}
@@ -309,7 +432,7 @@ func TestUpdateRoomGameModeInvalidID(t *testing.T) {
 	trInstance.Status = "Created"
 	trInstance.Addr = testConURL
 
-	err := UpdateRoomGameMode(randomID, testGameMode)
+	err := UpdateRoomGameMode(context.Background(), randomID, testGameMode, "")
 	if !errors.As(err, &sErr.MNF_ERR) {
 		t.Fatalf(`UpdateRoomGameMode(InvalidID) = %v, want MatchNotFoundError`, err)
 	}
@@ -317,10 +440,418 @@ func TestUpdateRoomGameModeInvalidID(t *testing.T) {
 	checkExpectedRoomData(t, id, trInstance)
 }
 
+func TestUpdateRoomGameModeSwitchesInstance(t *testing.T) {
+	id, _ := setupRoomTest(t)
+	events, _ := SubscribeToRoom(id)
+
+	err := UpdateRoomGameMode(context.Background(), id, altGameMode, "")
+	if err != nil {
+		t.Fatalf(`UpdateRoomGameMode(Switch) = %v, want nil`, err)
+	}
+
+	game, err := fakeBackend.GetGameInstance(id)
+	if err != nil || game.GameMode != altGameMode || game.Addr != altConURL {
+		t.Fatalf(`UpdateRoomGameMode(Switch) instance = %v, %v, want %s at %s`, game, err, altGameMode, altConURL)
+	}
+
+	event := confirmRoomEvent(t, events, EventGameModeChanged)
+	switched, _ := event.Data.(Room)
+	if switched.GameMode != altGameMode || switched.Addr != "" {
+		t.Fatalf(`RoomEvent(GameModeChanged) data = %v, want %s without its address`, event.Data, altGameMode)
+	}
+}
+
+func TestUpdateRoomGameModeKeepsCompatibleRules(t *testing.T) {
+	id := setupVersionedRoomTest(t)
+	SetRoomRules(context.Background(), id, []byte("compatible rules"))
+
+	UpdateRoomGameMode(context.Background(), id, testGameMode, "1.1.0")
+
+	game, _ := fakeBackend.GetGameInstance(id)
+	if game.Rules.Rules != "compatible rules" {
+		t.Fatalf(`UpdateRoomGameMode(Compatible) rules = %q, want "compatible rules"`, game.Rules.Rules)
+	}
+
+	UpdateRoomGameMode(context.Background(), id, altGameMode, "")
+
+	game, _ = fakeBackend.GetGameInstance(id)
+	if game.Rules.Rules != "" {
+		t.Fatalf(`UpdateRoomGameMode(OtherMode) rules = %q, want ""`, game.Rules.Rules)
+	}
+}
+
+func TestUpdateRoomGameModeRollback(t *testing.T) {
+	failing := setupAddrFailingBackend(t, altConURL)
+
+	id, _ := CreateRoom(context.Background(), testRoomJSON)
+	expected, _ := GetRoom(id)
+
+	err := UpdateRoomGameMode(context.Background(), id, altGameMode, "")
+	if err == nil {
+		t.Fatalf(`UpdateRoomGameMode(Unreachable) = nil, want error`)
+	}
+
+	checkExpectedRoomData(t, id, expected)
+
+	game, err := failing.GetGameInstance(id)
+	if err != nil || game.Addr != testConURL {
+		t.Fatalf(`UpdateRoomGameMode(Unreachable) instance = %v, %v, want instance at %s`, game, err, testConURL)
+	}
+}
+
+func TestUpdateRoomGameModeRunningMoved(t *testing.T) {
+	id := setupRunningVersionTest(t)
+
+	reg.RegisterGameModeInstance(reg.GameMode{Name: testGameMode, Version: "1.2.0", URL: altConURL})
+	t.Cleanup(func() {
+		reg.RemoveGameModeInstance(testGameMode, "1.2.0", "")
+	})
+
+	err := UpdateRoomGameMode(context.Background(), id, testGameMode, "1.2.0")
+	if err == nil {
+		t.Fatalf(`UpdateRoomGameMode(RunningMoved) = nil, want error`)
+	}
+}
+
+func TestMigrateRoom(t *testing.T) {
+	id, _ := setupMigrationTest(t)
+	events, _ := SubscribeToRoom(id)
+
+	migrated, err := MigrateRoom(context.Background(), id, "", true)
+	if err != nil || migrated.Addr != migrationURL {
+		t.Fatalf(`MigrateRoom(Valid) = %v, %v, want room at %s, nil`, migrated, err, migrationURL)
+	}
+
+	game, _ := fakeBackend.GetFakeGame(id)
+	if game.Addr != migrationURL || len(game.Snapshot) == 0 {
+		t.Fatalf(`MigrateRoom(Valid) instance = %v, want instance at %s with snapshot`, game, migrationURL)
+	}
+
+	event := confirmRoomEvent(t, events, EventGameMigrated)
+	data, _ := event.Data.(Room)
+	if data.RID != id || data.Addr != "" || strings.Contains(event.Message, migrationURL) {
+		t.Fatalf(`RoomEvent(GameMigrated) = %v, want room %s without its address`, event, id)
+	}
+}
+
+func TestMigrateRoomRunningWithoutState(t *testing.T) {
+	id, _ := setupMigrationTest(t)
+	UpdateRoomStatus(id, StatusRunning)
+
+	migrated, err := MigrateRoom(context.Background(), id, migrationURL, false)
+	if err != nil || migrated.Status != StatusCreated {
+		t.Fatalf(`MigrateRoom(RunningWithoutState) = %v, %v, want %q room, nil`, migrated, err, StatusCreated)
+	}
+}
+
+func TestMigrateRoomRunningWithState(t *testing.T) {
+	id, _ := setupMigrationTest(t)
+	UpdateRoomStatus(id, StatusRunning)
+
+	migrated, err := MigrateRoom(context.Background(), id, migrationURL, true)
+	if err != nil || migrated.Status != StatusRunning {
+		t.Fatalf(`MigrateRoom(RunningWithState) = %v, %v, want %q room, nil`, migrated, err, StatusRunning)
+	}
+}
+
+func TestMigrateRoomOldUnreachable(t *testing.T) {
+	id, _ := setupMigrationTest(t)
+	fakeBackend.SetFailure("EndGame", errors.New("game server unreachable"))
+
+	_, err := MigrateRoom(context.Background(), id, migrationURL, false)
+	if err != nil {
+		t.Fatalf(`MigrateRoom(OldUnreachable) = %v, want nil`, err)
+	}
+
+	orphaned := GetOrphanedGames()
+	if len(orphaned) != 1 || orphaned[0].Game.Addr != testConURL {
+		t.Fatalf(`MigrateRoom(OldUnreachable) orphans = %v, want old instance at %s`, orphaned, testConURL)
+	}
+}
+
+func TestMigrateRoomNoTarget(t *testing.T) {
+	id, expected := setupRoomTest(t)
+	expected.Status = StatusCreated
+	expected.Addr = testConURL
+
+	_, err := MigrateRoom(context.Background(), id, "", false)
+	if !errors.As(err, &sErr.UA_ERR) {
+		t.Fatalf(`MigrateRoom(NoTarget) = %v, want UnavailableError`, err)
+	}
+
+	checkExpectedRoomData(t, id, expected)
+}
+
+func TestMigrateRoomInvalidID(t *testing.T) {
+	_, err := MigrateRoom(context.Background(), randomID, "", false)
+	if !errors.As(err, &sErr.MNF_ERR) {
+		t.Fatalf(`MigrateRoom(InvalidID) = %v, want MatchNotFoundError`, err)
+	}
+}
+
+func TestCreateRoomPinnedVersion(t *testing.T) {
+	setupVersionTest(t)
+
+	versioned := testRoom
+	versioned.Version = "1.0.0"
+	versionedJSON, _ := json.Marshal(versioned)
+
+	id, err := CreateRoom(context.Background(), versionedJSON)
+	if err != nil {
+		t.Fatalf(`CreateRoom(PinnedVersion) = %q, %v, want "uuid", nil`, id, err)
+	}
+
+	room, _ := GetRoom(id)
+	if room.Version != "1.0.0" {
+		t.Fatalf(`CreateRoom(PinnedVersion) pinned %q, want "1.0.0"`, room.Version)
+	}
+}
+
+func TestCreateRoomLatestVersion(t *testing.T) {
+	setupVersionTest(t)
+
+	id, err := CreateRoom(context.Background(), testRoomJSON)
+	if err != nil {
+		t.Fatalf(`CreateRoom(LatestVersion) = %q, %v, want "uuid", nil`, id, err)
+	}
+
+	room, _ := GetRoom(id)
+	if room.Version != "2.0.0" {
+		t.Fatalf(`CreateRoom(LatestVersion) pinned %q, want "2.0.0"`, room.Version)
+	}
+}
+
+func TestUpdateRoomGameModeRunningCompatible(t *testing.T) {
+	id := setupRunningVersionTest(t)
+
+	err := UpdateRoomGameMode(context.Background(), id, testGameMode, "1.1.0")
+	if !errors.As(err, &sErr.IS_ERR) {
+		t.Fatalf(`UpdateRoomGameMode(RunningCompatible) = %v, want InvalidStateError`, err)
+	}
+
+	room, _ := GetRoom(id)
+	if room.Version != "1.0.0" || room.Status != StatusRunning {
+		t.Fatalf(`UpdateRoomGameMode(RunningCompatible) room = %v, want running room left at 1.0.0`, room)
+	}
+}
+
+func TestUpdateRoomGameModeRunningIncompatible(t *testing.T) {
+	id := setupRunningVersionTest(t)
+
+	err := UpdateRoomGameMode(context.Background(), id, testGameMode, "2.0.0")
+	if !errors.As(err, &sErr.IC_ERR) {
+		t.Fatalf(`UpdateRoomGameMode(RunningIncompatible) = %v, want IncompatibleVersionError`, err)
+	}
+}
+
+func TestUpdateRoomGameModeRunningOtherMode(t *testing.T) {
+	id := setupRunningVersionTest(t)
+
+	err := UpdateRoomGameMode(context.Background(), id, altGameMode, "")
+	if !errors.As(err, &sErr.IC_ERR) {
+		t.Fatalf(`UpdateRoomGameMode(RunningOtherMode) = %v, want IncompatibleVersionError`, err)
+	}
+}
+
+func TestReconcileGameServer(t *testing.T) {
+	id, _ := setupRoomTest(t)
+
+	events, _ := SubscribeToRoom(id)
+	mode, _ := reg.ResolveGameMode(testGameMode, "")
+
+	ReconcileGameServer(mode)
+
+	room, _ := GetRoom(id)
+	if room.Status != StatusCreated {
+		t.Fatalf(`ReconcileGameServer(Valid) status = %q, want %q`, room.Status, StatusCreated)
+	}
+
+	confirmRoomEvent(t, events, EventGameRecreated)
+}
+
+func TestReconcileGameServerUnreachable(t *testing.T) {
+	id, _ := setupRoomTest(t)
+
+	events, _ := SubscribeToRoom(id)
+	mode, _ := reg.ResolveGameMode(testGameMode, "")
+
+	fakeBackend.SetFailure("RecreateGameInstance", errors.New("game server unreachable"))
+	ReconcileGameServer(mode)
+
+	room, _ := GetRoom(id)
+	if room.Status != StatusFailed {
+		t.Fatalf(`ReconcileGameServer(Unreachable) status = %q, want %q`, room.Status, StatusFailed)
+	}
+
+	confirmRoomEvent(t, events, EventGameFailed)
+}
+
+func TestReconcileGameServerConcurrentRename(t *testing.T) {
+	id, _ := setupRoomTest(t)
+	mode, _ := reg.ResolveGameMode(testGameMode, "")
+
+	done := make(chan struct{})
+	go func() {
+		ReconcileGameServer(mode)
+		close(done)
+	}()
+
+	err := UpdateRoomName(id, "Renamed")
+	<-done
+
+	room, _ := GetRoom(id)
+	if err != nil || room.Name != "Renamed" || room.Status != StatusCreated {
+		t.Fatalf(`ReconcileGameServer(ConcurrentRename) = %v, %v, want renamed room in %q`, room, err, StatusCreated)
+	}
+}
+
+func TestGetRoomGameState(t *testing.T) {
+	id, _ := setupRoomTest(t)
+	fakeBackend.SetFakeScores(id, map[string]int{randomID: 3})
+
+	state, err := GetRoomGameState(context.Background(), id)
+	if state.Phase != gameclient.GameStateCreated || state.Scores[randomID] != 3 || err != nil {
+		t.Fatalf(`GetRoomGameState(Valid) = %v, %v, want Created state with scores, nil`, state, err)
+	}
+}
+
+func TestGetRoomGameStateCached(t *testing.T) {
+	id, _ := setupRoomTest(t)
+
+	GetRoomGameState(context.Background(), id)
+	fakeBackend.StartGame(context.Background(), id)
+
+	state, _ := GetRoomGameState(context.Background(), id)
+	game, _ := fakeBackend.GetFakeGame(id)
+	if state.Phase != gameclient.GameStateCreated || game.StateQueries != 1 {
+		t.Fatalf(`GetRoomGameState(Cached) = %v after %d queries, want cached Created state after 1 query`, state, game.StateQueries)
+	}
+}
+
+func TestGetRoomGameStateInvalidated(t *testing.T) {
+	id, _ := setupRoomTest(t)
+
+	GetRoomGameState(context.Background(), id)
+	fakeBackend.StartGame(context.Background(), id)
+	InvalidateRoomGameState(id)
+
+	state, _ := GetRoomGameState(context.Background(), id)
+	if state.Phase != gameclient.GameStateRunning {
+		t.Fatalf(`GetRoomGameState(Invalidated) = %v, want Running state`, state)
+	}
+}
+
+func TestGetRoomGameStateErrorCached(t *testing.T) {
+	id, _ := setupRoomTest(t)
+
+	fakeBackend.SetFailure("GetGameState", errors.New("game server unreachable"))
+	GetRoomGameState(context.Background(), id)
+	fakeBackend.SetFailure("GetGameState", nil)
+
+	_, err := GetRoomGameState(context.Background(), id)
+	if err == nil {
+		t.Fatalf(`GetRoomGameState(ErrorCached) = nil, want cached error`)
+	}
+
+	cached := roomGameStateCache(id)
+	cached.lock.Lock()
+	cached.fetchedAt = time.Now().Add(-gameStateErrorTTL)
+	cached.lock.Unlock()
+
+	_, err = GetRoomGameState(context.Background(), id)
+	if err != nil {
+		t.Fatalf(`GetRoomGameState(ErrorExpired) = %v, want nil`, err)
+	}
+}
+
+func TestGetRoomGameStateSharedFetch(t *testing.T) {
+	id, _ := setupRoomTest(t)
+
+	slow := &slowStateBackend{FakeBackend: fakeBackend, release: make(chan struct{})}
+	SetGameBackend(slow)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := GetRoomGameState(context.Background(), id)
+			errs <- err
+		}()
+	}
+
+	for deadline := time.Now().Add(time.Second); slow.calls.Load() == 0 && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	abandoned := make(chan error, 1)
+	go func() {
+		_, err := GetRoomGameState(ctx, id)
+		abandoned <- err
+	}()
+
+	select {
+	case err := <-abandoned:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf(`GetRoomGameState(Cancelled) = %v, want context.Canceled`, err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf(`GetRoomGameState(Cancelled) blocked behind the fetch, want context.Canceled`)
+	}
+
+	close(slow.release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf(`GetRoomGameState(Shared) = %v, want nil`, err)
+		}
+	}
+
+	if slow.calls.Load() != 1 {
+		t.Fatalf(`GetRoomGameState(Shared) queried the game server %d times, want 1`, slow.calls.Load())
+	}
+}
+
+func TestGetRoomGameStateInvalidID(t *testing.T) {
+	setupRoomTest(t)
+
+	_, err := GetRoomGameState(context.Background(), randomID)
+	if !errors.As(err, &sErr.MNF_ERR) {
+		t.Fatalf(`GetRoomGameState(InvalidID) = %v, want MatchNotFoundError`, err)
+	}
+}
+
+func TestEndRoomGame(t *testing.T) {
+	id, _ := setupActiveRoomTest(t)
+
+	err := EndRoomGame(context.Background(), id)
+	if err != nil || fakeBackend.HasGameInstance(id) {
+		t.Fatalf(`EndRoomGame(Valid) = %v with instance %t, want nil without instance`, err, fakeBackend.HasGameInstance(id))
+	}
+
+	room, _ := GetRoom(id)
+	if room.Status != StatusEnded {
+		t.Fatalf(`EndRoomGame(Valid) status = %q, want %q`, room.Status, StatusEnded)
+	}
+}
+
+func TestEndRoomGameInvalidID(t *testing.T) {
+	err := EndRoomGame(context.Background(), randomID)
+	if !errors.As(err, &sErr.MNF_ERR) {
+		t.Fatalf(`EndRoomGame(InvalidID) = %v, want MatchNotFoundError`, err)
+	}
+}
+
 func TestDeleteRoom(t *testing.T) {
 	id, _ := setupActiveRoomTest(t)
 
-	err := DeleteRoom(id)
+	err := DeleteRoom(context.Background(), id)
 	if err != nil {
 		t.Fatalf(`DeleteRoom(Valid) = %v, want nil`, err)
 	}
@@ -331,7 +862,7 @@ func TestDeleteRoom(t *testing.T) {
 func TestDeleteEmptyID(t *testing.T) {
 	setupActiveRoomTest(t)
 
-	err := DeleteRoom("")
+	err := DeleteRoom(context.Background(), "")
 	if !errors.As(err, &sErr.EV_ERR) {
 		t.Fatalf(`DeleteRoom(EmptyID) = %v, want EmptyValueError`, err)
 	}
@@ -340,7 +871,7 @@ func TestDeleteEmptyID(t *testing.T) {
 func TestDeleteInvalidID(t *testing.T) {
 	setupActiveRoomTest(t)
 
-	err := DeleteRoom(randomID)
+	err := DeleteRoom(context.Background(), randomID)
 	if !errors.As(err, &sErr.MNF_ERR) {
 		t.Fatalf(`DeleteRoom(InvalidID) = %v, want MatchNotFoundError`, err)
 	}
@@ -349,18 +880,235 @@ func TestDeleteInvalidID(t *testing.T) {
 func TestDeleteDouble(t *testing.T) {
 	id, _ := setupActiveRoomTest(t)
 
-	DeleteRoom(id)
-	err := DeleteRoom(id)
+	DeleteRoom(context.Background(), id)
+	err := DeleteRoom(context.Background(), id)
 	if !errors.As(err, &sErr.MNF_ERR) {
 		t.Fatalf(`DeleteRoom(Double) = %v, want MatchNotFoundError`, err)
 	}
 }
 
+func TestCreateRoomRollback(t *testing.T) {
+	cancelling := setupCancellingBackend(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelling.cancel = cancel
+
+	id, err := CreateRoom(ctx, testRoomJSON)
+	if id != "" || !errors.Is(err, context.Canceled) {
+		t.Fatalf(`CreateRoom(Cancelled) = %q, %v, want "", context.Canceled`, id, err)
+	}
+
+	if len(GetRooms()) != 0 || len(cancelling.GetGameInstances()) != 0 {
+		t.Fatalf(`CreateRoom(Cancelled) left %v, %v behind, want nothing`, GetRooms(), cancelling.GetGameInstances())
+	}
+}
+
+func TestCreateRoomRollbackOrphaned(t *testing.T) {
+	cancelling := setupCancellingBackend(t)
+	cancelling.SetFailure("EndGame", errors.New("game server unreachable"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelling.cancel = cancel
+
+	CreateRoom(ctx, testRoomJSON)
+
+	orphaned := GetOrphanedGames()
+	if len(orphaned) != 1 || len(GetRooms()) != 0 {
+		t.Fatalf(`CreateRoom(Unreachable Rollback) orphans = %v, want 1 orphaned game`, orphaned)
+	}
+}
+
+func TestDeleteRoomUnreachable(t *testing.T) {
+	id, _ := setupActiveRoomTest(t)
+	fakeBackend.SetFailure("EndGame", errors.New("game server unreachable"))
+
+	err := DeleteRoom(context.Background(), id)
+	if err != nil {
+		t.Fatalf(`DeleteRoom(Unreachable) = %v, want nil`, err)
+	}
+
+	confirmRoomNotExist(t, id)
+
+	orphaned := GetOrphanedGames()
+	if len(orphaned) != 1 || orphaned[0].Game.RID != id {
+		t.Fatalf(`DeleteRoom(Unreachable) orphans = %v, want %s`, orphaned, id)
+	}
+
+	fakeBackend.SetFailure("EndGame", nil)
+	confirmOrphansCleaned(t)
+
+	if fakeBackend.HasGameInstance(id) {
+		t.Fatalf(`DeleteRoom(Unreachable) cleanup left game instance behind`)
+	}
+}
+
+func TestRetryOrphanedGame(t *testing.T) {
+	id, _ := setupActiveRoomTest(t)
+	fakeBackend.SetFailure("EndGame", errors.New("game server unreachable"))
+	DeleteRoom(context.Background(), id)
+
+	err := RetryOrphanedGame(context.Background(), id)
+	if err == nil {
+		t.Fatalf(`RetryOrphanedGame(Unreachable) = nil, want error`)
+	}
+
+	fakeBackend.SetFailure("EndGame", nil)
+
+	err = RetryOrphanedGame(context.Background(), id)
+	if err != nil || len(GetOrphanedGames()) != 0 {
+		t.Fatalf(`RetryOrphanedGame(Valid) = %v, want nil`, err)
+	}
+}
+
+func TestRetryOrphanedGameInvalidID(t *testing.T) {
+	err := RetryOrphanedGame(context.Background(), randomID)
+	if !errors.As(err, &sErr.MNF_ERR) {
+		t.Fatalf(`RetryOrphanedGame(InvalidID) = %v, want MatchNotFoundError`, err)
+	}
+}
+
+func TestCreateRoomWhileDraining(t *testing.T) {
+	StopAcceptingRooms()
+	t.Cleanup(func() { draining.Store(false) })
+
+	id, err := CreateRoom(context.Background(), testRoomJSON)
+	if id != "" || !errors.As(err, &sErr.UA_ERR) {
+		t.Fatalf(`CreateRoom(Draining) = %q, %v, want "", UnavailableError`, id, err)
+	}
+}
+
+func TestNotifyShutdown(t *testing.T) {
+	id, _ := setupRoomTest(t)
+
+	events, _ := SubscribeToRoom(id)
+
+	NotifyShutdown()
+
+	confirmRoomEvent(t, events, EventServerShutdown)
+
+	_, open := <-events
+	if open {
+		t.Fatalf(`NotifyShutdown(Subscribed) left event stream open, want closed`)
+	}
+}
+
+func TestDrainRooms(t *testing.T) {
+	id, _ := setupRoomTest(t)
+
+	err := DrainRooms(context.Background(), "")
+	if err != nil || fakeBackend.HasGameInstance(id) {
+		t.Fatalf(`DrainRooms(Valid) = %v, want nil and game instance ended`, err)
+	}
+}
+
+func TestDrainRoomsRestore(t *testing.T) {
+	id, trInstance := setupRoomTest(t)
+	stateFile := t.TempDir() + "/rooms.json"
+
+	SetRoomRules(context.Background(), id, []byte(`{"rules":"`+testRoomName+`"}`))
+	UpdateRoomScores(id, map[string]int{randomID: 3})
+
+	err := DrainRooms(context.Background(), stateFile)
+	if err != nil {
+		t.Fatalf(`DrainRooms(StateFile) = %v, want nil`, err)
+	}
+
+	cleanUpAfterTest()
+
+	err = RestoreRooms(context.Background(), stateFile)
+	if err != nil {
+		t.Fatalf(`RestoreRooms(Valid) = %v, want nil`, err)
+	}
+
+	trInstance.Status = StatusCreated
+	trInstance.Addr = testConURL
+	trInstance.Created = trInstance.Created.Round(0)
+	checkExpectedRoomData(t, id, trInstance)
+
+	game, err := fakeBackend.GetFakeGame(id)
+	if err != nil || game.Rules.Rules != testRoomName || len(game.Snapshot) == 0 {
+		t.Fatalf(`RestoreRooms(Game) = %v, %v, want game with saved rules and snapshot`, game, err)
+	}
+
+	results, _ := GetRoomResults(id)
+	if results.Scores[randomID] != 3 {
+		t.Fatalf(`RestoreRooms(Results) = %v, want saved scores`, results)
+	}
+}
+
+func TestRestoreRoomsMissingFile(t *testing.T) {
+	err := RestoreRooms(context.Background(), t.TempDir()+"/rooms.json")
+	if err != nil {
+		t.Fatalf(`RestoreRooms(MissingFile) = %v, want nil`, err)
+	}
+}
+
+type cancellingBackend struct {
+	*gameclient.FakeBackend
+	cancel context.CancelFunc
+}
+
+func (b *cancellingBackend) CreateGameInstance(ctx context.Context, setup gameclient.GameSetup) error {
+	err := b.FakeBackend.CreateGameInstance(ctx, setup)
+	b.cancel()
+	return err
+}
+
+type addrFailingBackend struct {
+	*gameclient.FakeBackend
+	addr string
+}
+
+func (b *addrFailingBackend) CreateGameInstance(ctx context.Context, setup gameclient.GameSetup) error {
+	if setup.Addr == b.addr {
+		return errors.New("game server unreachable")
+	}
+
+	return b.FakeBackend.CreateGameInstance(ctx, setup)
+}
+
+func setupAddrFailingBackend(t *testing.T, addr string) *addrFailingBackend {
+	failing := &addrFailingBackend{
+		FakeBackend: gameclient.NewFakeBackend(),
+		addr:        addr,
+	}
+	SetGameBackend(failing)
+
+	t.Cleanup(cleanUpAfterTest)
+
+	return failing
+}
+
+func setupCancellingBackend(t *testing.T) *cancellingBackend {
+	cancelling := &cancellingBackend{
+		FakeBackend: gameclient.NewFakeBackend(),
+	}
+	SetGameBackend(cancelling)
+
+	t.Cleanup(cleanUpAfterTest)
+
+	return cancelling
+}
+
+func confirmOrphansCleaned(t *testing.T) {
+	deadline := time.Now().Add(time.Second)
+	for len(GetOrphanedGames()) != 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf(`Orphaned games = %v, want none after cleanup`, GetOrphanedGames())
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
 func setupRoomTest(t *testing.T) (string, Room) {
-	id, _ := CreateRoom(testRoomJSON)
+	id, _ := CreateRoom(context.Background(), testRoomJSON)
 
 	trInstance := testRoom
 	trInstance.RID = id
+	trInstance.Version = reg.DefaultVersion
+	trInstance.Protocol = reg.DefaultProtocol
+	trInstance.Created = rooms[id].Created
 
 	t.Cleanup(cleanUpAfterTest)
 
@@ -368,10 +1116,13 @@ func setupRoomTest(t *testing.T) (string, Room) {
 }
 
 func setupAltRoomTest() (string, Room) {
-	id, _ := CreateRoom(altRoomJSON)
+	id, _ := CreateRoom(context.Background(), altRoomJSON)
 
 	trInstance := altRoom
 	trInstance.RID = id
+	trInstance.Version = reg.DefaultVersion
+	trInstance.Protocol = reg.DefaultProtocol
+	trInstance.Created = rooms[id].Created
 
 	return id, trInstance
 }
@@ -379,23 +1130,79 @@ func setupAltRoomTest() (string, Room) {
 func setupActiveRoomTest(t *testing.T) (string, Room) {
 	id, _ := setupRoomTest(t)
 
-	UpdateRoomGameMode(id, testGameMode)
+	UpdateRoomGameMode(context.Background(), id, testGameMode, "")
 
 	trInstance, _ := GetRoom(id)
 
-	gameclient.CreateGameInstance(id, trInstance.Addr)
+	fakeBackend.CreateGameInstance(context.Background(), gameclient.GameSetup{RID: id, GameMode: trInstance.GameMode, Addr: trInstance.Addr})
 
 	return id, trInstance
 }
 
+func setupMigrationTest(t *testing.T) (string, Room) {
+	id, trInstance := setupRoomTest(t)
+
+	reg.RegisterGameModeInstance(reg.GameMode{Name: testGameMode, URL: migrationURL})
+	t.Cleanup(func() {
+		reg.RemoveGameModeInstance(testGameMode, "", migrationURL)
+	})
+
+	return id, trInstance
+}
+
+func setupVersionTest(t *testing.T) {
+	for _, version := range []string{"1.0.0", "1.1.0", "2.0.0"} {
+		reg.RegisterGameModeInstance(reg.GameMode{Name: testGameMode, Version: version, URL: testConURL})
+	}
+
+	t.Cleanup(func() {
+		for _, version := range []string{"1.0.0", "1.1.0", "2.0.0"} {
+			reg.RemoveGameModeInstance(testGameMode, version, "")
+		}
+	})
+
+	t.Cleanup(cleanUpAfterTest)
+}
+
+func setupRunningVersionTest(t *testing.T) string {
+	id := setupVersionedRoomTest(t)
+	UpdateRoomStatus(id, StatusRunning)
+
+	return id
+}
+
+func setupVersionedRoomTest(t *testing.T) string {
+	setupVersionTest(t)
+
+	versioned := testRoom
+	versioned.Version = "1.0.0"
+	versionedJSON, _ := json.Marshal(versioned)
+
+	id, _ := CreateRoom(context.Background(), versionedJSON)
+
+	return id
+}
+
+// slowStateBackend holds game state queries until release is closed.
+type slowStateBackend struct {
+	*gameclient.FakeBackend
+	release chan struct{}
+	calls   atomic.Int32
+}
+
+func (b *slowStateBackend) GetGameState(ctx context.Context, rid string) (gameclient.GameState, error) {
+	b.calls.Add(1)
+	<-b.release
+
+	return b.FakeBackend.GetGameState(ctx, rid)
+}
+
 func setupRoomSuite() {
-	go testDummy.Serve(testConPort)
-	go testDummy.Serve(altConPort)
+	SetGameBackend(fakeBackend)
+	cleanupRetryInterval = time.Millisecond
 
 	reg.RegisterGameMode(testGameMode, testConURL)
 	reg.RegisterGameMode(altGameMode, altConURL)
-
-	time.Sleep(200 * time.Millisecond)
 }
 
 func checkExpectedRoomData(t *testing.T, id string, expected Room) {
@@ -405,6 +1212,21 @@ func checkExpectedRoomData(t *testing.T, id string, expected Room) {
 	}
 }
 
+func confirmRoomEvent(t *testing.T, events chan RoomEvent, eventType string) RoomEvent {
+	select {
+	case event := <-events:
+		if event.Type != eventType {
+			t.Fatalf(`RoomEvent = %q, want %q`, event.Type, eventType)
+		}
+
+		return event
+	case <-time.After(time.Second):
+		t.Fatalf(`RoomEvent not received, want %q`, eventType)
+	}
+
+	return RoomEvent{}
+}
+
 func confirmRoomNotExist(t *testing.T, id string) {
 	room, err := GetRoom(id)
 	if err == nil {
@@ -413,7 +1235,20 @@ func confirmRoomNotExist(t *testing.T, id string) {
 }
 
 func cleanUpAfterTest() {
+	roomsLock.Lock()
 	rooms = make(map[string]Room)
+	roomsLock.Unlock()
+
+	gameStates = make(map[string]*cachedGameState)
+
+	orphansLock.Lock()
+	orphans = make(map[string]OrphanedGame)
+	orphansLock.Unlock()
+
+	fakeBackend = gameclient.NewFakeBackend()
+	SetGameBackend(fakeBackend)
+
+	maxRoomsPerOwner = 0
 }
 
 func cleanUpAfterSuite() {
@@ -0,0 +1,210 @@
+package room
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync/atomic"
+
+	"Engee-Server/gameClient/payload"
+	registry "Engee-Server/gameRegistry"
+	sErr "Engee-Server/stockErrors"
+	"Engee-Server/utils"
+)
+
+const EventServerShutdown = "server_shutdown"
+
+type persistedRoom struct {
+	Room     Room          `json:"room"`
+	Rules    payload.Rules `json:"rules"`
+	Snapshot []byte        `json:"snapshot,omitempty"`
+	Results  RoomResults   `json:"results"`
+}
+
+var draining atomic.Bool
+
+func StopAcceptingRooms() {
+	draining.Store(true)
+}
+
+func requireAcceptingRooms() error {
+	if draining.Load() {
+		return &sErr.UnavailableError{
+			Space:  "Rooms",
+			Reason: "server is shutting down",
+		}
+	}
+
+	return nil
+}
+
+func NotifyShutdown() {
+	eventsLock.Lock()
+	closing := roomEvents
+	roomEvents = make(map[string]*utils.Broadcaster[RoomEvent])
+	eventsLock.Unlock()
+
+	for rid, broadcaster := range closing {
+		broadcaster.Publish(RoomEvent{
+			RID:     rid,
+			Type:    EventServerShutdown,
+			Message: "server is shutting down",
+		})
+		broadcaster.Close()
+	}
+}
+
+// DrainRooms ends every game instance, first saving each room and its
+// exported game state to stateFile when one is given.
+func DrainRooms(ctx context.Context, stateFile string) error {
+	gameBackend := backend
+	persisted := make([]persistedRoom, 0)
+
+	var errs []error
+	for _, room := range GetRooms() {
+		err := ctx.Err()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not drain room %s: %w", room.RID, err))
+			continue
+		}
+
+		if stateFile != "" {
+			persisted = append(persisted, persistRoom(ctx, room))
+		}
+
+		err = endGameInstance(ctx, gameBackend, room.RID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not end game instance for room %s: %w", room.RID, err))
+		}
+	}
+
+	if stateFile != "" {
+		err := writeRoomState(stateFile, persisted)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// RestoreRooms recreates the rooms saved by DrainRooms, handing each game
+// server its saved state, and removes stateFile once it has been read.
+func RestoreRooms(ctx context.Context, stateFile string) error {
+	content, err := os.ReadFile(stateFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("could not read room state: %w", err)
+	}
+
+	var persisted []persistedRoom
+	err = json.Unmarshal(content, &persisted)
+	if err != nil {
+		return fmt.Errorf("could not parse room state: %w", err)
+	}
+
+	var errs []error
+	for _, saved := range persisted {
+		err = restoreRoom(ctx, saved)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not restore room %s: %w", saved.Room.RID, err))
+		}
+	}
+
+	err = os.Remove(stateFile)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("could not remove room state: %w", err))
+	}
+
+	return errors.Join(errs...)
+}
+
+func persistRoom(ctx context.Context, room Room) persistedRoom {
+	saved := persistedRoom{
+		Room: room,
+	}
+
+	results, err := GetRoomResults(room.RID)
+	if err == nil {
+		saved.Results = results
+	}
+
+	game, err := backend.GetGameInstance(room.RID)
+	if err != nil {
+		return saved
+	}
+
+	saved.Rules = game.Rules
+
+	saved.Snapshot, err = backend.ExportGameSnapshot(ctx, room.RID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Exporting game state", "rid", room.RID, "error", err)
+	}
+
+	return saved
+}
+
+func writeRoomState(stateFile string, persisted []persistedRoom) error {
+	content, err := json.MarshalIndent(persisted, "", "    ")
+	if err != nil {
+		return fmt.Errorf("could not marshal room state: %w", err)
+	}
+
+	err = os.WriteFile(stateFile, content, 0600)
+	if err != nil {
+		return fmt.Errorf("could not write room state: %w", err)
+	}
+
+	return nil
+}
+
+func restoreRoom(ctx context.Context, saved persistedRoom) error {
+	room := saved.Room
+
+	_, err := GetRoom(room.RID)
+	if err == nil {
+		return &sErr.MatchFoundError[string]{
+			Space: "Rooms",
+			Field: "RID",
+			Value: room.RID,
+		}
+	}
+
+	mode, err := registry.ResolveGameMode(room.GameMode, room.Version)
+	if err != nil {
+		return fmt.Errorf("could not get gamemode info: %w", err)
+	}
+
+	room.Protocol = mode.Protocol
+	room.Addr = mode.URL
+	room.Status = StatusCreated
+
+	setup := gameSetup(room)
+	setup.Rules = saved.Rules
+	setup.Snapshot = saved.Snapshot
+
+	err = backend.CreateGameInstance(ctx, setup)
+	if err != nil {
+		return err
+	}
+
+	roomsLock.Lock()
+	err = insertRoom(room)
+	roomsLock.Unlock()
+	if err != nil {
+		discardGameInstance(ctx, backend, room.RID, "room restored twice")
+		return err
+	}
+
+	resultsLock.Lock()
+	results[room.RID] = saved.Results
+	resultsLock.Unlock()
+
+	return nil
+}
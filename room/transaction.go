@@ -0,0 +1,66 @@
+package room
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"Engee-Server/tracing"
+)
+
+type step struct {
+	name string
+	do   func(ctx context.Context) error
+	undo func(ctx context.Context) error
+}
+
+type transaction struct {
+	name  string
+	steps []step
+}
+
+func newTransaction(name string) *transaction {
+	return &transaction{
+		name:  name,
+		steps: make([]step, 0),
+	}
+}
+
+func (t *transaction) add(name string, do func(ctx context.Context) error, undo func(ctx context.Context) error) {
+	t.steps = append(t.steps, step{
+		name: name,
+		do:   do,
+		undo: undo,
+	})
+}
+
+func (t *transaction) run(ctx context.Context) error {
+	for i, s := range t.steps {
+		stepCtx, span := tracing.Start(ctx, t.name+": "+s.name)
+		err := s.do(stepCtx)
+		span.RecordError(err)
+		span.End()
+		if err != nil {
+			t.rollback(i)
+			return fmt.Errorf("%s failed at %s: %w", t.name, s.name, err)
+		}
+	}
+
+	return nil
+}
+
+func (t *transaction) rollback(failed int) {
+	ctx := context.Background()
+
+	for i := failed - 1; i >= 0; i-- {
+		s := t.steps[i]
+		if s.undo == nil {
+			continue
+		}
+
+		err := s.undo(ctx)
+		if err != nil {
+			slog.Error("Rolling back", "transaction", t.name, "step", s.name, "error", err)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"Engee-Server/room"
+	sErr "Engee-Server/stockErrors"
+	"Engee-Server/user"
+	"Engee-Server/utils"
+)
+
+const AdminTokenHeader = "X-Engee-Admin-Token"
+
+var adminToken string
+
+func SetAdminToken(token string) {
+	adminToken = token
+}
+
+func AdminMiddleWare() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if adminToken == "" {
+			http.Error(c.Writer, "Admin API is disabled", http.StatusForbidden)
+			c.Abort()
+			return
+		}
+
+		token := c.Request.Header.Get(AdminTokenHeader)
+		if subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) != 1 {
+			http.Error(c.Writer, "Admin token is not recognised", http.StatusUnauthorized)
+			slog.WarnContext(c.Request.Context(), "Rejected admin request", "path", c.Request.URL.Path)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func registerAdminRoutes(router *gin.RouterGroup) {
+	router.GET("/orphans", getOrphanedGames)
+	router.POST("/orphans/:rid/cleanup", retryOrphanedGame)
+	router.POST("/rooms/:rid/migrate", migrateRoom)
+	router.GET("/users", getUsers)
+}
+
+func getOrphanedGames(c *gin.Context) {
+	_, w := processMessage(c)
+	orphans := room.GetOrphanedGames()
+
+	orphansJSON, err := json.Marshal(orphans)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to package orphaned games: %v", err), http.StatusInternalServerError)
+		slog.ErrorContext(c.Request.Context(), "Marshalling orphaned games", "error", err)
+		return
+	}
+
+	err = sendReply(w, "GET orphaned games", orphansJSON, http.StatusOK)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Sending reply", "error", err)
+	}
+}
+
+func retryOrphanedGame(c *gin.Context) {
+	_, w := processMessage(c)
+	ids := utils.GetRequestIDs(c.Request)
+	if len(ids) == 0 {
+		http.Error(w, "Failed to clean up orphaned game: no RID provided", http.StatusBadRequest)
+		return
+	}
+
+	err := room.RetryOrphanedGame(c.Request.Context(), ids[0])
+	if err != nil {
+		code := http.StatusBadGateway
+		if errors.As(err, &sErr.MNF_ERR) {
+			code = http.StatusNotFound
+		}
+
+		http.Error(w, fmt.Sprintf("Failed to clean up orphaned game: %v", err), code)
+		slog.ErrorContext(c.Request.Context(), "Cleaning up orphaned game", "error", err)
+		return
+	}
+
+	err = sendAccept(w, "POST orphaned game cleanup")
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Sending reply", "error", err)
+	}
+}
+
+func migrateRoom(c *gin.Context) {
+	reqBody, w := processMessage(c)
+	ids := utils.GetRequestIDs(c.Request)
+	if len(ids) == 0 {
+		http.Error(w, "Failed to migrate room: no RID provided", http.StatusBadRequest)
+		return
+	}
+
+	var request MigrateRoomRequest
+	if len(reqBody) > 0 {
+		err := json.Unmarshal(reqBody, &request)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Failed to parse migration request: %v", err), http.StatusBadRequest)
+			slog.ErrorContext(c.Request.Context(), "Unmarshalling migration request", "error", err)
+			return
+		}
+	}
+
+	migrated, err := room.MigrateRoom(c.Request.Context(), ids[0], request.URL, request.TransferState)
+	if err != nil {
+		code := http.StatusInternalServerError
+		if errors.As(err, &sErr.MNF_ERR) {
+			code = http.StatusNotFound
+		} else if errors.As(err, &sErr.UA_ERR) {
+			code = http.StatusServiceUnavailable
+		} else if errors.As(err, &sErr.IV_ERR) {
+			code = http.StatusBadRequest
+		}
+
+		http.Error(w, fmt.Sprintf("Failed to migrate room: %v", err), code)
+		slog.ErrorContext(c.Request.Context(), "Migrating room", "error", err)
+		return
+	}
+
+	roomJSON, err := json.Marshal(migrated)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to package room info: %v", err), http.StatusInternalServerError)
+		slog.ErrorContext(c.Request.Context(), "Marshalling room", "error", err)
+		return
+	}
+
+	err = sendReply(w, "POST room migration", roomJSON, http.StatusOK)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Sending reply", "error", err)
+	}
+}
+
+func getUsers(c *gin.Context) {
+	w := c.Writer
+
+	page, err := pageQuery(c)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list users: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	users, next, err := user.ListUsers(userFilterQuery(c), page)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list users: %v", err), http.StatusBadRequest)
+		slog.ErrorContext(c.Request.Context(), "Listing users", "error", err)
+		return
+	}
+
+	usersJSON, err := json.Marshal(UserListResponse{
+		Users:      newUserResponses(users),
+		NextCursor: next,
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to package users: %v", err), http.StatusInternalServerError)
+		slog.ErrorContext(c.Request.Context(), "Marshalling users", "error", err)
+		return
+	}
+
+	err = sendReply(w, "GET users", usersJSON, http.StatusOK)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Sending reply", "error", err)
+	}
+}
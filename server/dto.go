@@ -0,0 +1,208 @@
+package server
+
+import (
+	"time"
+
+	"Engee-Server/gameClient/payload"
+	registry "Engee-Server/gameRegistry"
+	"Engee-Server/room"
+	"Engee-Server/user"
+)
+
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+type UserResponse struct {
+	UID     string    `json:"uid"`
+	Name    string    `json:"name"`
+	Status  string    `json:"status"`
+	Created time.Time `json:"created"`
+}
+
+type UserListResponse struct {
+	Users      []UserResponse `json:"users"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+type CreateUserRequest struct {
+	Name string `json:"name"`
+}
+
+type CreateUserResponse struct {
+	User  UserResponse `json:"user"`
+	Token string       `json:"token"`
+}
+
+type UpdateUserRequest struct {
+	Name   *string `json:"name"`
+	Status *string `json:"status"`
+}
+
+type RoomResponse struct {
+	RID        string    `json:"rid"`
+	Name       string    `json:"name"`
+	GameMode   string    `json:"gamemode"`
+	Version    string    `json:"version"`
+	Protocol   int       `json:"protocol"`
+	Status     string    `json:"status"`
+	Private    bool      `json:"private"`
+	MaxPlayers int       `json:"max_players"`
+	Players    int       `json:"players"`
+	Owner      string    `json:"owner,omitempty"`
+	Created    time.Time `json:"created"`
+}
+
+type RoomListResponse struct {
+	Rooms      []RoomResponse `json:"rooms"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+type CreateRoomRequest struct {
+	Name       string `json:"name"`
+	GameMode   string `json:"gamemode"`
+	Version    string `json:"version"`
+	Private    bool   `json:"private"`
+	MaxPlayers int    `json:"max_players"`
+}
+
+type UpdateRoomRequest struct {
+	Name   *string `json:"name"`
+	Status *string `json:"status"`
+}
+
+type RoomGameModeRequest struct {
+	GameMode string `json:"gamemode"`
+	Version  string `json:"version"`
+}
+
+type RulesRequest struct {
+	Rules string              `json:"rules"`
+	Teams map[string][]string `json:"teams,omitempty"`
+}
+
+type RulesResponse struct {
+	RID   string              `json:"rid"`
+	Rules string              `json:"rules"`
+	Teams map[string][]string `json:"teams,omitempty"`
+}
+
+type MembershipRequest struct {
+	UID string `json:"uid"`
+}
+
+type GameModeResponse struct {
+	Name      string            `json:"name"`
+	Version   string            `json:"version"`
+	Protocol  int               `json:"protocol"`
+	Transport string            `json:"transport"`
+	Health    string            `json:"health"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+}
+
+type RegisterGameModeRequest struct {
+	Name      string            `json:"name"`
+	URL       string            `json:"url"`
+	Version   string            `json:"version"`
+	Protocol  int               `json:"protocol"`
+	Transport string            `json:"transport"`
+	BootID    string            `json:"boot_id"`
+	Metadata  map[string]string `json:"metadata"`
+}
+
+type GameModeHeartbeatRequest struct {
+	URL     string `json:"url"`
+	Version string `json:"version"`
+	BootID  string `json:"boot_id"`
+}
+
+type HealthResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+type StatusResponse struct {
+	Users         int    `json:"users"`
+	Rooms         int    `json:"rooms"`
+	Lobbies       int    `json:"lobbies"`
+	GameModes     int    `json:"gamemodes"`
+	GameInstances int    `json:"gamemode_instances"`
+	Uptime        string `json:"uptime"`
+	ShuttingDown  bool   `json:"shutting_down"`
+}
+
+type MigrateRoomRequest struct {
+	URL           string `json:"url"`
+	TransferState bool   `json:"transfer_state"`
+}
+
+func newUserResponse(u user.User) UserResponse {
+	return UserResponse{
+		UID:     u.UID,
+		Name:    u.Name,
+		Status:  u.Status,
+		Created: u.Created,
+	}
+}
+
+func newUserResponses(users []user.User) []UserResponse {
+	responses := make([]UserResponse, 0, len(users))
+	for _, u := range users {
+		responses = append(responses, newUserResponse(u))
+	}
+
+	return responses
+}
+
+func newRoomResponse(r room.Room) RoomResponse {
+	return RoomResponse{
+		RID:        r.RID,
+		Name:       r.Name,
+		GameMode:   r.GameMode,
+		Version:    r.Version,
+		Protocol:   r.Protocol,
+		Status:     r.Status,
+		Private:    r.Private,
+		MaxPlayers: r.MaxPlayers,
+		Players:    room.GetRoomPlayerCount(r.RID),
+		Owner:      r.Owner,
+		Created:    r.Created,
+	}
+}
+
+func newRoomResponses(rooms []room.Room) []RoomResponse {
+	responses := make([]RoomResponse, 0, len(rooms))
+	for _, r := range rooms {
+		responses = append(responses, newRoomResponse(r))
+	}
+
+	return responses
+}
+
+func newRulesResponse(rid string, rules payload.Rules) RulesResponse {
+	return RulesResponse{
+		RID:   rid,
+		Rules: rules.Rules,
+		Teams: rules.Teams,
+	}
+}
+
+func newGameModeResponse(mode registry.GameMode) GameModeResponse {
+	return GameModeResponse{
+		Name:      mode.Name,
+		Version:   mode.Version,
+		Protocol:  mode.Protocol,
+		Transport: mode.Transport,
+		Health:    mode.Health,
+		Metadata:  mode.Metadata,
+	}
+}
+
+func newGameModeResponses(modes []registry.GameMode) []GameModeResponse {
+	responses := make([]GameModeResponse, 0, len(modes))
+	for _, mode := range modes {
+		responses = append(responses, newGameModeResponse(mode))
+	}
+
+	return responses
+}
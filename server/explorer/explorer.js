@@ -0,0 +1,100 @@
+"use strict";
+
+// Renders the OpenAPI document served next to this page. Operations are
+// grouped by tag, and each one lists its parameters, body and responses.
+// Component schemas are listed once at the end.
+(function () {
+  const root = document.getElementById("explorer");
+
+  function element(tag, className, text) {
+    const node = document.createElement(tag);
+    if (className) {
+      node.className = className;
+    }
+    if (text !== undefined) {
+      node.textContent = text;
+    }
+    return node;
+  }
+
+  function schemaText(schema) {
+    return JSON.stringify(schema, null, 2);
+  }
+
+  function section(parent, title, text) {
+    parent.appendChild(element("h4", "", title));
+    parent.appendChild(element("pre", "", text));
+  }
+
+  function operation(path, method, op) {
+    const details = element("details", op.deprecated ? "deprecated" : "");
+    const summary = element("summary");
+    summary.appendChild(element("span", "method " + method, method));
+    summary.appendChild(element("code", "", path));
+    summary.appendChild(document.createTextNode(" " + (op.summary || "")));
+    details.appendChild(summary);
+
+    const body = element("div");
+    if (op.parameters && op.parameters.length > 0) {
+      const rows = op.parameters.map(function (p) {
+        return p.in + " " + p.name + (p.required ? " (required)" : "");
+      });
+      section(body, "Parameters", rows.join("\n"));
+    }
+    if (op.requestBody) {
+      for (const [type, content] of Object.entries(op.requestBody.content)) {
+        section(body, "Body " + type, schemaText(content.schema));
+      }
+    }
+    for (const [code, response] of Object.entries(op.responses || {})) {
+      let text = response.description || "";
+      for (const [type, content] of Object.entries(response.content || {})) {
+        text += "\n" + type + "\n" + schemaText(content.schema);
+      }
+      section(body, "Response " + code, text);
+    }
+    details.appendChild(body);
+
+    return details;
+  }
+
+  function render(doc) {
+    root.appendChild(element("h1", "", doc.info.title + " " + doc.info.version));
+
+    const tags = {};
+    for (const [path, methods] of Object.entries(doc.paths)) {
+      for (const [method, op] of Object.entries(methods)) {
+        const tag = (op.tags && op.tags[0]) || "other";
+        if (!tags[tag]) {
+          tags[tag] = element("section");
+          tags[tag].appendChild(element("h2", "", tag));
+          root.appendChild(tags[tag]);
+        }
+        tags[tag].appendChild(operation(path, method, op));
+      }
+    }
+
+    const schemas = element("section");
+    schemas.appendChild(element("h2", "", "schemas"));
+    for (const [name, schema] of Object.entries(doc.components.schemas)) {
+      const details = element("details");
+      details.id = "schema-" + name;
+      details.appendChild(element("summary", "", name));
+      details.appendChild(element("pre", "", schemaText(schema)));
+      schemas.appendChild(details);
+    }
+    root.appendChild(schemas);
+  }
+
+  fetch("openapi.json")
+    .then(function (response) {
+      if (!response.ok) {
+        throw new Error("GET openapi.json: " + response.status);
+      }
+      return response.json();
+    })
+    .then(render)
+    .catch(function (err) {
+      root.appendChild(element("pre", "", String(err)));
+    });
+})();
@@ -0,0 +1,109 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	registry "Engee-Server/gameRegistry"
+	"Engee-Server/lobby"
+	"Engee-Server/room"
+	"Engee-Server/user"
+	"Engee-Server/utils"
+)
+
+const storeCheckTimeout = time.Second
+
+var requireGameMode bool
+var startTime = time.Now()
+
+func SetReadinessOptions(gameModeRequired bool) {
+	requireGameMode = gameModeRequired
+}
+
+func registerHealthRoutes(router *gin.Engine) {
+	router.GET("/healthz", getHealth)
+	router.GET("/readyz", getReadiness)
+	router.GET("/status", getStatus)
+}
+
+func getHealth(c *gin.Context) {
+	sendJSON(c, "GET healthz", HealthResponse{Status: "ok"}, http.StatusOK)
+}
+
+func getReadiness(c *gin.Context) {
+	response := HealthResponse{
+		Status: "ready",
+		Checks: make(map[string]string),
+	}
+
+	code := http.StatusOK
+	for name, err := range readinessChecks() {
+		if err != nil {
+			response.Status = "not ready"
+			response.Checks[name] = err.Error()
+			code = http.StatusServiceUnavailable
+			continue
+		}
+
+		response.Checks[name] = "ok"
+	}
+
+	sendJSON(c, "GET readyz", response, code)
+}
+
+func getStatus(c *gin.Context) {
+	sendJSON(c, "GET status", StatusResponse{
+		Users:         user.CountUsers(),
+		Rooms:         room.CountRooms(),
+		Lobbies:       lobby.CountLobbies(),
+		GameModes:     len(registry.GetGameModes()),
+		GameInstances: registry.CountGameModeInstances(),
+		Uptime:        time.Since(startTime).Round(time.Second).String(),
+		ShuttingDown:  isShuttingDown(),
+	}, http.StatusOK)
+}
+
+func readinessChecks() map[string]error {
+	checks := map[string]error{
+		"shutdown":           nil,
+		"store":              checkStore(),
+		"heartbeat_monitors": nil,
+		"game_modes":         nil,
+	}
+
+	if isShuttingDown() {
+		checks["shutdown"] = errors.New("server is shutting down")
+	}
+
+	if utils.HeartbeatMonitorsStopped() {
+		checks["heartbeat_monitors"] = errors.New("heartbeat monitors have stopped")
+	}
+
+	if requireGameMode && len(registry.GetGameModes()) == 0 {
+		checks["game_modes"] = errors.New("no game modes are registered")
+	}
+
+	return checks
+}
+
+// checkStore confirms every in-memory store can still be locked, so a
+// deadlocked store reports the server unready instead of hanging requests.
+func checkStore() error {
+	done := make(chan struct{})
+	go func() {
+		user.CountUsers()
+		registry.CountGameModeInstances()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-time.After(storeCheckTimeout):
+		return fmt.Errorf("store did not respond within %v", storeCheckTimeout)
+	}
+}
@@ -0,0 +1,617 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	registry "Engee-Server/gameRegistry"
+	"Engee-Server/lobby"
+	"Engee-Server/room"
+	sErr "Engee-Server/stockErrors"
+	"Engee-Server/user"
+	"Engee-Server/utils"
+)
+
+func registerLegacyRoutes(router *gin.Engine) {
+	router.POST("/users", postUser)
+	router.POST("/rooms", postRoom)
+
+	router.POST("/users/:id", userHeartbeat)
+
+	router.GET("/rooms", getRooms)
+	router.GET("/rooms/:rid/users", getRoomUsers)
+	router.GET("/rooms/:rid/events", getRoomEvents)
+	router.GET("/rooms/:rid/results", getRoomResults)
+	router.GET("/rooms/:rid/game", getRoomGameState)
+	router.GET("/rooms/:rid/play", proxyRoomGame)
+	router.GET("/rooms/:rid", getRoomInfo)
+
+	router.GET("/gameModes", getGameModes)
+	router.POST("/gameModes", postGameMode)
+	router.GET("/gameModes/events", getRegistryEvents)
+	router.GET("/gameModes/:gameMode", getGameModeVersions)
+	router.POST("/gameModes/:gameMode", gameModeHeartbeat)
+
+	router.POST("/games/:rid/events", postGameEvent)
+
+	router.PUT("/users/:uid/name", updateUserName)
+	router.PUT("/users/:uid/room", userJoinRoom)
+	router.PUT("/users/:uid/leave", userLeaveRoom)
+
+	router.PUT("/rooms/:rid/name", updateRoomName)
+	router.PUT("/rooms/:rid/status", updateRoomStatus)
+	router.PUT("/rooms/:rid/mode", updateRoomGameMode)
+	router.PUT("/rooms/:rid/rules", updateRoomRules)
+
+	router.PUT("/rooms/:rid/create", initRoomGame)
+	router.PUT("/rooms/:rid/end", endRoomGame)
+
+	router.DELETE("/users/:uid", deleteUser)
+	router.DELETE("/rooms/:rid", deleteRoom)
+}
+
+func postUser(c *gin.Context) {
+	reqBody, w := processMessage(c)
+	uid, err := user.CreateUserFromIP(string(reqBody), c.ClientIP())
+
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to create user: %v", err), http.StatusInternalServerError)
+		slog.ErrorContext(c.Request.Context(), "Creating user", "error", err)
+		return
+	}
+
+	token, err := user.IssueUserToken(uid)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to issue user token: %v", err), http.StatusInternalServerError)
+		slog.ErrorContext(c.Request.Context(), "Issuing user token", "error", err)
+		return
+	}
+
+	w.Header().Set(TokenHeader, token)
+
+	err = sendSimpleReply(w, "POST user", uid, http.StatusOK)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Sending reply", "error", err)
+	}
+}
+
+func postRoom(c *gin.Context) {
+	reqBody, w := processMessage(c)
+	rid, err := room.CreateRoom(c.Request.Context(), ownedRoomInfo(reqBody, authenticatedUID(c.Request)))
+
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to create room: %v", err), http.StatusInternalServerError)
+		slog.ErrorContext(c.Request.Context(), "Creating room", "error", err)
+		return
+	}
+
+	err = sendSimpleReply(w, "POST room", rid, http.StatusOK)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Sending reply", "error", err)
+	}
+}
+
+func userHeartbeat(c *gin.Context) {
+	_, w := processMessage(c)
+	ids := utils.GetRequestIDs(c.Request)
+
+	err := user.Heartbeat(ids[0])
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Hearbeat failed: %v", err), http.StatusInternalServerError)
+		slog.ErrorContext(c.Request.Context(), "Receiving user heartbeat", "error", err)
+		return
+	}
+
+	err = sendAccept(w, "HEARTBEAT user")
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Sending reply", "error", err)
+	}
+}
+
+func getRooms(c *gin.Context) {
+	_, w := processMessage(c)
+	rooms := room.GetRooms()
+	for i, r := range rooms {
+		rooms[i] = r.Public()
+	}
+
+	roomsJSON, err := json.Marshal(rooms)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to package room info: %v", err), http.StatusInternalServerError)
+		slog.ErrorContext(c.Request.Context(), "Marshalling rooms", "error", err)
+		return
+	}
+
+	err = sendReply(w, "GET rooms", roomsJSON, http.StatusOK)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Sending reply", "error", err)
+	}
+}
+
+func getRoomUsers(c *gin.Context) {
+	_, w := processMessage(c)
+	ids := utils.GetRequestIDs(c.Request)
+
+	users, err := lobby.GetUsersInRoom(c.Request.Context(), ids[0])
+
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get room users: %v", err), http.StatusInternalServerError)
+		slog.ErrorContext(c.Request.Context(), "Getting room users", "error", err)
+		return
+	}
+
+	usersJSON, err := json.Marshal(users)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to package room user info: %v", err), http.StatusInternalServerError)
+		slog.ErrorContext(c.Request.Context(), "Marshalling room users", "error", err)
+		return
+	}
+
+	err = sendReply(w, "GET room/users", usersJSON, http.StatusOK)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Sending reply", "error", err)
+	}
+}
+
+func getRoomInfo(c *gin.Context) {
+	_, w := processMessage(c)
+	ids := utils.GetRequestIDs(c.Request)
+
+	roomInfo, err := room.GetRoom(ids[0])
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get room URL: %v", err), http.StatusInternalServerError)
+		slog.ErrorContext(c.Request.Context(), "Getting room URL", "error", err)
+		return
+	}
+
+	rInfo, err := json.Marshal(roomInfo.Public())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to package room info: %v", err), http.StatusInternalServerError)
+		slog.ErrorContext(c.Request.Context(), "Marshaling room info", "error", err)
+		return
+	}
+
+	err = sendReply(w, "GET room/info", rInfo, http.StatusOK)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Sending reply", "error", err)
+		return
+	}
+}
+
+func getRoomResults(c *gin.Context) {
+	_, w := processMessage(c)
+	ids := utils.GetRequestIDs(c.Request)
+	if len(ids) == 0 {
+		http.Error(w, "Failed to get room results: no RID provided", http.StatusBadRequest)
+		return
+	}
+
+	results, err := room.GetRoomResults(ids[0])
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get room results: %v", err), http.StatusInternalServerError)
+		slog.ErrorContext(c.Request.Context(), "Getting room results", "error", err)
+		return
+	}
+
+	rResults, err := json.Marshal(results)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to package room results: %v", err), http.StatusInternalServerError)
+		slog.ErrorContext(c.Request.Context(), "Marshaling room results", "error", err)
+		return
+	}
+
+	err = sendReply(w, "GET room/results", rResults, http.StatusOK)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Sending reply", "error", err)
+	}
+}
+
+func getRoomGameState(c *gin.Context) {
+	_, w := processMessage(c)
+	ids := utils.GetRequestIDs(c.Request)
+	if len(ids) == 0 {
+		http.Error(w, "Failed to get game state: no RID provided", http.StatusBadRequest)
+		return
+	}
+
+	state, err := room.GetRoomGameState(c.Request.Context(), ids[0])
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get game state: %v", err), http.StatusInternalServerError)
+		slog.ErrorContext(c.Request.Context(), "Getting game state", "error", err)
+		return
+	}
+
+	gState, err := json.Marshal(state)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to package game state: %v", err), http.StatusInternalServerError)
+		slog.ErrorContext(c.Request.Context(), "Marshaling game state", "error", err)
+		return
+	}
+
+	err = sendReply(w, "GET room/game", gState, http.StatusOK)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Sending reply", "error", err)
+	}
+}
+
+func getGameModes(c *gin.Context) {
+	_, w := processMessage(c)
+
+	gameModes := registry.GetGameModes()
+
+	gameModesJSON, err := json.Marshal(gameModes)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to package game modes: %v", err), http.StatusInternalServerError)
+		slog.ErrorContext(c.Request.Context(), "Marshalling game modes", "error", err)
+		return
+	}
+
+	err = sendReply(w, "GET gamemodes", gameModesJSON, http.StatusOK)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Sending reply", "error", err)
+	}
+}
+
+func getGameModeVersions(c *gin.Context) {
+	_, w := processMessage(c)
+
+	versions, err := registry.GetGameModeVersions(c.Param("gameMode"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get game mode versions: %v", err), http.StatusInternalServerError)
+		slog.ErrorContext(c.Request.Context(), "Getting game mode versions", "error", err)
+		return
+	}
+
+	for i, version := range versions {
+		versions[i] = version.Public()
+	}
+
+	versionsJSON, err := json.Marshal(versions)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to package game mode versions: %v", err), http.StatusInternalServerError)
+		slog.ErrorContext(c.Request.Context(), "Marshalling game mode versions", "error", err)
+		return
+	}
+
+	err = sendReply(w, "GET gamemode/versions", versionsJSON, http.StatusOK)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Sending reply", "error", err)
+	}
+}
+
+// legacyGameModeRegistration is the body of the legacy POST /gameModes,
+// which names the game mode "first" and its URL "second".
+type legacyGameModeRegistration struct {
+	First     string            `json:"first"`
+	Second    string            `json:"second"`
+	Version   string            `json:"version"`
+	Protocol  int               `json:"protocol"`
+	Transport string            `json:"transport"`
+	BootID    string            `json:"boot_id"`
+	Metadata  map[string]string `json:"metadata"`
+}
+
+func postGameMode(c *gin.Context) {
+	reqBody, w := processMessage(c)
+
+	var gameMode legacyGameModeRegistration
+	err := json.Unmarshal(reqBody, &gameMode)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to unmarshal game mode: %v", err), http.StatusInternalServerError)
+		slog.ErrorContext(c.Request.Context(), "Unmarshalling game mode", "error", err)
+		return
+	}
+
+	err = registry.VerifyGameModeRequest(gameMode.First, c.Request, reqBody)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to authenticate game mode: %v", err), http.StatusUnauthorized)
+		slog.ErrorContext(c.Request.Context(), "Authenticating game mode", "error", err)
+		return
+	}
+
+	err = registry.RegisterGameModeInstance(registry.GameMode{
+		Name:      gameMode.First,
+		Version:   gameMode.Version,
+		Protocol:  gameMode.Protocol,
+		Transport: gameMode.Transport,
+		URL:       gameMode.Second,
+		BootID:    gameMode.BootID,
+		Metadata:  gameMode.Metadata,
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to update game mode: %v", err), http.StatusInternalServerError)
+		slog.ErrorContext(c.Request.Context(), "Updating game mode", "error", err)
+		return
+	}
+
+	err = sendAccept(w, "POST gamemode")
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Sending reply", "error", err)
+	}
+}
+
+func gameModeHeartbeat(c *gin.Context) {
+	reqBody, w := processMessage(c)
+	splitPath := strings.Split(c.Request.URL.Path, "/")
+	modeName := splitPath[len(splitPath)-1]
+
+	err := registry.VerifyGameModeRequest(modeName, c.Request, reqBody)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to authenticate heartbeat: %v", err), http.StatusUnauthorized)
+		slog.ErrorContext(c.Request.Context(), "Authenticating gamemode heartbeat", "error", err)
+		return
+	}
+
+	err = registry.Heartbeat(modeName, c.Query("version"), c.Query("url"), c.Query("boot_id"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to accept heartbeat: %v", err), http.StatusInternalServerError)
+		slog.ErrorContext(c.Request.Context(), "Receiving gamemode heartbeat", "error", err)
+		return
+	}
+
+	err = sendAccept(w, "HEARTBEAT gamemode")
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Sending reply", "error", err)
+	}
+}
+
+func postGameEvent(c *gin.Context) {
+	reqBody, w := processMessage(c)
+	ids := utils.GetRequestIDs(c.Request)
+	if len(ids) == 0 {
+		http.Error(w, "Failed to accept game event: no RID provided", http.StatusBadRequest)
+		return
+	}
+
+	gameRoom, err := room.GetRoom(ids[0])
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to accept game event: %v", err), http.StatusNotFound)
+		slog.ErrorContext(c.Request.Context(), "Finding room for game event", "error", err)
+		return
+	}
+
+	err = registry.VerifyGameModeRequest(gameRoom.GameMode, c.Request, reqBody)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to authenticate game event: %v", err), http.StatusUnauthorized)
+		slog.ErrorContext(c.Request.Context(), "Authenticating game event", "error", err)
+		return
+	}
+
+	err = lobby.HandleGameEvent(c.Request.Context(), gameRoom.RID, reqBody)
+	if err != nil {
+		code := http.StatusInternalServerError
+		if errors.As(err, &sErr.EV_ERR) || errors.As(err, &sErr.IV_ERR) {
+			code = http.StatusBadRequest
+		}
+
+		http.Error(w, fmt.Sprintf("Failed to handle game event: %v", err), code)
+		slog.ErrorContext(c.Request.Context(), "Handling game event", "error", err)
+		return
+	}
+
+	err = sendAccept(w, "POST game/event")
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Sending reply", "error", err)
+	}
+}
+
+func updateUserName(c *gin.Context) {
+	reqBody, w := processMessage(c)
+	ids := utils.GetRequestIDs(c.Request)
+	err := user.UpdateUserName(ids[0], string(reqBody))
+
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to update user name: %v", err), http.StatusInternalServerError)
+		slog.ErrorContext(c.Request.Context(), "Updating user name", "error", err)
+		return
+	}
+
+	err = sendAccept(w, "PUT user/name")
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Sending reply", "error", err)
+	}
+}
+
+func userJoinRoom(c *gin.Context) {
+	reqBody, w := processMessage(c)
+	ids := utils.GetRequestIDs(c.Request)
+	err := lobby.JoinUserToRoom(ids[0], string(reqBody))
+
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to add user to room: %v", err), http.StatusInternalServerError)
+		slog.ErrorContext(c.Request.Context(), "Adding user to room", "error", err)
+		return
+	}
+
+	err = sendAccept(w, "PUT user/room")
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Sending reply", "error", err)
+	}
+}
+
+func userLeaveRoom(c *gin.Context) {
+	reqBody, w := processMessage(c)
+	ids := utils.GetRequestIDs(c.Request)
+	err := lobby.RemoveUserFromRoom(c.Request.Context(), ids[0], string(reqBody))
+
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to remove user from room: %v", err), http.StatusInternalServerError)
+		slog.ErrorContext(c.Request.Context(), "Removing user from room", "error", err)
+		return
+	}
+
+	err = sendAccept(w, "PUT user/leave")
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Sending reply", "error", err)
+	}
+}
+
+func updateRoomName(c *gin.Context) {
+	reqBody, w := processMessage(c)
+	ids := utils.GetRequestIDs(c.Request)
+	err := room.UpdateRoomName(ids[0], string(reqBody))
+
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to update room name: %v", err), http.StatusInternalServerError)
+		slog.ErrorContext(c.Request.Context(), "Updating room name", "error", err)
+		return
+	}
+
+	err = sendAccept(w, "PUT room/name")
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Sending reply", "error", err)
+		return
+	}
+}
+
+func updateRoomStatus(c *gin.Context) {
+	reqBody, w := processMessage(c)
+	ids := utils.GetRequestIDs(c.Request)
+	err := room.UpdateRoomStatus(ids[0], string(reqBody))
+
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to update room status: %v", err), http.StatusInternalServerError)
+		slog.ErrorContext(c.Request.Context(), "Updating room status", "error", err)
+		return
+	}
+
+	err = sendAccept(w, "PUT room/status")
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Sending reply", "error", err)
+		return
+	}
+}
+
+func updateRoomGameMode(c *gin.Context) {
+	reqBody, w := processMessage(c)
+	ids := utils.GetRequestIDs(c.Request)
+
+	type gameModeSelection struct {
+		GameMode string `json:"gamemode"`
+		Version  string `json:"version"`
+	}
+
+	var selection gameModeSelection
+	err := json.Unmarshal(reqBody, &selection)
+	if err != nil {
+		selection.GameMode = string(reqBody)
+	}
+
+	err = room.UpdateRoomGameMode(c.Request.Context(), ids[0], selection.GameMode, selection.Version)
+
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to update room game mode: %v", err), http.StatusInternalServerError)
+		slog.ErrorContext(c.Request.Context(), "Updating room game mode", "error", err)
+		return
+	}
+
+	err = sendAccept(w, "PUT room/gamemode")
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Sending reply", "error", err)
+		return
+	}
+}
+
+func updateRoomRules(c *gin.Context) {
+	reqBody, w := processMessage(c)
+	ids := utils.GetRequestIDs(c.Request)
+	err := room.SetRoomRules(c.Request.Context(), ids[0], reqBody)
+
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to update room rules: %v", err), http.StatusInternalServerError)
+		slog.ErrorContext(c.Request.Context(), "Updating room rules", "error", err)
+		return
+	}
+
+	err = sendAccept(w, "PUT room/rules")
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Sending reply", "error", err)
+		return
+	}
+}
+
+func initRoomGame(c *gin.Context) {
+	_, w := processMessage(c)
+	ids := utils.GetRequestIDs(c.Request)
+	err := room.InitializeRoomGame(c.Request.Context(), ids[0])
+
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to update user name: %v", err), http.StatusInternalServerError)
+		slog.ErrorContext(c.Request.Context(), "Updating user name", "error", err)
+		return
+	}
+
+	err = sendAccept(w, "PUT room/init")
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Sending reply", "error", err)
+		return
+	}
+}
+
+func endRoomGame(c *gin.Context) {
+	_, w := processMessage(c)
+	ids := utils.GetRequestIDs(c.Request)
+	err := room.EndRoomGame(c.Request.Context(), ids[0])
+
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to end game: %v", err), http.StatusInternalServerError)
+		slog.ErrorContext(c.Request.Context(), "Ending game", "error", err)
+		return
+	}
+
+	err = sendAccept(w, "PUT room/end")
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Sending reply", "error", err)
+		return
+	}
+}
+
+func deleteUser(c *gin.Context) {
+	_, w := processMessage(c)
+	ids := utils.GetRequestIDs(c.Request)
+
+	err := lobby.RemoveUserFromAllRooms(c.Request.Context(), ids[0])
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to delete room(s) user is in: %v", err), http.StatusInternalServerError)
+		slog.ErrorContext(c.Request.Context(), "Removing deleting user from room(s)", "error", err)
+		//No return, want to complete deleting user regardless
+	}
+
+	err = user.DeleteUser(ids[0])
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to delete user: %v", err), http.StatusInternalServerError)
+		slog.ErrorContext(c.Request.Context(), "Deleting user", "error", err)
+		return
+	}
+
+	err = sendAccept(w, "DELETE user")
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Sending reply", "error", err)
+		return
+	}
+
+}
+
+func deleteRoom(c *gin.Context) {
+	_, w := processMessage(c)
+	ids := utils.GetRequestIDs(c.Request)
+
+	err := room.DeleteRoom(c.Request.Context(), ids[0])
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to delete room: %v", err), http.StatusInternalServerError)
+		slog.ErrorContext(c.Request.Context(), "Deleting room", "error", err)
+		return
+	}
+
+	err = sendAccept(w, "DELETE room")
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Sending reply", "error", err)
+		return
+	}
+}
@@ -0,0 +1,245 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	registry "Engee-Server/gameRegistry"
+	"Engee-Server/lobby"
+	"Engee-Server/room"
+	"Engee-Server/user"
+	"Engee-Server/utils"
+)
+
+func TestLegacyRoomsHideGameServer(t *testing.T) {
+	game, rid := setupGameServerTest(t)
+
+	for _, path := range []string{"/rooms", "/rooms/" + rid, "/gameModes/" + proxyGameMode} {
+		recorder := serveTestRequest(http.MethodGet, path)
+
+		body := recorder.Body.String()
+		if recorder.Code != http.StatusOK || strings.Contains(body, game.server.URL) || strings.Contains(body, `"addr"`) || strings.Contains(body, `"url"`) {
+			t.Fatalf(`GET %s = %d, %s, want 200 without the game server address`, path, recorder.Code, body)
+		}
+	}
+}
+
+func TestLegacyUsers(t *testing.T) {
+	recorder := serveLegacyRequest(http.MethodPost, "/users", "Legacy User", nil)
+
+	var uid string
+	err := json.Unmarshal(recorder.Body.Bytes(), &uid)
+	t.Cleanup(func() { user.DeleteUser(uid) })
+
+	if recorder.Code != http.StatusOK || err != nil || uid == "" || recorder.Header().Get(TokenHeader) == "" {
+		t.Fatalf(`POST /users = %d, %s, want 200 with a quoted UID and a token header`, recorder.Code, recorder.Body.String())
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+	}{
+		{"Heartbeat", http.MethodPost, "/users/" + uid, ""},
+		{"Name", http.MethodPut, "/users/" + uid + "/name", "Renamed User"},
+		{"Delete", http.MethodDelete, "/users/" + uid, ""},
+	}
+
+	for _, test := range tests {
+		recorder := serveLegacyRequest(test.method, test.path, test.body, nil)
+		if recorder.Code != http.StatusAccepted || recorder.Body.Len() != 0 {
+			t.Fatalf(`%s %s(%s) = %d, %q, want 202 with no body`, test.method, test.path, test.name, recorder.Code, recorder.Body.String())
+		}
+	}
+}
+
+func TestLegacyRooms(t *testing.T) {
+	setupGameServerTest(t)
+
+	roomJSON, _ := json.Marshal(room.Room{Name: "Legacy Room", GameMode: proxyGameMode})
+	recorder := serveLegacyRequest(http.MethodPost, "/rooms", string(roomJSON), nil)
+
+	var rid string
+	err := json.Unmarshal(recorder.Body.Bytes(), &rid)
+	t.Cleanup(func() { room.DeleteRoom(context.Background(), rid) })
+
+	if recorder.Code != http.StatusOK || err != nil || rid == "" {
+		t.Fatalf(`POST /rooms = %d, %s, want 200 with a quoted RID`, recorder.Code, recorder.Body.String())
+	}
+
+	uid := createLegacyPlayer(t)
+	leaving := createLegacyPlayer(t)
+
+	accepted := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+	}{
+		{"Name", http.MethodPut, "/rooms/" + rid + "/name", "Renamed Room"},
+		{"Status", http.MethodPut, "/rooms/" + rid + "/status", room.StatusCreated},
+		{"Mode", http.MethodPut, "/rooms/" + rid + "/mode", proxyGameMode},
+		{"Rules", http.MethodPut, "/rooms/" + rid + "/rules", `{"rules":"First to ten"}`},
+		{"End", http.MethodPut, "/rooms/" + rid + "/end", ""},
+		{"Create", http.MethodPut, "/rooms/" + rid + "/create", ""},
+		{"Join", http.MethodPut, "/users/" + uid + "/room", rid},
+		{"JoinLeaving", http.MethodPut, "/users/" + leaving + "/room", rid},
+		{"Leave", http.MethodPut, "/users/" + leaving + "/leave", rid},
+	}
+
+	for _, test := range accepted {
+		recorder := serveLegacyRequest(test.method, test.path, test.body, nil)
+		if recorder.Code != http.StatusAccepted || recorder.Body.Len() != 0 {
+			t.Fatalf(`%s %s(%s) = %d, %q, want 202 with no body`, test.method, test.path, test.name, recorder.Code, recorder.Body.String())
+		}
+	}
+
+	recorder = serveLegacyRequest(http.MethodGet, "/rooms/"+rid, "", nil)
+	var found room.Room
+	err = json.Unmarshal(recorder.Body.Bytes(), &found)
+	if recorder.Code != http.StatusOK || err != nil || found.RID != rid || found.Name != "Renamed Room" {
+		t.Fatalf(`GET /rooms/:rid = %d, %s, want 200 with the renamed room`, recorder.Code, recorder.Body.String())
+	}
+
+	for _, path := range []string{"/rooms/" + rid + "/users", "/rooms/" + rid + "/game", "/rooms/" + rid + "/results"} {
+		recorder := serveLegacyRequest(http.MethodGet, path, "", nil)
+		if recorder.Code != http.StatusOK || !json.Valid(recorder.Body.Bytes()) {
+			t.Fatalf(`GET %s = %d, %s, want 200 with JSON`, path, recorder.Code, recorder.Body.String())
+		}
+	}
+
+	recorder = serveLegacyRequest(http.MethodDelete, "/rooms/"+rid, "", nil)
+	if recorder.Code != http.StatusAccepted {
+		t.Fatalf(`DELETE /rooms/:rid = %d, want 202`, recorder.Code)
+	}
+}
+
+func TestLegacyGameModes(t *testing.T) {
+	registry.SetGameModeSecrets(map[string]string{signedGameMode: proxySecret})
+	t.Cleanup(func() {
+		registry.SetGameModeSecrets(nil)
+		registry.RemoveGameMode(signedGameMode)
+	})
+
+	registration := `{"first":"` + signedGameMode + `","second":"` + signedGameModeURL + `"}`
+
+	recorder := serveLegacyRequest(http.MethodPost, "/gameModes", registration, nil)
+	if recorder.Code != http.StatusUnauthorized {
+		t.Fatalf(`POST /gameModes(Unsigned) = %d, want 401`, recorder.Code)
+	}
+
+	recorder = serveLegacyRequest(http.MethodPost, "/gameModes", registration, signLegacyRequest(registration))
+	if recorder.Code != http.StatusAccepted {
+		t.Fatalf(`POST /gameModes = %d, %s, want 202`, recorder.Code, recorder.Body.String())
+	}
+
+	recorder = serveLegacyRequest(http.MethodGet, "/gameModes", "", nil)
+	if recorder.Code != http.StatusOK || !strings.Contains(recorder.Body.String(), `"`+signedGameMode+`"`) {
+		t.Fatalf(`GET /gameModes = %d, %s, want 200 listing %s`, recorder.Code, recorder.Body.String(), signedGameMode)
+	}
+
+	heartbeat := "/gameModes/" + signedGameMode + "?url=" + signedGameModeURL
+	recorder = serveLegacyRequest(http.MethodPost, heartbeat, "", signLegacyRequest(""))
+	if recorder.Code != http.StatusAccepted {
+		t.Fatalf(`POST /gameModes/:gameMode = %d, %s, want 202`, recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestLegacyGameEvents(t *testing.T) {
+	registry.SetGameModeSecrets(map[string]string{proxyGameMode: proxySecret})
+	t.Cleanup(func() { registry.SetGameModeSecrets(nil) })
+
+	_, rid := setupGameServerTest(t)
+	path := "/games/" + rid + "/events"
+
+	tests := []struct {
+		name string
+		path string
+		body string
+		sign bool
+		want int
+	}{
+		{"Accepted", path, `{"kind":"scores_updated","scores":{"player":3}}`, true, http.StatusAccepted},
+		{"Unsigned", path, `{"kind":"scores_updated"}`, false, http.StatusUnauthorized},
+		{"UnknownKind", path, `{"kind":"exploded"}`, true, http.StatusBadRequest},
+		{"MissingRoom", "/games/" + uuid.NewString() + "/events", `{"kind":"scores_updated"}`, true, http.StatusNotFound},
+	}
+
+	for _, test := range tests {
+		var prepare func(request *http.Request)
+		if test.sign {
+			prepare = signLegacyRequest(test.body)
+		}
+
+		recorder := serveLegacyRequest(http.MethodPost, test.path, test.body, prepare)
+		if recorder.Code != test.want {
+			t.Fatalf(`POST %s(%s) = %d, %s, want %d`, test.path, test.name, recorder.Code, recorder.Body.String(), test.want)
+		}
+	}
+}
+
+func TestLegacyErrors(t *testing.T) {
+	missing := uuid.NewString()
+
+	tests := []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{http.MethodGet, "/rooms/" + missing, ""},
+		{http.MethodGet, "/rooms/" + missing + "/users", ""},
+		{http.MethodGet, "/rooms/" + missing + "/game", ""},
+		{http.MethodGet, "/rooms/" + missing + "/results", ""},
+		{http.MethodGet, "/gameModes/Missing", ""},
+		{http.MethodPost, "/users/" + missing, ""},
+		{http.MethodPut, "/users/" + missing + "/name", "Name"},
+		{http.MethodPut, "/users/" + missing + "/room", missing},
+		{http.MethodPut, "/rooms/" + missing + "/name", "Name"},
+		{http.MethodPut, "/rooms/" + missing + "/create", ""},
+		{http.MethodDelete, "/rooms/" + missing, ""},
+	}
+
+	for _, test := range tests {
+		recorder := serveLegacyRequest(test.method, test.path, test.body, nil)
+		if recorder.Code != http.StatusInternalServerError || !strings.HasPrefix(recorder.Header().Get("Content-Type"), "text/plain") || strings.TrimSpace(recorder.Body.String()) == "" {
+			t.Fatalf(`%s %s = %d, %q, want 500 with a plain text error`, test.method, test.path, recorder.Code, recorder.Body.String())
+		}
+	}
+}
+
+func createLegacyPlayer(t *testing.T) string {
+	uid, _ := user.CreateUser("Legacy Player")
+	t.Cleanup(func() {
+		lobby.RemoveUserFromAllRooms(context.Background(), uid)
+		user.DeleteUser(uid)
+	})
+
+	return uid
+}
+
+// serveLegacyRequest sends body as is, since the legacy routes take plain
+// text as often as JSON.
+func serveLegacyRequest(method string, path string, body string, prepare func(request *http.Request)) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, path, strings.NewReader(body))
+	if prepare != nil {
+		prepare(request)
+	}
+
+	recorder := httptest.NewRecorder()
+	newRouter().ServeHTTP(recorder, request)
+
+	return recorder
+}
+
+func signLegacyRequest(body string) func(request *http.Request) {
+	return func(request *http.Request) {
+		utils.SignRequest(request, proxySecret, []byte(body))
+	}
+}
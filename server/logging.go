@@ -0,0 +1,77 @@
+package server
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"Engee-Server/logging"
+)
+
+const maxRequestIDLength = 128
+
+// RequestIDMiddleWare keeps the caller's X-Request-ID, or assigns one, and
+// carries it with the UID and the RID into the request context so every log
+// line and game server call made for the request can be traced. Only a UID
+// proven by the request's token is forwarded to game servers.
+func RequestIDMiddleWare() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Request.Header.Get(logging.RequestIDHeader)
+		if !validRequestID(id) {
+			id = logging.NewRequestID()
+		}
+
+		c.Header(logging.RequestIDHeader, id)
+
+		ctx := logging.WithRequestID(c.Request.Context(), id)
+
+		uid := authenticatedUID(c.Request)
+		if uid != "" {
+			ctx = logging.WithCallerUID(ctx, uid)
+		} else {
+			uid = c.Param("uid")
+		}
+
+		if uid != "" {
+			ctx = logging.WithUID(ctx, uid)
+		}
+
+		rid := c.Param("rid")
+		if rid != "" {
+			ctx = logging.WithRID(ctx, rid)
+		}
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+func AccessLogMiddleWare() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		slog.InfoContext(c.Request.Context(), "Handled request",
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"status", c.Writer.Status(),
+			"duration", time.Since(start),
+			"client_ip", c.ClientIP(),
+		)
+	}
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for _, char := range id {
+		if char <= ' ' || char > '~' {
+			return false
+		}
+	}
+
+	return true
+}
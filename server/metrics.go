@@ -0,0 +1,62 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	registry "Engee-Server/gameRegistry"
+	"Engee-Server/lobby"
+	"Engee-Server/metrics"
+	"Engee-Server/room"
+	"Engee-Server/user"
+)
+
+var requestDuration = metrics.NewHistogram(
+	"engee_http_request_duration_seconds",
+	"Latency of HTTP requests by route.",
+	metrics.DefaultBuckets,
+	"method", "route", "status",
+)
+
+func init() {
+	metrics.NewGaugeFunc("engee_users", "Users currently connected.", func() float64 {
+		return float64(user.CountUsers())
+	})
+	metrics.NewGaugeFunc("engee_rooms", "Rooms currently open.", func() float64 {
+		return float64(room.CountRooms())
+	})
+	metrics.NewGaugeFunc("engee_lobbies", "Rooms with at least one user in their lobby.", func() float64 {
+		return float64(lobby.CountLobbies())
+	})
+	metrics.NewGaugeFunc("engee_gamemodes", "Game modes currently registered.", func() float64 {
+		return float64(len(registry.GetGameModes()))
+	})
+	metrics.NewGaugeFunc("engee_gamemode_instances", "Game mode instances currently registered.", func() float64 {
+		return float64(registry.CountGameModeInstances())
+	})
+}
+
+// MetricsMiddleWare records request latency under the route pattern rather
+// than the raw path, so IDs in the URL do not create a series each.
+func MetricsMiddleWare() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		requestDuration.ObserveSince(start, c.Request.Method, route, strconv.Itoa(c.Writer.Status()))
+	}
+}
+
+func getMetrics(c *gin.Context) {
+	c.Writer.Header().Set("Content-Type", metrics.ContentType)
+	c.Writer.WriteHeader(http.StatusOK)
+	metrics.WriteText(c.Writer)
+}
@@ -0,0 +1,567 @@
+package server
+
+import (
+	"crypto/sha512"
+	_ "embed"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+
+	gameclient "Engee-Server/gameClient"
+	registry "Engee-Server/gameRegistry"
+	"Engee-Server/room"
+	"Engee-Server/user"
+	"Engee-Server/utils"
+)
+
+const openAPIVersion = "3.0.3"
+const apiVersion = "1.0.0"
+const modulePath = "Engee-Server"
+
+const (
+	securityAdmin     = "adminToken"
+	securityUser      = "userToken"
+	securitySignature = "gameModeSignature"
+)
+
+// rawText marks request bodies that legacy routes read as plain text.
+type rawText string
+
+type apiRoute struct {
+	Method      string
+	Path        string
+	Summary     string
+	Tag         string
+	Query       []string
+	Request     reflect.Type
+	Response    reflect.Type
+	Status      int
+	ContentType string
+	Security    string
+	Deprecated  bool
+}
+
+var apiRoutes = []apiRoute{
+	{Method: http.MethodPost, Path: "/v1/users", Tag: "users", Summary: "Create a user and issue its session token",
+		Request: reflect.TypeFor[CreateUserRequest](), Response: reflect.TypeFor[CreateUserResponse](), Status: http.StatusCreated},
+	{Method: http.MethodGet, Path: "/v1/users/:uid", Tag: "users", Summary: "Get a user",
+		Response: reflect.TypeFor[UserResponse](), Status: http.StatusOK},
+	{Method: http.MethodPatch, Path: "/v1/users/:uid", Tag: "users", Summary: "Update a user's name or status",
+		Request: reflect.TypeFor[UpdateUserRequest](), Response: reflect.TypeFor[UserResponse](), Status: http.StatusOK},
+	{Method: http.MethodPost, Path: "/v1/users/:uid/heartbeat", Tag: "users", Summary: "Keep a user alive",
+		Response: reflect.TypeFor[UserResponse](), Status: http.StatusOK},
+	{Method: http.MethodDelete, Path: "/v1/users/:uid", Tag: "users", Summary: "Delete a user and remove them from their rooms",
+		Status: http.StatusNoContent},
+
+	{Method: http.MethodGet, Path: "/v1/rooms", Tag: "rooms", Summary: "List rooms a page at a time",
+		Query: append(roomFilterQueryParams, pageQueryParams...), Response: reflect.TypeFor[RoomListResponse](), Status: http.StatusOK},
+	{Method: http.MethodPost, Path: "/v1/rooms", Tag: "rooms", Summary: "Create a room and its game instance",
+		Request: reflect.TypeFor[CreateRoomRequest](), Response: reflect.TypeFor[RoomResponse](), Status: http.StatusCreated},
+	{Method: http.MethodGet, Path: "/v1/rooms/:rid", Tag: "rooms", Summary: "Get a room",
+		Response: reflect.TypeFor[RoomResponse](), Status: http.StatusOK},
+	{Method: http.MethodPatch, Path: "/v1/rooms/:rid", Tag: "rooms", Summary: "Update a room's name or status",
+		Request: reflect.TypeFor[UpdateRoomRequest](), Response: reflect.TypeFor[RoomResponse](), Status: http.StatusOK},
+	{Method: http.MethodDelete, Path: "/v1/rooms/:rid", Tag: "rooms", Summary: "Delete a room and end its game instance",
+		Status: http.StatusNoContent},
+	{Method: http.MethodPut, Path: "/v1/rooms/:rid/mode", Tag: "rooms", Summary: "Switch a room's game mode",
+		Request: reflect.TypeFor[RoomGameModeRequest](), Response: reflect.TypeFor[RoomResponse](), Status: http.StatusOK},
+	{Method: http.MethodPut, Path: "/v1/rooms/:rid/rules", Tag: "rooms", Summary: "Set a room's game rules",
+		Request: reflect.TypeFor[RulesRequest](), Response: reflect.TypeFor[RulesResponse](), Status: http.StatusOK},
+	{Method: http.MethodPost, Path: "/v1/rooms/:rid/game", Tag: "rooms", Summary: "Create a room's game instance",
+		Response: reflect.TypeFor[RoomResponse](), Status: http.StatusOK},
+	{Method: http.MethodGet, Path: "/v1/rooms/:rid/game", Tag: "rooms", Summary: "Get a room's game state",
+		Response: reflect.TypeFor[gameclient.GameState](), Status: http.StatusOK},
+	{Method: http.MethodGet, Path: "/v1/rooms/:rid/results", Tag: "rooms", Summary: "Get a room's results",
+		Response: reflect.TypeFor[room.RoomResults](), Status: http.StatusOK},
+	{Method: http.MethodGet, Path: "/v1/rooms/:rid/users", Tag: "rooms", Summary: "List the users in a room",
+		Response: reflect.TypeFor[[]UserResponse](), Status: http.StatusOK},
+	{Method: http.MethodPost, Path: "/v1/rooms/:rid/users", Tag: "rooms", Summary: "Join a user to a room",
+		Request: reflect.TypeFor[MembershipRequest](), Response: reflect.TypeFor[[]UserResponse](), Status: http.StatusOK},
+	{Method: http.MethodDelete, Path: "/v1/rooms/:rid/users/:uid", Tag: "rooms", Summary: "Remove a user from a room",
+		Status: http.StatusNoContent},
+	{Method: http.MethodGet, Path: "/v1/rooms/:rid/events", Tag: "rooms", Summary: "Stream a room's events",
+		Response: reflect.TypeFor[room.RoomEvent](), Status: http.StatusOK, ContentType: "text/event-stream"},
+	{Method: http.MethodGet, Path: "/v1/rooms/:rid/play", Tag: "rooms", Summary: "Proxy a player to the room's game server",
+		Status: http.StatusOK, Security: securityUser},
+
+	{Method: http.MethodGet, Path: "/v1/gamemodes", Tag: "gamemodes", Summary: "List registered game modes",
+		Response: reflect.TypeFor[[]string](), Status: http.StatusOK},
+	{Method: http.MethodPost, Path: "/v1/gamemodes", Tag: "gamemodes", Summary: "Register a game server instance",
+		Request: reflect.TypeFor[RegisterGameModeRequest](), Response: reflect.TypeFor[GameModeResponse](), Status: http.StatusCreated, Security: securitySignature},
+	{Method: http.MethodGet, Path: "/v1/gamemodes/events", Tag: "gamemodes", Summary: "Stream registry events",
+		Response: reflect.TypeFor[registry.RegistryEvent](), Status: http.StatusOK, ContentType: "text/event-stream"},
+	{Method: http.MethodGet, Path: "/v1/gamemodes/:gameMode", Tag: "gamemodes", Summary: "List a game mode's instances",
+		Response: reflect.TypeFor[[]GameModeResponse](), Status: http.StatusOK},
+	{Method: http.MethodPost, Path: "/v1/gamemodes/:gameMode/heartbeat", Tag: "gamemodes", Summary: "Report a game server instance as alive",
+		Request: reflect.TypeFor[GameModeHeartbeatRequest](), Response: reflect.TypeFor[GameModeResponse](), Status: http.StatusOK, Security: securitySignature},
+
+	{Method: http.MethodPost, Path: "/v1/games/:rid/events", Tag: "games", Summary: "Report a game event and get the room's results",
+		Request: reflect.TypeFor[json.RawMessage](), Response: reflect.TypeFor[room.RoomResults](), Status: http.StatusOK, Security: securitySignature},
+
+	{Method: http.MethodGet, Path: "/admin/orphans", Tag: "admin", Summary: "List orphaned game instances",
+		Response: reflect.TypeFor[[]room.OrphanedGame](), Status: http.StatusOK, Security: securityAdmin},
+	{Method: http.MethodPost, Path: "/admin/orphans/:rid/cleanup", Tag: "admin", Summary: "Retry cleaning up a room's orphaned game instances",
+		Status: http.StatusAccepted, Security: securityAdmin},
+	{Method: http.MethodGet, Path: "/admin/users", Tag: "admin", Summary: "List users a page at a time",
+		Query: append(userFilterQueryParams, pageQueryParams...), Response: reflect.TypeFor[UserListResponse](), Status: http.StatusOK, Security: securityAdmin},
+	{Method: http.MethodPost, Path: "/admin/rooms/:rid/migrate", Tag: "admin", Summary: "Move a room to another game server instance",
+		Request: reflect.TypeFor[MigrateRoomRequest](), Response: reflect.TypeFor[room.Room](), Status: http.StatusOK, Security: securityAdmin},
+
+	{Method: http.MethodGet, Path: "/healthz", Tag: "meta", Summary: "Report that the process is alive",
+		Response: reflect.TypeFor[HealthResponse](), Status: http.StatusOK},
+	{Method: http.MethodGet, Path: "/readyz", Tag: "meta", Summary: "Report whether the server can take traffic",
+		Response: reflect.TypeFor[HealthResponse](), Status: http.StatusOK},
+	{Method: http.MethodGet, Path: "/status", Tag: "meta", Summary: "Summarise users, rooms, lobbies and game modes",
+		Response: reflect.TypeFor[StatusResponse](), Status: http.StatusOK},
+	{Method: http.MethodGet, Path: "/openapi.json", Tag: "meta", Summary: "Get this OpenAPI document",
+		Response: reflect.TypeFor[map[string]any](), Status: http.StatusOK},
+	{Method: http.MethodGet, Path: "/docs", Tag: "meta", Summary: "Browse this API",
+		Response: reflect.TypeFor[string](), Status: http.StatusOK, ContentType: "text/html"},
+	{Method: http.MethodGet, Path: "/docs/explorer.js", Tag: "meta", Summary: "Get the script behind /docs",
+		Response: reflect.TypeFor[string](), Status: http.StatusOK, ContentType: "text/javascript"},
+	{Method: http.MethodGet, Path: "/docs/explorer.css", Tag: "meta", Summary: "Get the stylesheet behind /docs",
+		Response: reflect.TypeFor[string](), Status: http.StatusOK, ContentType: "text/css"},
+	{Method: http.MethodGet, Path: "/metrics", Tag: "meta", Summary: "Get metrics in the Prometheus text format",
+		Response: reflect.TypeFor[string](), Status: http.StatusOK, ContentType: "text/plain"},
+
+	{Method: http.MethodPost, Path: "/users", Tag: "legacy", Summary: "Create a user from a plain-text name",
+		Request: reflect.TypeFor[rawText](), Response: reflect.TypeFor[string](), Status: http.StatusOK, Deprecated: true},
+	{Method: http.MethodPost, Path: "/rooms", Tag: "legacy", Summary: "Create a room",
+		Request: reflect.TypeFor[room.Room](), Response: reflect.TypeFor[string](), Status: http.StatusOK, Deprecated: true},
+	{Method: http.MethodPost, Path: "/users/:id", Tag: "legacy", Summary: "Keep a user alive",
+		Status: http.StatusAccepted, Deprecated: true},
+	{Method: http.MethodGet, Path: "/rooms", Tag: "legacy", Summary: "List rooms",
+		Response: reflect.TypeFor[[]room.Room](), Status: http.StatusOK, Deprecated: true},
+	{Method: http.MethodGet, Path: "/rooms/:rid/users", Tag: "legacy", Summary: "List the users in a room",
+		Response: reflect.TypeFor[[]user.User](), Status: http.StatusOK, Deprecated: true},
+	{Method: http.MethodGet, Path: "/rooms/:rid/events", Tag: "legacy", Summary: "Stream a room's events",
+		Response: reflect.TypeFor[room.RoomEvent](), Status: http.StatusOK, ContentType: "text/event-stream", Deprecated: true},
+	{Method: http.MethodGet, Path: "/rooms/:rid/results", Tag: "legacy", Summary: "Get a room's results",
+		Response: reflect.TypeFor[room.RoomResults](), Status: http.StatusOK, Deprecated: true},
+	{Method: http.MethodGet, Path: "/rooms/:rid/game", Tag: "legacy", Summary: "Get a room's game state",
+		Response: reflect.TypeFor[gameclient.GameState](), Status: http.StatusOK, Deprecated: true},
+	{Method: http.MethodGet, Path: "/rooms/:rid/play", Tag: "legacy", Summary: "Proxy a player to the room's game server",
+		Status: http.StatusOK, Security: securityUser, Deprecated: true},
+	{Method: http.MethodGet, Path: "/rooms/:rid", Tag: "legacy", Summary: "Get a room",
+		Response: reflect.TypeFor[room.Room](), Status: http.StatusOK, Deprecated: true},
+	{Method: http.MethodGet, Path: "/gameModes", Tag: "legacy", Summary: "List registered game modes",
+		Response: reflect.TypeFor[[]string](), Status: http.StatusOK, Deprecated: true},
+	{Method: http.MethodPost, Path: "/gameModes", Tag: "legacy", Summary: "Register a game server instance",
+		Request: reflect.TypeFor[legacyGameModeRegistration](), Status: http.StatusAccepted, Security: securitySignature, Deprecated: true},
+	{Method: http.MethodGet, Path: "/gameModes/events", Tag: "legacy", Summary: "Stream registry events",
+		Response: reflect.TypeFor[registry.RegistryEvent](), Status: http.StatusOK, ContentType: "text/event-stream", Deprecated: true},
+	{Method: http.MethodGet, Path: "/gameModes/:gameMode", Tag: "legacy", Summary: "List a game mode's instances",
+		Response: reflect.TypeFor[[]registry.GameMode](), Status: http.StatusOK, Deprecated: true},
+	{Method: http.MethodPost, Path: "/gameModes/:gameMode", Tag: "legacy", Summary: "Report a game server instance as alive",
+		Query: []string{"version", "url", "boot_id"}, Status: http.StatusAccepted, Security: securitySignature, Deprecated: true},
+	{Method: http.MethodPost, Path: "/games/:rid/events", Tag: "legacy", Summary: "Report a game event",
+		Request: reflect.TypeFor[json.RawMessage](), Status: http.StatusAccepted, Security: securitySignature, Deprecated: true},
+	{Method: http.MethodPut, Path: "/users/:uid/name", Tag: "legacy", Summary: "Rename a user from a plain-text name",
+		Request: reflect.TypeFor[rawText](), Status: http.StatusAccepted, Deprecated: true},
+	{Method: http.MethodPut, Path: "/users/:uid/room", Tag: "legacy", Summary: "Join a user to the plain-text RID",
+		Request: reflect.TypeFor[rawText](), Status: http.StatusAccepted, Deprecated: true},
+	{Method: http.MethodPut, Path: "/users/:uid/leave", Tag: "legacy", Summary: "Remove a user from the plain-text RID",
+		Request: reflect.TypeFor[rawText](), Status: http.StatusAccepted, Deprecated: true},
+	{Method: http.MethodPut, Path: "/rooms/:rid/name", Tag: "legacy", Summary: "Rename a room from a plain-text name",
+		Request: reflect.TypeFor[rawText](), Status: http.StatusAccepted, Deprecated: true},
+	{Method: http.MethodPut, Path: "/rooms/:rid/status", Tag: "legacy", Summary: "Set a room's plain-text status",
+		Request: reflect.TypeFor[rawText](), Status: http.StatusAccepted, Deprecated: true},
+	{Method: http.MethodPut, Path: "/rooms/:rid/mode", Tag: "legacy", Summary: "Switch a room's game mode",
+		Request: reflect.TypeFor[RoomGameModeRequest](), Status: http.StatusAccepted, Deprecated: true},
+	{Method: http.MethodPut, Path: "/rooms/:rid/rules", Tag: "legacy", Summary: "Set a room's game rules",
+		Request: reflect.TypeFor[RulesRequest](), Status: http.StatusAccepted, Deprecated: true},
+	{Method: http.MethodPut, Path: "/rooms/:rid/create", Tag: "legacy", Summary: "Create a room's game instance",
+		Status: http.StatusAccepted, Deprecated: true},
+	{Method: http.MethodPut, Path: "/rooms/:rid/end", Tag: "legacy", Summary: "End a room's game",
+		Status: http.StatusAccepted, Deprecated: true},
+	{Method: http.MethodDelete, Path: "/users/:uid", Tag: "legacy", Summary: "Delete a user",
+		Status: http.StatusAccepted, Deprecated: true},
+	{Method: http.MethodDelete, Path: "/rooms/:rid", Tag: "legacy", Summary: "Delete a room",
+		Status: http.StatusAccepted, Deprecated: true},
+}
+
+type openAPIDocument struct {
+	OpenAPI    string                          `json:"openapi"`
+	Info       openAPIInfo                     `json:"info"`
+	Paths      map[string]map[string]operation `json:"paths"`
+	Components components                      `json:"components"`
+}
+
+type openAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type operation struct {
+	Summary     string                `json:"summary"`
+	Tags        []string              `json:"tags"`
+	OperationID string                `json:"operationId"`
+	Parameters  []parameter           `json:"parameters,omitempty"`
+	RequestBody *requestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]response   `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+	Deprecated  bool                  `json:"deprecated,omitempty"`
+}
+
+type parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Schema   *schema `json:"schema"`
+}
+
+type requestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]mediaType `json:"content"`
+}
+
+type response struct {
+	Description string               `json:"description"`
+	Content     map[string]mediaType `json:"content,omitempty"`
+}
+
+type mediaType struct {
+	Schema *schema `json:"schema"`
+}
+
+type components struct {
+	Schemas         map[string]*schema        `json:"schemas"`
+	SecuritySchemes map[string]securityScheme `json:"securitySchemes"`
+}
+
+type securityScheme struct {
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	In          string `json:"in"`
+	Description string `json:"description,omitempty"`
+}
+
+type schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Items                *schema            `json:"items,omitempty"`
+	Properties           map[string]*schema `json:"properties,omitempty"`
+	AdditionalProperties *schema            `json:"additionalProperties,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+}
+
+var openAPIOnce sync.Once
+var openAPISpec []byte
+
+func getOpenAPI(c *gin.Context) {
+	openAPIOnce.Do(func() {
+		var err error
+		openAPISpec, err = json.Marshal(buildOpenAPI(apiRoutes))
+		if err != nil {
+			slog.ErrorContext(c.Request.Context(), "Marshalling OpenAPI document", "error", err)
+		}
+	})
+
+	if openAPISpec == nil {
+		http.Error(c.Writer, "Failed to build OpenAPI document", http.StatusInternalServerError)
+		return
+	}
+
+	c.Writer.Header().Set("Content-Type", "application/json")
+
+	err := sendReply(c.Writer, "GET openapi", openAPISpec, http.StatusOK)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Sending reply", "error", err)
+	}
+}
+
+// The explorer is served from this binary so /docs loads no third-party
+// code. Its assets are pinned by subresource integrity hashes computed from
+// the embedded files.
+//
+//go:embed explorer/explorer.js
+var explorerScript []byte
+
+//go:embed explorer/explorer.css
+var explorerStyle []byte
+
+var apiExplorerPage = fmt.Sprintf(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Engee Server API</title>
+<link rel="stylesheet" href="docs/explorer.css" integrity="%s">
+</head>
+<body>
+<div id="explorer"></div>
+<script src="docs/explorer.js" integrity="%s"></script>
+</body>
+</html>
+`, subresourceIntegrity(explorerStyle), subresourceIntegrity(explorerScript))
+
+func subresourceIntegrity(asset []byte) string {
+	sum := sha512.Sum384(asset)
+	return "sha384-" + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+func getAPIExplorer(c *gin.Context) {
+	c.Writer.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	err := sendReply(c.Writer, "GET docs", []byte(apiExplorerPage), http.StatusOK)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Sending reply", "error", err)
+	}
+}
+
+func getExplorerScript(c *gin.Context) {
+	sendExplorerAsset(c, "GET docs/explorer.js", "text/javascript; charset=utf-8", explorerScript)
+}
+
+func getExplorerStyle(c *gin.Context) {
+	sendExplorerAsset(c, "GET docs/explorer.css", "text/css; charset=utf-8", explorerStyle)
+}
+
+func sendExplorerAsset(c *gin.Context, request string, contentType string, asset []byte) {
+	c.Writer.Header().Set("Content-Type", contentType)
+
+	err := sendReply(c.Writer, request, asset, http.StatusOK)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Sending reply", "error", err)
+	}
+}
+
+func buildOpenAPI(routes []apiRoute) openAPIDocument {
+	doc := openAPIDocument{
+		OpenAPI: openAPIVersion,
+		Info: openAPIInfo{
+			Title:   "Engee Server",
+			Version: apiVersion,
+		},
+		Paths: make(map[string]map[string]operation),
+		Components: components{
+			Schemas: make(map[string]*schema),
+			SecuritySchemes: map[string]securityScheme{
+				securityAdmin: {Type: "apiKey", Name: AdminTokenHeader, In: "header"},
+				securityUser:  {Type: "apiKey", Name: TokenHeader, In: "header"},
+				securitySignature: {Type: "apiKey", Name: utils.SignatureHeader, In: "header",
+					Description: fmt.Sprintf("HMAC of the %s header and request body using the game mode secret", utils.TimestampHeader)},
+			},
+		},
+	}
+
+	for _, route := range routes {
+		path := openAPIPath(route.Path)
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = make(map[string]operation)
+		}
+
+		doc.Paths[path][strings.ToLower(route.Method)] = newOperation(route, doc.Components.Schemas)
+	}
+
+	return doc
+}
+
+func newOperation(route apiRoute, schemas map[string]*schema) operation {
+	op := operation{
+		Summary:     route.Summary,
+		Tags:        []string{route.Tag},
+		OperationID: operationID(route),
+		Responses:   make(map[string]response),
+		Deprecated:  route.Deprecated,
+	}
+
+	for _, segment := range strings.Split(route.Path, "/") {
+		if strings.HasPrefix(segment, ":") {
+			op.Parameters = append(op.Parameters, parameter{
+				Name:     segment[1:],
+				In:       "path",
+				Required: true,
+				Schema:   &schema{Type: "string"},
+			})
+		}
+	}
+
+	for _, name := range route.Query {
+		op.Parameters = append(op.Parameters, parameter{
+			Name:   name,
+			In:     "query",
+			Schema: &schema{Type: "string"},
+		})
+	}
+
+	if route.Request != nil {
+		contentType := "application/json"
+		if route.Request == reflect.TypeFor[rawText]() {
+			contentType = "text/plain"
+		}
+
+		op.RequestBody = &requestBody{
+			Required: true,
+			Content: map[string]mediaType{
+				contentType: {Schema: schemaFor(route.Request, schemas)},
+			},
+		}
+	}
+
+	success := response{Description: http.StatusText(route.Status)}
+	if route.Response != nil {
+		contentType := route.ContentType
+		if contentType == "" {
+			contentType = "application/json"
+		}
+
+		success.Content = map[string]mediaType{
+			contentType: {Schema: schemaFor(route.Response, schemas)},
+		}
+	}
+	op.Responses[fmt.Sprint(route.Status)] = success
+
+	failure := response{Description: "Error"}
+	if !route.Deprecated && route.Tag != "admin" {
+		failure.Content = map[string]mediaType{
+			"application/json": {Schema: schemaFor(reflect.TypeFor[ErrorResponse](), schemas)},
+		}
+	}
+	op.Responses["default"] = failure
+
+	if route.Security != "" {
+		op.Security = []map[string][]string{{route.Security: {}}}
+	}
+
+	return op
+}
+
+func openAPIPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
+
+func operationID(route apiRoute) string {
+	id := strings.ToLower(route.Method)
+	for _, segment := range strings.Split(route.Path, "/") {
+		segment = strings.TrimPrefix(segment, ":")
+		segment = strings.NewReplacer(".", "", "_", "").Replace(segment)
+		if segment != "" {
+			id += strings.ToUpper(segment[:1]) + segment[1:]
+		}
+	}
+
+	if route.Deprecated {
+		id = "legacy" + strings.ToUpper(id[:1]) + id[1:]
+	}
+
+	return id
+}
+
+func schemaFor(t reflect.Type, schemas map[string]*schema) *schema {
+	switch t {
+	case reflect.TypeFor[time.Time]():
+		return &schema{Type: "string", Format: "date-time"}
+	case reflect.TypeFor[json.RawMessage]():
+		return &schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		s := schemaFor(t.Elem(), schemas)
+		if s.Ref != "" {
+			return s
+		}
+
+		s.Nullable = true
+		return s
+	case reflect.String:
+		return &schema{Type: "string"}
+	case reflect.Bool:
+		return &schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &schema{Type: "string", Format: "byte"}
+		}
+
+		return &schema{Type: "array", Items: schemaFor(t.Elem(), schemas)}
+	case reflect.Map:
+		return &schema{Type: "object", AdditionalProperties: schemaFor(t.Elem(), schemas)}
+	case reflect.Struct:
+		return structSchema(t, schemas)
+	}
+
+	return &schema{}
+}
+
+func structSchema(t reflect.Type, schemas map[string]*schema) *schema {
+	if t.Name() == "" {
+		return objectSchema(t, schemas)
+	}
+
+	name := schemaName(t)
+	ref := &schema{Ref: "#/components/schemas/" + name}
+
+	_, found := schemas[name]
+	if found {
+		return ref
+	}
+
+	// Reserve the name first so self-referencing types terminate.
+	schemas[name] = &schema{}
+	*schemas[name] = *objectSchema(t, schemas)
+
+	return ref
+}
+
+// schemaName qualifies a type's name with its package, relative to this
+// module, so that same-named types from different packages get separate
+// schemas, e.g. room.Room and gameClient.payload.Rules.
+func schemaName(t reflect.Type) string {
+	pkg := strings.TrimPrefix(t.PkgPath(), modulePath+"/")
+	name := strings.ReplaceAll(pkg, "/", ".") + "." + t.Name()
+
+	return strings.Map(func(r rune) rune {
+		if r == '.' || r == '-' || r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+func objectSchema(t reflect.Type, schemas map[string]*schema) *schema {
+	object := &schema{
+		Type:       "object",
+		Properties: make(map[string]*schema),
+	}
+
+	for _, field := range reflect.VisibleFields(t) {
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		object.Properties[name] = schemaFor(field.Type, schemas)
+	}
+
+	return object
+}
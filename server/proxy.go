@@ -0,0 +1,128 @@
+package server
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	registry "Engee-Server/gameRegistry"
+	"Engee-Server/lobby"
+	"Engee-Server/logging"
+	"Engee-Server/room"
+	"Engee-Server/user"
+	"Engee-Server/utils"
+)
+
+const TokenHeader = "X-Engee-Token"
+const UIDHeader = "X-Engee-UID"
+
+func proxyRoomGame(c *gin.Context) {
+	w := c.Writer
+	ids := utils.GetRequestIDs(c.Request)
+	if len(ids) == 0 {
+		http.Error(w, "Failed to join game: no RID provided", http.StatusBadRequest)
+		return
+	}
+
+	rid := ids[0]
+
+	uid, err := user.AuthenticateUser(requestToken(c.Request))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to authenticate player: %v", err), http.StatusUnauthorized)
+		slog.ErrorContext(c.Request.Context(), "Authenticating player", "error", err)
+		return
+	}
+
+	err = lobby.RequireUserInRoom(uid, rid)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to join game: %v", err), http.StatusForbidden)
+		slog.ErrorContext(c.Request.Context(), "Checking player is in room", "error", err)
+		return
+	}
+
+	game, err := room.GetRoomGameInstance(rid)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to join game: %v", err), http.StatusNotFound)
+		slog.ErrorContext(c.Request.Context(), "Getting game instance", "error", err)
+		return
+	}
+
+	target, err := url.Parse(game.URL)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to join game: %v", err), http.StatusInternalServerError)
+		slog.ErrorContext(c.Request.Context(), "Parsing game instance URL", "error", err)
+		return
+	}
+
+	proxy := &httputil.ReverseProxy{
+		Director: func(request *http.Request) {
+			directToGame(request, target, game.GameMode, uid)
+		},
+		ErrorHandler: func(w http.ResponseWriter, request *http.Request, err error) {
+			http.Error(w, "Failed to reach game server", http.StatusBadGateway)
+			slog.ErrorContext(request.Context(), "Proxying to game server", "error", err)
+		},
+	}
+
+	proxy.ServeHTTP(w, c.Request)
+}
+
+func directToGame(request *http.Request, target *url.URL, gameMode string, uid string) {
+	query := request.URL.Query()
+	query.Del("token")
+	query.Set("uid", uid)
+
+	request.URL.Scheme = target.Scheme
+	request.URL.Host = target.Host
+	request.URL.Path = target.Path + "/play"
+	request.URL.RawPath = ""
+	request.URL.RawQuery = query.Encode()
+	request.Host = target.Host
+
+	request.Header.Del("Authorization")
+	request.Header.Del(TokenHeader)
+	request.Header.Del(utils.SignatureHeader)
+	request.Header.Del(utils.TimestampHeader)
+	request.Header.Set(UIDHeader, uid)
+	request.Header.Set(logging.CallerUIDHeader, uid)
+
+	secret := registry.GetGameModeSecret(gameMode)
+	if secret != "" {
+		utils.SignRequest(request, secret, []byte{})
+	}
+}
+
+// authenticatedUID returns the user whose token the request carries, or ""
+// when it carries no valid token.
+func authenticatedUID(request *http.Request) string {
+	token := requestToken(request)
+	if token == "" {
+		return ""
+	}
+
+	uid, err := user.AuthenticateUser(token)
+	if err != nil {
+		return ""
+	}
+
+	return uid
+}
+
+func requestToken(request *http.Request) string {
+	token := request.Header.Get(TokenHeader)
+	if token != "" {
+		return token
+	}
+
+	authorization := request.Header.Get("Authorization")
+	if strings.HasPrefix(authorization, "Bearer ") {
+		return strings.TrimPrefix(authorization, "Bearer ")
+	}
+
+	return request.URL.Query().Get("token")
+}
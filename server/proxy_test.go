@@ -0,0 +1,208 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	registry "Engee-Server/gameRegistry"
+	"Engee-Server/lobby"
+	"Engee-Server/room"
+	"Engee-Server/user"
+	"Engee-Server/utils"
+)
+
+const proxyGameMode = "Proxy"
+const proxySecret = "proxy-secret"
+
+type fakeGameServer struct {
+	lock      sync.Mutex
+	requests  int
+	path      string
+	query     map[string][]string
+	header    http.Header
+	signature error
+	server    *httptest.Server
+}
+
+func TestProxyTokenSources(t *testing.T) {
+	tests := []struct {
+		name  string
+		apply func(request *http.Request, token string)
+	}{
+		{"Header", func(request *http.Request, token string) { request.Header.Set(TokenHeader, token) }},
+		{"Bearer", func(request *http.Request, token string) { request.Header.Set("Authorization", "Bearer "+token) }},
+		{"Query", func(request *http.Request, token string) { request.URL.RawQuery = "token=" + token }},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			game, rid, uid, token := setupProxyTest(t)
+
+			response := serveProxyRequest(t, "/v1/rooms/"+rid+"/play", func(request *http.Request) {
+				test.apply(request, token)
+			})
+
+			if response.StatusCode != http.StatusOK || game.count() != 1 {
+				t.Fatalf(`GET /v1/rooms/:rid/play(%s) = %d after %d upstream calls, want 200 after 1`, test.name, response.StatusCode, game.count())
+			}
+
+			path, query, header := game.lastRequest()
+			if path != "/games/"+rid+"/play" || query["uid"][0] != uid || header.Get(UIDHeader) != uid {
+				t.Fatalf(`GET /v1/rooms/:rid/play(%s) forwarded %s %v %v, want /games/%s/play for %s`, test.name, path, query, header, rid, uid)
+			}
+
+			if header.Get(TokenHeader) != "" || header.Get("Authorization") != "" || query["token"] != nil {
+				t.Fatalf(`GET /v1/rooms/:rid/play(%s) forwarded %v %v, want token stripped`, test.name, query, header)
+			}
+		})
+	}
+}
+
+func TestProxySignsRequest(t *testing.T) {
+	registry.SetGameModeSecrets(map[string]string{proxyGameMode: proxySecret})
+	t.Cleanup(func() { registry.SetGameModeSecrets(nil) })
+
+	game, rid, _, token := setupProxyTest(t)
+
+	response := serveProxyRequest(t, "/rooms/"+rid+"/play", func(request *http.Request) {
+		request.Header.Set(TokenHeader, token)
+		request.Header.Set(utils.SignatureHeader, "forged")
+	})
+
+	if response.StatusCode != http.StatusOK || game.signatureError() != nil {
+		t.Fatalf(`GET /rooms/:rid/play(Secret) = %d, %v, want 200 with a valid signature`, response.StatusCode, game.signatureError())
+	}
+}
+
+func TestProxyUpstreamError(t *testing.T) {
+	game, rid, _, token := setupProxyTest(t)
+	game.server.Close()
+
+	response := serveProxyRequest(t, "/v1/rooms/"+rid+"/play", func(request *http.Request) {
+		request.Header.Set(TokenHeader, token)
+	})
+
+	if response.StatusCode != http.StatusBadGateway {
+		t.Fatalf(`GET /v1/rooms/:rid/play(UpstreamDown) = %d, want 502`, response.StatusCode)
+	}
+}
+
+func TestProxyRejectsUnauthenticated(t *testing.T) {
+	game, rid, _, _ := setupProxyTest(t)
+
+	response := serveProxyRequest(t, "/v1/rooms/"+rid+"/play", func(request *http.Request) {})
+
+	if response.StatusCode != http.StatusUnauthorized || game.count() != 0 {
+		t.Fatalf(`GET /v1/rooms/:rid/play(NoToken) = %d after %d upstream calls, want 401 after 0`, response.StatusCode, game.count())
+	}
+}
+
+func TestProxyRejectsOutsider(t *testing.T) {
+	game, rid, _, _ := setupProxyTest(t)
+
+	outsider := testUserToken(t)
+	response := serveProxyRequest(t, "/v1/rooms/"+rid+"/play", func(request *http.Request) {
+		request.Header.Set(TokenHeader, outsider)
+	})
+
+	if response.StatusCode != http.StatusForbidden || game.count() != 0 {
+		t.Fatalf(`GET /v1/rooms/:rid/play(Outsider) = %d after %d upstream calls, want 403 after 0`, response.StatusCode, game.count())
+	}
+}
+
+// serveProxyRequest goes through a real listener, since the reverse proxy
+// needs a ResponseWriter that supports CloseNotify.
+func serveProxyRequest(t *testing.T, path string, prepare func(request *http.Request)) *http.Response {
+	server := httptest.NewServer(newRouter())
+	t.Cleanup(server.Close)
+
+	request, _ := http.NewRequest(http.MethodGet, server.URL+path, nil)
+	prepare(request)
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatalf(`GET %s = %v, want response`, path, err)
+	}
+	response.Body.Close()
+
+	return response
+}
+
+// setupProxyTest creates a room on a fake game server and a player who has
+// joined it.
+func setupProxyTest(t *testing.T) (*fakeGameServer, string, string, string) {
+	game, rid := setupGameServerTest(t)
+
+	uid, _ := user.CreateUser("Proxy Player")
+	t.Cleanup(func() {
+		lobby.RemoveUserFromAllRooms(context.Background(), uid)
+		user.DeleteUser(uid)
+	})
+
+	err := lobby.JoinUserToRoom(uid, rid)
+	if err != nil {
+		t.Fatalf(`JoinUserToRoom(Proxy) = %v, want nil`, err)
+	}
+
+	token, err := user.IssueUserToken(uid)
+	if err != nil {
+		t.Fatalf(`IssueUserToken(%s) = %v, want nil`, uid, err)
+	}
+
+	return game, rid, uid, token
+}
+
+// setupGameServerTest registers a fake game server and creates a room on it.
+func setupGameServerTest(t *testing.T) (*fakeGameServer, string) {
+	game := &fakeGameServer{}
+	game.server = httptest.NewServer(http.HandlerFunc(game.handle))
+	t.Cleanup(game.server.Close)
+
+	registry.RegisterGameMode(proxyGameMode, game.server.URL)
+	t.Cleanup(func() { registry.RemoveGameMode(proxyGameMode) })
+
+	roomJSON, _ := json.Marshal(room.Room{Name: "Proxy Room", GameMode: proxyGameMode})
+	rid, err := room.CreateRoom(context.Background(), roomJSON)
+	if err != nil {
+		t.Fatalf(`CreateRoom(Proxy) = %v, want nil`, err)
+	}
+	t.Cleanup(func() { room.DeleteRoom(context.Background(), rid) })
+
+	return game, rid
+}
+
+func (g *fakeGameServer) handle(w http.ResponseWriter, r *http.Request) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	g.requests++
+	g.path = r.URL.Path
+	g.query = r.URL.Query()
+	g.header = r.Header.Clone()
+	g.signature = utils.VerifyRequest(r, proxySecret, []byte{})
+}
+
+func (g *fakeGameServer) count() int {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	return g.requests
+}
+
+func (g *fakeGameServer) lastRequest() (string, map[string][]string, http.Header) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	return g.path, g.query, g.header
+}
+
+func (g *fakeGameServer) signatureError() error {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	return g.signature
+}
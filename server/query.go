@@ -0,0 +1,93 @@
+package server
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"Engee-Server/room"
+	sErr "Engee-Server/stockErrors"
+	"Engee-Server/user"
+	"Engee-Server/utils"
+)
+
+var pageQueryParams = []string{"sort", "order", "cursor", "limit"}
+var roomFilterQueryParams = []string{"gamemode", "status", "name", "has_space", "private"}
+var userFilterQueryParams = []string{"status", "name"}
+
+func pageQuery(c *gin.Context) (utils.PageRequest, error) {
+	page := utils.PageRequest{
+		Sort:   c.Query("sort"),
+		Cursor: c.Query("cursor"),
+	}
+
+	switch c.Query("order") {
+	case "", "asc":
+	case "desc":
+		page.Descending = true
+	default:
+		return page, &sErr.InvalidValueError[string]{
+			Field: "order",
+			Value: c.Query("order"),
+		}
+	}
+
+	limit := c.Query("limit")
+	if limit != "" {
+		var err error
+		page.Limit, err = strconv.Atoi(limit)
+		if err != nil {
+			return page, &sErr.InvalidValueError[string]{
+				Field: "limit",
+				Value: limit,
+			}
+		}
+	}
+
+	return page, nil
+}
+
+func roomFilterQuery(c *gin.Context) (room.RoomFilter, error) {
+	filter := room.RoomFilter{
+		GameMode: c.Query("gamemode"),
+		Status:   c.Query("status"),
+		Name:     c.Query("name"),
+	}
+
+	var err error
+	filter.HasSpace, err = boolQuery(c, "has_space")
+	if err != nil {
+		return filter, err
+	}
+
+	filter.Private, err = boolQuery(c, "private")
+	if err != nil {
+		return filter, err
+	}
+
+	return filter, nil
+}
+
+func userFilterQuery(c *gin.Context) user.UserFilter {
+	return user.UserFilter{
+		Status: c.Query("status"),
+		Name:   c.Query("name"),
+	}
+}
+
+func boolQuery(c *gin.Context, name string) (*bool, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, &sErr.InvalidValueError[string]{
+			Field: name,
+			Value: value,
+		}
+	}
+
+	return &parsed, nil
+}
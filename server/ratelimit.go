@@ -0,0 +1,149 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"Engee-Server/ratelimit"
+	"Engee-Server/room"
+	sErr "Engee-Server/stockErrors"
+)
+
+var defaultLimiter *ratelimit.Limiter
+var routeLimiters = make(map[string]*ratelimit.Limiter)
+var trustedProxies []string
+
+// SetRateLimits replaces the request budgets. Routes are keyed as
+// "METHOD /path/:param"; routes without their own budget share the default.
+func SetRateLimits(config ratelimit.Config) error {
+	err := config.Validate()
+	if err != nil {
+		return err
+	}
+
+	defaultLimiter = nil
+	if config.Default.Limited() {
+		defaultLimiter = ratelimit.NewLimiter(config.Default)
+	}
+
+	routeLimiters = make(map[string]*ratelimit.Limiter)
+	for route, budget := range config.Routes {
+		routeLimiters[route] = nil
+		if budget.Limited() {
+			routeLimiters[route] = ratelimit.NewLimiter(budget)
+		}
+	}
+
+	return nil
+}
+
+// SetTrustedProxies lists the proxies whose X-Forwarded-For headers are
+// believed when identifying a client's IP. With none, the connecting
+// address is used, so clients cannot dodge limits by forging the header.
+func SetTrustedProxies(proxies []string) error {
+	for _, proxy := range proxies {
+		_, _, err := net.ParseCIDR(proxy)
+		if err != nil && net.ParseIP(proxy) == nil {
+			return &sErr.InvalidValueError[string]{
+				Field: "Trusted Proxy",
+				Value: proxy,
+			}
+		}
+	}
+
+	trustedProxies = proxies
+	return nil
+}
+
+// RateLimitMiddleWare spends a token from the calling client's bucket for
+// the route, rejecting the request with 429 once the bucket is empty. Every
+// request is charged to its client IP, and authenticated requests are also
+// charged to their user, so minting fresh tokens cannot escape the IP budget.
+func RateLimitMiddleWare() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.Request.Method + " " + c.FullPath()
+
+		limiter, found := routeLimiters[route]
+		if !found {
+			limiter = defaultLimiter
+		}
+
+		if limiter == nil {
+			c.Next()
+			return
+		}
+
+		client, allowed, wait := allowRequest(limiter, c)
+		if allowed {
+			c.Next()
+			return
+		}
+
+		retryAfter := int(math.Ceil(wait.Seconds()))
+		if retryAfter < 1 {
+			retryAfter = 1
+		}
+
+		err := &sErr.LimitExceededError{
+			Space:  "Requests",
+			Reason: fmt.Sprintf("retry after %v", time.Duration(retryAfter)*time.Second),
+		}
+		slog.WarnContext(c.Request.Context(), "Rate limited request", "route", route, "client", client, "retry_after", retryAfter)
+
+		c.Header("Retry-After", strconv.Itoa(retryAfter))
+		sendJSON(c, route, ErrorResponse{Error: err.Error()}, http.StatusTooManyRequests)
+		c.Abort()
+	}
+}
+
+// allowRequest spends a token from each of the client's buckets, stopping at
+// and returning the first bucket that is empty.
+func allowRequest(limiter *ratelimit.Limiter, c *gin.Context) (string, bool, time.Duration) {
+	for _, client := range clientKeys(c) {
+		allowed, wait := limiter.Allow(client)
+		if !allowed {
+			return client, false, wait
+		}
+	}
+
+	return "", true, 0
+}
+
+func clientKeys(c *gin.Context) []string {
+	keys := []string{"ip:" + c.ClientIP()}
+
+	uid := authenticatedUID(c.Request)
+	if uid != "" {
+		keys = append(keys, "uid:"+uid)
+	}
+
+	return keys
+}
+
+// ownedRoomInfo records owner on a legacy room creation body, replacing any
+// owner the caller sent. Bodies that do not parse are left for CreateRoom to
+// reject.
+func ownedRoomInfo(roomInfo []byte, owner string) []byte {
+	var newRoom room.Room
+	err := json.Unmarshal(roomInfo, &newRoom)
+	if err != nil {
+		return roomInfo
+	}
+
+	newRoom.Owner = owner
+
+	owned, err := json.Marshal(newRoom)
+	if err != nil {
+		return roomInfo
+	}
+
+	return owned
+}
@@ -0,0 +1,349 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"Engee-Server/cors"
+	gameclient "Engee-Server/gameClient"
+	"Engee-Server/lobby"
+	"Engee-Server/logging"
+	"Engee-Server/ratelimit"
+	"Engee-Server/room"
+	sErr "Engee-Server/stockErrors"
+	"Engee-Server/user"
+)
+
+var testBackend = gameclient.NewFakeBackend()
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	room.SetGameBackend(testBackend)
+	room.SetPlayerLookup(lobby.GetRoomPlayers)
+	code := m.Run()
+	os.Exit(code)
+}
+
+func TestOpenAPIDescribesRoutes(t *testing.T) {
+	documented := make(map[string]bool)
+	for _, route := range apiRoutes {
+		documented[route.Method+" "+route.Path] = true
+	}
+
+	for _, route := range newRouter().Routes() {
+		if !documented[route.Method+" "+route.Path] {
+			t.Fatalf(`apiRoutes(%s %s) = missing, want route described in the OpenAPI document`, route.Method, route.Path)
+		}
+	}
+}
+
+func TestOpenAPIRoutesRegistered(t *testing.T) {
+	registered := make(map[string]bool)
+	for _, route := range newRouter().Routes() {
+		registered[route.Method+" "+route.Path] = true
+	}
+
+	for _, route := range apiRoutes {
+		if !registered[route.Method+" "+route.Path] {
+			t.Fatalf(`newRouter(%s %s) = missing, want described route registered`, route.Method, route.Path)
+		}
+	}
+}
+
+func TestOpenAPISchemasResolve(t *testing.T) {
+	doc := buildOpenAPI(apiRoutes)
+
+	docJSON, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf(`buildOpenAPI(Marshal) = %v, want nil`, err)
+	}
+
+	for _, ref := range findRefs(docJSON) {
+		name := strings.TrimPrefix(ref, "#/components/schemas/")
+		if _, found := doc.Components.Schemas[name]; !found {
+			t.Fatalf(`buildOpenAPI(Ref) = %q unresolved, want schema defined`, ref)
+		}
+	}
+}
+
+func TestOpenAPISchemaNamesQualified(t *testing.T) {
+	type Room struct {
+		Local bool `json:"local"`
+	}
+
+	schemas := make(map[string]*schema)
+	roomRef := schemaFor(reflect.TypeFor[room.Room](), schemas).Ref
+	localRef := schemaFor(reflect.TypeFor[Room](), schemas).Ref
+
+	if roomRef == localRef || len(schemas) != 2 {
+		t.Fatalf(`schemaFor(room.Room, server.Room) = %q, %q, want separate schemas`, roomRef, localRef)
+	}
+}
+
+func TestOpenAPILegacyGameModeBody(t *testing.T) {
+	doc := buildOpenAPI(apiRoutes)
+
+	ref := doc.Paths["/gameModes"]["post"].RequestBody.Content["application/json"].Schema.Ref
+	body := doc.Components.Schemas[strings.TrimPrefix(ref, "#/components/schemas/")]
+	if body == nil || body.Properties["first"] == nil || body.Properties["second"] == nil {
+		t.Fatalf(`buildOpenAPI(POST /gameModes) = %q, want legacy body with "first" and "second"`, ref)
+	}
+}
+
+func TestGetOpenAPI(t *testing.T) {
+	recorder := serveTestRequest(http.MethodGet, "/openapi.json")
+
+	var doc openAPIDocument
+	err := json.Unmarshal(recorder.Body.Bytes(), &doc)
+	if recorder.Code != http.StatusOK || err != nil || doc.OpenAPI != openAPIVersion {
+		t.Fatalf(`GET /openapi.json = %d, %v, want 200 and OpenAPI %s document`, recorder.Code, err, openAPIVersion)
+	}
+
+	if _, found := doc.Paths["/v1/rooms/{rid}"]["get"]; !found {
+		t.Fatalf(`GET /openapi.json = %v, want /v1/rooms/{rid} described`, doc.Paths)
+	}
+}
+
+func TestGetAPIExplorer(t *testing.T) {
+	recorder := serveTestRequest(http.MethodGet, "/docs")
+
+	body := recorder.Body.String()
+	if recorder.Code != http.StatusOK || strings.Contains(body, "https://") {
+		t.Fatalf(`GET /docs = %d, %q, want 200 loading no third-party assets`, recorder.Code, body)
+	}
+
+	for _, asset := range []string{`src="docs/explorer.js"`, `href="docs/explorer.css"`} {
+		_, tag, found := strings.Cut(body, asset)
+		tag, _, _ = strings.Cut(tag, ">")
+		if !found || !strings.Contains(tag, `integrity="sha384-`) {
+			t.Fatalf(`GET /docs(%s) = %q, want integrity attribute`, asset, tag)
+		}
+	}
+}
+
+func TestExplorerAssetsMatchIntegrity(t *testing.T) {
+	page := serveTestRequest(http.MethodGet, "/docs").Body.String()
+
+	for _, path := range []string{"/docs/explorer.js", "/docs/explorer.css"} {
+		recorder := serveTestRequest(http.MethodGet, path)
+
+		hash := subresourceIntegrity(recorder.Body.Bytes())
+		if recorder.Code != http.StatusOK || !strings.Contains(page, `integrity="`+hash+`"`) {
+			t.Fatalf(`GET %s = %d, %s, want 200 matching the integrity pinned by /docs`, path, recorder.Code, hash)
+		}
+	}
+}
+
+func TestHealthz(t *testing.T) {
+	recorder := serveTestRequest(http.MethodGet, "/healthz")
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf(`GET /healthz = %d, want 200`, recorder.Code)
+	}
+}
+
+func TestReadyz(t *testing.T) {
+	recorder := serveTestRequest(http.MethodGet, "/readyz")
+
+	var response HealthResponse
+	err := json.Unmarshal(recorder.Body.Bytes(), &response)
+	if recorder.Code != http.StatusOK || err != nil || response.Checks["store"] != "ok" {
+		t.Fatalf(`GET /readyz = %d, %v, %v, want 200 with passing checks`, recorder.Code, response, err)
+	}
+}
+
+func TestReadyzRequiresGameMode(t *testing.T) {
+	SetReadinessOptions(true)
+	t.Cleanup(func() { SetReadinessOptions(false) })
+
+	recorder := serveTestRequest(http.MethodGet, "/readyz")
+
+	var response HealthResponse
+	json.Unmarshal(recorder.Body.Bytes(), &response)
+	if recorder.Code != http.StatusServiceUnavailable || response.Checks["game_modes"] == "ok" {
+		t.Fatalf(`GET /readyz(NoGameModes) = %d, %v, want 503 with failing game_modes check`, recorder.Code, response)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	uid, _ := user.CreateUser("Status User")
+	t.Cleanup(func() { user.DeleteUser(uid) })
+
+	recorder := serveTestRequest(http.MethodGet, "/status")
+
+	var response StatusResponse
+	err := json.Unmarshal(recorder.Body.Bytes(), &response)
+	if recorder.Code != http.StatusOK || err != nil || response.Users != 1 {
+		t.Fatalf(`GET /status = %d, %v, %v, want 200 with 1 user`, recorder.Code, response, err)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	serveTestRequest(http.MethodGet, "/healthz")
+
+	recorder := serveTestRequest(http.MethodGet, "/metrics")
+
+	body := recorder.Body.String()
+	if recorder.Code != http.StatusOK || !strings.Contains(body, "engee_users ") {
+		t.Fatalf(`GET /metrics = %d, %q, want 200 with user gauge`, recorder.Code, body)
+	}
+
+	if !strings.Contains(body, `engee_http_request_duration_seconds_count{method="GET",route="/healthz",status="200"}`) {
+		t.Fatalf(`GET /metrics = %q, want /healthz latency recorded`, body)
+	}
+}
+
+func TestRequestIDAssigned(t *testing.T) {
+	recorder := serveTestRequest(http.MethodGet, "/healthz")
+
+	if recorder.Header().Get(logging.RequestIDHeader) == "" {
+		t.Fatalf(`GET /healthz = %v, want %s assigned`, recorder.Header(), logging.RequestIDHeader)
+	}
+}
+
+func TestRequestIDPropagated(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	request.Header.Set(logging.RequestIDHeader, "caller-id")
+	newRouter().ServeHTTP(recorder, request)
+
+	if recorder.Header().Get(logging.RequestIDHeader) != "caller-id" {
+		t.Fatalf(`GET /healthz(RequestID) = %q, want "caller-id"`, recorder.Header().Get(logging.RequestIDHeader))
+	}
+}
+
+func TestCORSPreflight(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodOptions, "/v1/rooms", nil)
+	request.Header.Set("Origin", "https://example.com")
+	request.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	newRouter().ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusNoContent || recorder.Header().Get("Access-Control-Allow-Origin") == "" {
+		t.Fatalf(`OPTIONS /v1/rooms = %d, %v, want 204 with CORS headers`, recorder.Code, recorder.Header())
+	}
+}
+
+func TestCORSExposesServerHeaders(t *testing.T) {
+	err := SetCORSPolicy(cors.Policy{AllowedOrigins: []string{"https://example.com"}})
+	if err != nil {
+		t.Fatalf(`SetCORSPolicy(Origin) = %v, want nil`, err)
+	}
+	t.Cleanup(func() { SetCORSPolicy(cors.DefaultPolicy()) })
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	request.Header.Set("Origin", "https://example.com")
+	newRouter().ServeHTTP(recorder, request)
+
+	exposed := recorder.Header().Get("Access-Control-Expose-Headers")
+	if !strings.Contains(exposed, TokenHeader) || !strings.Contains(exposed, logging.RequestIDHeader) {
+		t.Fatalf(`GET /healthz(CORS) exposes %q, want %s and %s`, exposed, TokenHeader, logging.RequestIDHeader)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	err := SetRateLimits(ratelimit.Config{
+		Routes: map[string]ratelimit.Budget{"GET /healthz": {Rate: 0.5, Burst: 1}},
+	})
+	if err != nil {
+		t.Fatalf(`SetRateLimits(Healthz) = %v, want nil`, err)
+	}
+	t.Cleanup(func() { SetRateLimits(ratelimit.Config{}) })
+
+	router := newRouter()
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf(`GET /healthz(First) = %d, want 200`, recorder.Code)
+	}
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if recorder.Code != http.StatusTooManyRequests || recorder.Header().Get("Retry-After") != "2" {
+		t.Fatalf(`GET /healthz(Limited) = %d, Retry-After %q, want 429, "2"`, recorder.Code, recorder.Header().Get("Retry-After"))
+	}
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf(`GET /readyz(Unlimited) = %d, want 200`, recorder.Code)
+	}
+}
+
+func TestRateLimitChargesIPWithToken(t *testing.T) {
+	err := SetRateLimits(ratelimit.Config{
+		Routes: map[string]ratelimit.Budget{"GET /healthz": {Rate: 0.5, Burst: 2}},
+	})
+	if err != nil {
+		t.Fatalf(`SetRateLimits(Healthz) = %v, want nil`, err)
+	}
+	t.Cleanup(func() { SetRateLimits(ratelimit.Config{}) })
+
+	router := newRouter()
+
+	for i, token := range []string{testUserToken(t), "", testUserToken(t)} {
+		request := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+		if token != "" {
+			request.Header.Set(TokenHeader, token)
+		}
+
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+
+		want := http.StatusOK
+		if i == 2 {
+			want = http.StatusTooManyRequests
+		}
+
+		if recorder.Code != want {
+			t.Fatalf(`GET /healthz(Request %d, Token %t) = %d, want %d`, i+1, token != "", recorder.Code, want)
+		}
+	}
+}
+
+func TestSetTrustedProxiesInvalid(t *testing.T) {
+	err := SetTrustedProxies([]string{"not-an-ip"})
+	if !errors.As(err, &sErr.IV_ERR) {
+		t.Fatalf(`SetTrustedProxies(Invalid) = %v, want InvalidValueError`, err)
+	}
+}
+
+func serveTestRequest(method string, path string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(method, path, nil)
+	newRouter().ServeHTTP(recorder, request)
+
+	return recorder
+}
+
+func findRefs(docJSON []byte) []string {
+	refs := make([]string, 0)
+	for _, part := range strings.Split(string(docJSON), `"$ref":"`)[1:] {
+		ref, _, _ := strings.Cut(part, `"`)
+		refs = append(refs, ref)
+	}
+
+	return refs
+}
+
+func testUserToken(t *testing.T) string {
+	uid, _ := user.CreateUser("Token User")
+	t.Cleanup(func() { user.DeleteUser(uid) })
+
+	token, err := user.IssueUserToken(uid)
+	if err != nil {
+		t.Fatalf(`IssueUserToken(%s) = %v, want nil`, uid, err)
+	}
+
+	return token
+}
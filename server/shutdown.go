@@ -0,0 +1,109 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	registry "Engee-Server/gameRegistry"
+	"Engee-Server/room"
+	"Engee-Server/tracing"
+	"Engee-Server/utils"
+)
+
+const DefaultShutdownTimeout = 30 * time.Second
+
+var shutdownTimeout = DefaultShutdownTimeout
+var stateFile string
+
+var closing = make(chan struct{})
+
+func SetShutdownOptions(timeout time.Duration, file string) {
+	if timeout > 0 {
+		shutdownTimeout = timeout
+	}
+
+	stateFile = file
+}
+
+func serveUntilSignalled(srv *http.Server) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	failed := make(chan error, 1)
+	go func() {
+		err := srv.ListenAndServe()
+		if !errors.Is(err, http.ErrServerClosed) {
+			failed <- err
+		}
+	}()
+
+	select {
+	case err := <-failed:
+		slog.Error("Serving", "error", err)
+		os.Exit(1)
+	case sig := <-signals:
+		slog.Info("Shutting down", "signal", sig.String(), "timeout", shutdownTimeout)
+	}
+
+	err := shutdown(srv, shutdownTimeout)
+	if err != nil {
+		slog.Error("Shutting down", "error", err)
+		return
+	}
+
+	slog.Info("Shut down cleanly")
+}
+
+// shutdown gives closing connections and draining rooms half of timeout each,
+// so requests hanging until the deadline cannot leave rooms undrained.
+func shutdown(srv *http.Server, timeout time.Duration) error {
+	close(closing)
+	room.StopAcceptingRooms()
+
+	room.NotifyShutdown()
+	registry.CloseRegistryEvents()
+
+	var errs []error
+
+	connectionsCtx, cancelConnections := context.WithTimeout(context.Background(), timeout/2)
+	defer cancelConnections()
+
+	err := srv.Shutdown(connectionsCtx)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("could not close connections: %w", err))
+	}
+
+	drainCtx, cancelDrain := context.WithTimeout(context.Background(), timeout-timeout/2)
+	defer cancelDrain()
+
+	err = room.DrainRooms(drainCtx, stateFile)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("could not drain rooms: %w", err))
+	}
+
+	utils.StopHeartbeatMonitors()
+
+	err = tracing.Close()
+	if err != nil {
+		errs = append(errs, fmt.Errorf("could not close trace exporter: %w", err))
+	}
+
+	return errors.Join(errs...)
+}
+
+func isShuttingDown() bool {
+	select {
+	case <-closing:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,204 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	gameclient "Engee-Server/gameClient"
+	registry "Engee-Server/gameRegistry"
+	"Engee-Server/room"
+)
+
+// shutdown stops accepting rooms, monitors and event streams for good, so the
+// tests that call it run in a child test process.
+const shutdownTestEnv = "ENGEE_SHUTDOWN_TEST"
+
+const shutdownGameMode = "Shutdown"
+
+// slowDrainBackend holds EndGame until its context expires and records what
+// readiness reported and how long the drain had left when it started.
+type slowDrainBackend struct {
+	*gameclient.FakeBackend
+	lock      sync.Mutex
+	readiness int
+	budget    time.Duration
+}
+
+func TestShutdownSlowDrain(t *testing.T) {
+	if os.Getenv(shutdownTestEnv) == "" {
+		runShutdownTest(t, "TestShutdownSlowDrain")
+		return
+	}
+
+	const timeout = 400 * time.Millisecond
+
+	backend := setupSlowDrainTest(t)
+	srv, requestDone := serveSlowRequest(t, timeout/4)
+
+	start := time.Now()
+	err := shutdown(srv, timeout)
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, context.DeadlineExceeded) || strings.Contains(err.Error(), "could not close connections") {
+		t.Fatalf(`shutdown(SlowDrain) = %v, want only the drain to time out`, err)
+	}
+
+	code := <-requestDone
+	if code != http.StatusOK {
+		t.Fatalf(`shutdown(SlowDrain) in-flight request = %d, want 200`, code)
+	}
+
+	readiness, budget := backend.observed()
+	if budget < timeout/2*3/4 || budget > timeout/2 {
+		t.Fatalf(`shutdown(SlowDrain) drain budget = %v, want about %v after the in-flight request`, budget, timeout/2)
+	}
+
+	if readiness != http.StatusServiceUnavailable {
+		t.Fatalf(`GET /readyz(Draining) = %d, want 503`, readiness)
+	}
+
+	if elapsed > timeout+timeout/4 {
+		t.Fatalf(`shutdown(SlowDrain) took %v, want within %v`, elapsed, timeout)
+	}
+}
+
+func TestShutdownHangingRequest(t *testing.T) {
+	if os.Getenv(shutdownTestEnv) == "" {
+		runShutdownTest(t, "TestShutdownHangingRequest")
+		return
+	}
+
+	const timeout = 400 * time.Millisecond
+
+	backend := setupSlowDrainTest(t)
+	srv, _ := serveSlowRequest(t, timeout)
+
+	err := shutdown(srv, timeout)
+	if err == nil || !strings.Contains(err.Error(), "could not close connections") {
+		t.Fatalf(`shutdown(HangingRequest) = %v, want connections to time out`, err)
+	}
+
+	_, budget := backend.observed()
+	if budget < timeout/2*3/4 || budget > timeout/2 {
+		t.Fatalf(`shutdown(HangingRequest) drain budget = %v, want about %v left by the hanging request`, budget, timeout/2)
+	}
+}
+
+func TestShutdownReadiness(t *testing.T) {
+	if os.Getenv(shutdownTestEnv) == "" {
+		runShutdownTest(t, "TestShutdownReadiness")
+		return
+	}
+
+	srv, requestDone := serveSlowRequest(t, 100*time.Millisecond)
+
+	shutdownDone := make(chan error, 1)
+	go func() {
+		shutdownDone <- shutdown(srv, time.Second)
+	}()
+
+	for deadline := time.Now().Add(time.Second); !isShuttingDown() && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+
+	recorder := serveTestRequest(http.MethodGet, "/readyz")
+
+	var response HealthResponse
+	json.Unmarshal(recorder.Body.Bytes(), &response)
+	if recorder.Code != http.StatusServiceUnavailable || response.Checks["shutdown"] == "ok" {
+		t.Fatalf(`GET /readyz(ShuttingDown) = %d, %v, want 503 with failing shutdown check`, recorder.Code, response)
+	}
+
+	<-requestDone
+	err := <-shutdownDone
+	if err != nil {
+		t.Fatalf(`shutdown(Readiness) = %v, want nil`, err)
+	}
+}
+
+// runShutdownTest runs the named test in a child process with
+// shutdownTestEnv set and fails if it fails.
+func runShutdownTest(t *testing.T, name string) {
+	command := exec.Command(os.Args[0], "-test.run=^"+name+"$", "-test.count=1")
+	command.Env = append(os.Environ(), shutdownTestEnv+"=1")
+
+	output, err := command.CombinedOutput()
+	if err != nil {
+		t.Fatalf(`%s = %v, want pass:\n%s`, name, err, output)
+	}
+}
+
+func setupSlowDrainTest(t *testing.T) *slowDrainBackend {
+	backend := &slowDrainBackend{FakeBackend: gameclient.NewFakeBackend()}
+	room.SetGameBackend(backend)
+
+	registry.RegisterGameMode(shutdownGameMode, "http://localhost:8094")
+
+	roomJSON, _ := json.Marshal(room.Room{Name: "Shutdown Room", GameMode: shutdownGameMode})
+	_, err := room.CreateRoom(context.Background(), roomJSON)
+	if err != nil {
+		t.Fatalf(`CreateRoom(Shutdown) = %v, want nil`, err)
+	}
+
+	return backend
+}
+
+// serveSlowRequest starts srv with one request in flight that takes delay to
+// answer, and reports that request's status code.
+func serveSlowRequest(t *testing.T, delay time.Duration) (*http.Server, chan int) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf(`Listen(Shutdown) = %v, want nil`, err)
+	}
+
+	started := make(chan struct{})
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(delay)
+	})}
+	go srv.Serve(listener)
+
+	requestDone := make(chan int, 1)
+	go func() {
+		response, err := http.Get("http://" + listener.Addr().String())
+		if err != nil {
+			requestDone <- 0
+			return
+		}
+		response.Body.Close()
+		requestDone <- response.StatusCode
+	}()
+
+	<-started
+
+	return srv, requestDone
+}
+
+func (b *slowDrainBackend) EndGame(ctx context.Context, rid string) error {
+	deadline, _ := ctx.Deadline()
+	readiness := serveTestRequest(http.MethodGet, "/readyz").Code
+
+	b.lock.Lock()
+	b.readiness = readiness
+	b.budget = time.Until(deadline)
+	b.lock.Unlock()
+
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (b *slowDrainBackend) observed() (int, time.Duration) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.readiness, b.budget
+}
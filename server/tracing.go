@@ -0,0 +1,40 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"Engee-Server/logging"
+	"Engee-Server/tracing"
+)
+
+// TracingMiddleWare wraps each request in a server span, continuing the
+// caller's trace when it sends a W3C traceparent header.
+func TracingMiddleWare() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		ctx, span := tracing.StartServer(c.Request.Context(), c.Request.Method+" "+route, c.Request.Header.Get(tracing.TraceParentHeader))
+		defer span.End()
+
+		span.SetAttribute("http.request.method", c.Request.Method)
+		span.SetAttribute("http.route", route)
+		span.SetAttribute("url.path", c.Request.URL.Path)
+		span.SetAttribute("request_id", logging.RequestID(ctx))
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttribute("http.response.status_code", strconv.Itoa(status))
+		if status >= http.StatusInternalServerError {
+			span.RecordError(fmt.Errorf("request failed with status %d", status))
+		}
+	}
+}
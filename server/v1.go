@@ -0,0 +1,575 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	registry "Engee-Server/gameRegistry"
+	"Engee-Server/lobby"
+	"Engee-Server/room"
+	sErr "Engee-Server/stockErrors"
+	"Engee-Server/tracing"
+	"Engee-Server/user"
+)
+
+func registerV1Routes(router *gin.RouterGroup) {
+	router.POST("/users", postUserV1)
+	router.GET("/users/:uid", getUserV1)
+	router.PATCH("/users/:uid", patchUserV1)
+	router.POST("/users/:uid/heartbeat", userHeartbeatV1)
+	router.DELETE("/users/:uid", deleteUserV1)
+
+	router.GET("/rooms", getRoomsV1)
+	router.POST("/rooms", postRoomV1)
+	router.GET("/rooms/:rid", getRoomV1)
+	router.PATCH("/rooms/:rid", patchRoomV1)
+	router.DELETE("/rooms/:rid", deleteRoomV1)
+	router.PUT("/rooms/:rid/mode", putRoomGameModeV1)
+	router.PUT("/rooms/:rid/rules", putRoomRulesV1)
+	router.POST("/rooms/:rid/game", postRoomGameV1)
+	router.GET("/rooms/:rid/game", getRoomGameStateV1)
+	router.GET("/rooms/:rid/results", getRoomResultsV1)
+	router.GET("/rooms/:rid/users", getRoomUsersV1)
+	router.POST("/rooms/:rid/users", postRoomUserV1)
+	router.DELETE("/rooms/:rid/users/:uid", deleteRoomUserV1)
+	router.GET("/rooms/:rid/events", getRoomEvents)
+	router.GET("/rooms/:rid/play", proxyRoomGame)
+
+	router.GET("/gamemodes", getGameModesV1)
+	router.POST("/gamemodes", postGameModeV1)
+	router.GET("/gamemodes/events", getRegistryEvents)
+	router.GET("/gamemodes/:gameMode", getGameModeVersionsV1)
+	router.POST("/gamemodes/:gameMode/heartbeat", gameModeHeartbeatV1)
+
+	router.POST("/games/:rid/events", postGameEventV1)
+}
+
+func postUserV1(c *gin.Context) {
+	var request CreateUserRequest
+	_, ok := readJSON(c, "POST v1/users", &request)
+	if !ok {
+		return
+	}
+
+	uid, err := user.CreateUserFromIP(request.Name, c.ClientIP())
+	if err != nil {
+		sendError(c, "POST v1/users", err)
+		return
+	}
+
+	token, err := user.IssueUserToken(uid)
+	if err != nil {
+		sendError(c, "POST v1/users", err)
+		return
+	}
+
+	newUser, err := user.GetUser(uid)
+	if err != nil {
+		sendError(c, "POST v1/users", err)
+		return
+	}
+
+	c.Header(TokenHeader, token)
+
+	sendJSON(c, "POST v1/users", CreateUserResponse{
+		User:  newUserResponse(newUser),
+		Token: token,
+	}, http.StatusCreated)
+}
+
+func getUserV1(c *gin.Context) {
+	found, err := user.GetUser(c.Param("uid"))
+	if err != nil {
+		sendError(c, "GET v1/user", err)
+		return
+	}
+
+	sendJSON(c, "GET v1/user", newUserResponse(found), http.StatusOK)
+}
+
+func patchUserV1(c *gin.Context) {
+	var request UpdateUserRequest
+	_, ok := readJSON(c, "PATCH v1/user", &request)
+	if !ok {
+		return
+	}
+
+	uid := c.Param("uid")
+
+	if request.Name != nil {
+		err := user.UpdateUserName(uid, *request.Name)
+		if err != nil {
+			sendError(c, "PATCH v1/user", err)
+			return
+		}
+	}
+
+	if request.Status != nil {
+		err := user.UpdateUserStatus(uid, *request.Status)
+		if err != nil {
+			sendError(c, "PATCH v1/user", err)
+			return
+		}
+	}
+
+	getUserV1(c)
+}
+
+func userHeartbeatV1(c *gin.Context) {
+	err := user.Heartbeat(c.Param("uid"))
+	if err != nil {
+		sendError(c, "POST v1/user/heartbeat", err)
+		return
+	}
+
+	getUserV1(c)
+}
+
+func deleteUserV1(c *gin.Context) {
+	uid := c.Param("uid")
+
+	err := lobby.RemoveUserFromAllRooms(c.Request.Context(), uid)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Removing deleted user from room(s)", "error", err)
+	}
+
+	err = user.DeleteUser(uid)
+	if err != nil {
+		sendError(c, "DELETE v1/user", err)
+		return
+	}
+
+	c.Writer.WriteHeader(http.StatusNoContent)
+}
+
+func getRoomsV1(c *gin.Context) {
+	filter, err := roomFilterQuery(c)
+	if err != nil {
+		sendError(c, "GET v1/rooms", err)
+		return
+	}
+
+	page, err := pageQuery(c)
+	if err != nil {
+		sendError(c, "GET v1/rooms", err)
+		return
+	}
+
+	rooms, next, err := room.ListRooms(filter, page)
+	if err != nil {
+		sendError(c, "GET v1/rooms", err)
+		return
+	}
+
+	sendJSON(c, "GET v1/rooms", RoomListResponse{
+		Rooms:      newRoomResponses(rooms),
+		NextCursor: next,
+	}, http.StatusOK)
+}
+
+func postRoomV1(c *gin.Context) {
+	var request CreateRoomRequest
+	_, ok := readJSON(c, "POST v1/rooms", &request)
+	if !ok {
+		return
+	}
+
+	roomInfo, err := json.Marshal(room.Room{
+		Name:       request.Name,
+		GameMode:   request.GameMode,
+		Version:    request.Version,
+		Private:    request.Private,
+		MaxPlayers: request.MaxPlayers,
+		Owner:      authenticatedUID(c.Request),
+	})
+	if err != nil {
+		sendError(c, "POST v1/rooms", err)
+		return
+	}
+
+	rid, err := room.CreateRoom(c.Request.Context(), roomInfo)
+	if err != nil {
+		sendError(c, "POST v1/rooms", err)
+		return
+	}
+
+	sendRoom(c, "POST v1/rooms", rid, http.StatusCreated)
+}
+
+func getRoomV1(c *gin.Context) {
+	sendRoom(c, "GET v1/room", c.Param("rid"), http.StatusOK)
+}
+
+func patchRoomV1(c *gin.Context) {
+	var request UpdateRoomRequest
+	_, ok := readJSON(c, "PATCH v1/room", &request)
+	if !ok {
+		return
+	}
+
+	rid := c.Param("rid")
+
+	if request.Name != nil {
+		err := room.UpdateRoomName(rid, *request.Name)
+		if err != nil {
+			sendError(c, "PATCH v1/room", err)
+			return
+		}
+	}
+
+	if request.Status != nil {
+		err := room.UpdateRoomStatus(rid, *request.Status)
+		if err != nil {
+			sendError(c, "PATCH v1/room", err)
+			return
+		}
+	}
+
+	sendRoom(c, "PATCH v1/room", rid, http.StatusOK)
+}
+
+func deleteRoomV1(c *gin.Context) {
+	err := room.DeleteRoom(c.Request.Context(), c.Param("rid"))
+	if err != nil {
+		sendError(c, "DELETE v1/room", err)
+		return
+	}
+
+	c.Writer.WriteHeader(http.StatusNoContent)
+}
+
+func putRoomGameModeV1(c *gin.Context) {
+	var request RoomGameModeRequest
+	_, ok := readJSON(c, "PUT v1/room/mode", &request)
+	if !ok {
+		return
+	}
+
+	rid := c.Param("rid")
+
+	err := room.UpdateRoomGameMode(c.Request.Context(), rid, request.GameMode, request.Version)
+	if err != nil {
+		sendError(c, "PUT v1/room/mode", err)
+		return
+	}
+
+	sendRoom(c, "PUT v1/room/mode", rid, http.StatusOK)
+}
+
+func putRoomRulesV1(c *gin.Context) {
+	var request RulesRequest
+	_, ok := readJSON(c, "PUT v1/room/rules", &request)
+	if !ok {
+		return
+	}
+
+	rid := c.Param("rid")
+
+	rulesInfo, err := json.Marshal(request)
+	if err != nil {
+		sendError(c, "PUT v1/room/rules", err)
+		return
+	}
+
+	err = room.SetRoomRules(c.Request.Context(), rid, rulesInfo)
+	if err != nil {
+		sendError(c, "PUT v1/room/rules", err)
+		return
+	}
+
+	game, err := room.GetRoomGameInstance(rid)
+	if err != nil {
+		sendError(c, "PUT v1/room/rules", err)
+		return
+	}
+
+	sendJSON(c, "PUT v1/room/rules", newRulesResponse(rid, game.Rules), http.StatusOK)
+}
+
+func postRoomGameV1(c *gin.Context) {
+	rid := c.Param("rid")
+
+	err := room.InitializeRoomGame(c.Request.Context(), rid)
+	if err != nil {
+		sendError(c, "POST v1/room/game", err)
+		return
+	}
+
+	sendRoom(c, "POST v1/room/game", rid, http.StatusOK)
+}
+
+func getRoomGameStateV1(c *gin.Context) {
+	state, err := room.GetRoomGameState(c.Request.Context(), c.Param("rid"))
+	if err != nil {
+		sendError(c, "GET v1/room/game", err)
+		return
+	}
+
+	sendJSON(c, "GET v1/room/game", state, http.StatusOK)
+}
+
+func getRoomResultsV1(c *gin.Context) {
+	results, err := room.GetRoomResults(c.Param("rid"))
+	if err != nil {
+		sendError(c, "GET v1/room/results", err)
+		return
+	}
+
+	sendJSON(c, "GET v1/room/results", results, http.StatusOK)
+}
+
+func getRoomUsersV1(c *gin.Context) {
+	sendRoomUsers(c, "GET v1/room/users", http.StatusOK)
+}
+
+func postRoomUserV1(c *gin.Context) {
+	var request MembershipRequest
+	_, ok := readJSON(c, "POST v1/room/users", &request)
+	if !ok {
+		return
+	}
+
+	err := lobby.JoinUserToRoom(request.UID, c.Param("rid"))
+	if err != nil {
+		sendError(c, "POST v1/room/users", err)
+		return
+	}
+
+	sendRoomUsers(c, "POST v1/room/users", http.StatusOK)
+}
+
+func deleteRoomUserV1(c *gin.Context) {
+	err := lobby.RemoveUserFromRoom(c.Request.Context(), c.Param("uid"), c.Param("rid"))
+	if err != nil {
+		sendError(c, "DELETE v1/room/user", err)
+		return
+	}
+
+	c.Writer.WriteHeader(http.StatusNoContent)
+}
+
+func getGameModesV1(c *gin.Context) {
+	gameModes := registry.GetGameModes()
+	if gameModes == nil {
+		gameModes = make([]string, 0)
+	}
+
+	sendJSON(c, "GET v1/gamemodes", gameModes, http.StatusOK)
+}
+
+func postGameModeV1(c *gin.Context) {
+	var request RegisterGameModeRequest
+	reqBody, ok := readJSON(c, "POST v1/gamemodes", &request)
+	if !ok {
+		return
+	}
+
+	err := registry.VerifyGameModeRequest(request.Name, c.Request, reqBody)
+	if err != nil {
+		sendErrorCode(c, "POST v1/gamemodes", err, http.StatusUnauthorized)
+		return
+	}
+
+	err = registry.RegisterGameModeInstance(registry.GameMode{
+		Name:      request.Name,
+		Version:   request.Version,
+		Protocol:  request.Protocol,
+		Transport: request.Transport,
+		URL:       request.URL,
+		BootID:    request.BootID,
+		Metadata:  request.Metadata,
+	})
+	if err != nil {
+		sendError(c, "POST v1/gamemodes", err)
+		return
+	}
+
+	sendGameModeInstance(c, "POST v1/gamemodes", request.Name, request.Version, request.URL, http.StatusCreated)
+}
+
+func getGameModeVersionsV1(c *gin.Context) {
+	versions, err := registry.GetGameModeVersions(c.Param("gameMode"))
+	if err != nil {
+		sendError(c, "GET v1/gamemode", err)
+		return
+	}
+
+	sendJSON(c, "GET v1/gamemode", newGameModeResponses(versions), http.StatusOK)
+}
+
+func gameModeHeartbeatV1(c *gin.Context) {
+	var request GameModeHeartbeatRequest
+	reqBody, ok := readJSON(c, "POST v1/gamemode/heartbeat", &request)
+	if !ok {
+		return
+	}
+
+	name := c.Param("gameMode")
+
+	err := registry.VerifyGameModeRequest(name, c.Request, reqBody)
+	if err != nil {
+		sendErrorCode(c, "POST v1/gamemode/heartbeat", err, http.StatusUnauthorized)
+		return
+	}
+
+	err = registry.Heartbeat(name, request.Version, request.URL, request.BootID)
+	if err != nil {
+		sendError(c, "POST v1/gamemode/heartbeat", err)
+		return
+	}
+
+	sendGameModeInstance(c, "POST v1/gamemode/heartbeat", name, request.Version, request.URL, http.StatusOK)
+}
+
+func postGameEventV1(c *gin.Context) {
+	reqBody, w := processMessage(c)
+	if w == nil {
+		return
+	}
+
+	rid := c.Param("rid")
+
+	gameRoom, err := room.GetRoom(rid)
+	if err != nil {
+		sendError(c, "POST v1/game/events", err)
+		return
+	}
+
+	err = registry.VerifyGameModeRequest(gameRoom.GameMode, c.Request, reqBody)
+	if err != nil {
+		sendErrorCode(c, "POST v1/game/events", err, http.StatusUnauthorized)
+		return
+	}
+
+	err = lobby.HandleGameEvent(c.Request.Context(), rid, reqBody)
+	if err != nil {
+		sendError(c, "POST v1/game/events", err)
+		return
+	}
+
+	results, err := room.GetRoomResults(rid)
+	if err != nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	sendJSON(c, "POST v1/game/events", results, http.StatusOK)
+}
+
+func sendRoom(c *gin.Context, request string, rid string, code int) {
+	found, err := room.GetRoom(rid)
+	if err != nil {
+		sendError(c, request, err)
+		return
+	}
+
+	sendJSON(c, request, newRoomResponse(found), code)
+}
+
+func sendRoomUsers(c *gin.Context, request string, code int) {
+	users, err := lobby.GetUsersInRoom(c.Request.Context(), c.Param("rid"))
+	if err != nil {
+		sendError(c, request, err)
+		return
+	}
+
+	sendJSON(c, request, newUserResponses(users), code)
+}
+
+func sendGameModeInstance(c *gin.Context, request string, name string, version string, url string, code int) {
+	mode, err := registry.GetGameModeInstance(name, version, url)
+	if err != nil {
+		sendError(c, request, err)
+		return
+	}
+
+	sendJSON(c, request, newGameModeResponse(mode), code)
+}
+
+func readJSON(c *gin.Context, request string, target any) ([]byte, bool) {
+	reqBody, w := processMessage(c)
+	if w == nil {
+		return nil, false
+	}
+
+	err := json.Unmarshal(reqBody, target)
+	if err != nil {
+		sendErrorCode(c, request, fmt.Errorf("could not parse request body: %w", err), http.StatusBadRequest)
+		return nil, false
+	}
+
+	return reqBody, true
+}
+
+func sendJSON(c *gin.Context, request string, response any, code int) {
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		sendErrorCode(c, request, fmt.Errorf("could not package response: %w", err), http.StatusInternalServerError)
+		return
+	}
+
+	c.Header("Content-Type", "application/json")
+
+	err = sendReply(c.Writer, request, responseJSON, code)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Sending reply", "error", err)
+	}
+}
+
+func sendError(c *gin.Context, request string, err error) {
+	sendErrorCode(c, request, err, errorStatus(err))
+}
+
+func sendErrorCode(c *gin.Context, request string, err error, code int) {
+	slog.ErrorContext(c.Request.Context(), "Handling request", "request", request, "status", code, "error", err)
+	if code >= http.StatusInternalServerError {
+		tracing.SpanFromContext(c.Request.Context()).RecordError(err)
+	}
+
+	response, _ := json.Marshal(ErrorResponse{
+		Error: err.Error(),
+	})
+
+	c.Header("Content-Type", "application/json")
+
+	err = sendReply(c.Writer, request, response, code)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Sending reply", "error", err)
+	}
+}
+
+func errorStatus(err error) int {
+	var emptyValue *sErr.EmptyValueError
+	var invalidValue *sErr.InvalidValueError[string]
+	var invalidNumber *sErr.InvalidValueError[int]
+	var notFound *sErr.MatchNotFoundError[string]
+	var found *sErr.MatchFoundError[string]
+	var unauthorized *sErr.AuthorizationError
+	var incompatible *sErr.IncompatibleVersionError
+	var unavailable *sErr.UnavailableError
+	var limitExceeded *sErr.LimitExceededError
+	var capacityReached *sErr.CapacityReachedError
+	var invalidState *sErr.InvalidStateError
+
+	switch {
+	case errors.As(err, &emptyValue), errors.As(err, &invalidValue), errors.As(err, &invalidNumber):
+		return http.StatusBadRequest
+	case errors.As(err, &notFound):
+		return http.StatusNotFound
+	case errors.As(err, &found), errors.As(err, &incompatible), errors.As(err, &capacityReached), errors.As(err, &invalidState):
+		return http.StatusConflict
+	case errors.As(err, &unauthorized):
+		return http.StatusUnauthorized
+	case errors.As(err, &unavailable):
+		return http.StatusServiceUnavailable
+	case errors.As(err, &limitExceeded):
+		return http.StatusTooManyRequests
+	}
+
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,564 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	registry "Engee-Server/gameRegistry"
+	"Engee-Server/lobby"
+	"Engee-Server/room"
+	sErr "Engee-Server/stockErrors"
+	"Engee-Server/user"
+	"Engee-Server/utils"
+)
+
+const signedGameMode = "Signed"
+const signedGameModeURL = "http://127.0.0.1:9"
+
+func TestV1Users(t *testing.T) {
+	recorder := serveJSONRequest(t, http.MethodPost, "/v1/users", CreateUserRequest{Name: "V1 User"}, nil)
+
+	created := decodeResponse[CreateUserResponse](t, recorder)
+	uid := created.User.UID
+	t.Cleanup(func() { user.DeleteUser(uid) })
+
+	if recorder.Code != http.StatusCreated || created.User.Name != "V1 User" || created.Token == "" || recorder.Header().Get(TokenHeader) != created.Token {
+		t.Fatalf(`POST /v1/users = %d, %+v, want 201 with the user and its token`, recorder.Code, created)
+	}
+
+	recorder = serveJSONRequest(t, http.MethodGet, "/v1/users/"+uid, nil, nil)
+	if found := decodeResponse[UserResponse](t, recorder); recorder.Code != http.StatusOK || found.UID != uid {
+		t.Fatalf(`GET /v1/users/:uid = %d, %+v, want 200 with %s`, recorder.Code, found, uid)
+	}
+
+	name := "Renamed User"
+	recorder = serveJSONRequest(t, http.MethodPatch, "/v1/users/"+uid, UpdateUserRequest{Name: &name}, nil)
+	if updated := decodeResponse[UserResponse](t, recorder); recorder.Code != http.StatusOK || updated.Name != name {
+		t.Fatalf(`PATCH /v1/users/:uid = %d, %+v, want 200 named %q`, recorder.Code, updated, name)
+	}
+
+	recorder = serveJSONRequest(t, http.MethodPost, "/v1/users/"+uid+"/heartbeat", nil, nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf(`POST /v1/users/:uid/heartbeat = %d, want 200`, recorder.Code)
+	}
+
+	recorder = serveJSONRequest(t, http.MethodDelete, "/v1/users/"+uid, nil, nil)
+	if recorder.Code != http.StatusNoContent {
+		t.Fatalf(`DELETE /v1/users/:uid = %d, want 204`, recorder.Code)
+	}
+
+	recorder = serveJSONRequest(t, http.MethodGet, "/v1/users/"+uid, nil, nil)
+	if recorder.Code != http.StatusNotFound {
+		t.Fatalf(`GET /v1/users/:uid(Deleted) = %d, want 404`, recorder.Code)
+	}
+}
+
+func TestV1UserErrors(t *testing.T) {
+	uid, _ := user.CreateUser("V1 User")
+	t.Cleanup(func() { user.DeleteUser(uid) })
+
+	empty := ""
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   any
+		want   int
+	}{
+		{"NoName", http.MethodPost, "/v1/users", CreateUserRequest{}, http.StatusBadRequest},
+		{"BadJSON", http.MethodPost, "/v1/users", "not an object", http.StatusBadRequest},
+		{"EmptyName", http.MethodPatch, "/v1/users/" + uid, UpdateUserRequest{Name: &empty}, http.StatusBadRequest},
+		{"EmptyStatus", http.MethodPatch, "/v1/users/" + uid, UpdateUserRequest{Status: &empty}, http.StatusBadRequest},
+		{"GetMissing", http.MethodGet, "/v1/users/missing", nil, http.StatusNotFound},
+		{"PatchMissing", http.MethodPatch, "/v1/users/missing", UpdateUserRequest{}, http.StatusNotFound},
+		{"HeartbeatMissing", http.MethodPost, "/v1/users/missing/heartbeat", nil, http.StatusNotFound},
+		{"DeleteMissing", http.MethodDelete, "/v1/users/missing", nil, http.StatusNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recorder := serveJSONRequest(t, test.method, test.path, test.body, nil)
+			requireErrorResponse(t, recorder, test.method+" "+test.path, test.want)
+		})
+	}
+}
+
+func TestV1UserLimit(t *testing.T) {
+	user.SetMaxUsersPerIP(1)
+	t.Cleanup(func() { user.SetMaxUsersPerIP(0) })
+
+	fromIP := func(request *http.Request) { request.RemoteAddr = "198.51.100.7:1234" }
+
+	recorder := serveJSONRequest(t, http.MethodPost, "/v1/users", CreateUserRequest{Name: "Limited User"}, fromIP)
+	created := decodeResponse[CreateUserResponse](t, recorder)
+	t.Cleanup(func() { user.DeleteUser(created.User.UID) })
+
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf(`POST /v1/users(First) = %d, want 201`, recorder.Code)
+	}
+
+	recorder = serveJSONRequest(t, http.MethodPost, "/v1/users", CreateUserRequest{Name: "Limited User"}, fromIP)
+	requireErrorResponse(t, recorder, "POST /v1/users(OverLimit)", http.StatusTooManyRequests)
+}
+
+func TestV1Rooms(t *testing.T) {
+	setupGameServerTest(t)
+
+	recorder := serveJSONRequest(t, http.MethodPost, "/v1/rooms", CreateRoomRequest{Name: "V1 Room", GameMode: proxyGameMode, MaxPlayers: 4}, nil)
+
+	created := decodeResponse[RoomResponse](t, recorder)
+	rid := created.RID
+	t.Cleanup(func() { room.DeleteRoom(context.Background(), rid) })
+
+	if recorder.Code != http.StatusCreated || created.Name != "V1 Room" || created.GameMode != proxyGameMode || created.MaxPlayers != 4 {
+		t.Fatalf(`POST /v1/rooms = %d, %+v, want 201 with the room`, recorder.Code, created)
+	}
+
+	if strings.Contains(recorder.Body.String(), `"addr"`) {
+		t.Fatalf(`POST /v1/rooms = %s, want no game server address`, recorder.Body.String())
+	}
+
+	recorder = serveJSONRequest(t, http.MethodGet, "/v1/rooms?gamemode="+proxyGameMode, nil, nil)
+	listed := decodeResponse[RoomListResponse](t, recorder)
+	if recorder.Code != http.StatusOK || !containsRoom(listed.Rooms, rid) {
+		t.Fatalf(`GET /v1/rooms = %d, %+v, want 200 listing %s`, recorder.Code, listed, rid)
+	}
+
+	recorder = serveJSONRequest(t, http.MethodGet, "/v1/rooms/"+rid, nil, nil)
+	if found := decodeResponse[RoomResponse](t, recorder); recorder.Code != http.StatusOK || found.RID != rid {
+		t.Fatalf(`GET /v1/rooms/:rid = %d, %+v, want 200 with %s`, recorder.Code, found, rid)
+	}
+
+	name := "Renamed Room"
+	recorder = serveJSONRequest(t, http.MethodPatch, "/v1/rooms/"+rid, UpdateRoomRequest{Name: &name}, nil)
+	if updated := decodeResponse[RoomResponse](t, recorder); recorder.Code != http.StatusOK || updated.Name != name {
+		t.Fatalf(`PATCH /v1/rooms/:rid = %d, %+v, want 200 named %q`, recorder.Code, updated, name)
+	}
+
+	recorder = serveJSONRequest(t, http.MethodDelete, "/v1/rooms/"+rid, nil, nil)
+	if recorder.Code != http.StatusNoContent {
+		t.Fatalf(`DELETE /v1/rooms/:rid = %d, want 204`, recorder.Code)
+	}
+
+	recorder = serveJSONRequest(t, http.MethodGet, "/v1/rooms/"+rid, nil, nil)
+	requireErrorResponse(t, recorder, "GET /v1/rooms/:rid(Deleted)", http.StatusNotFound)
+}
+
+func TestV1RoomErrors(t *testing.T) {
+	_, rid := setupGameServerTest(t)
+
+	empty := ""
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   any
+		want   int
+	}{
+		{"BadOrder", http.MethodGet, "/v1/rooms?order=sideways", nil, http.StatusBadRequest},
+		{"BadLimit", http.MethodGet, "/v1/rooms?limit=many", nil, http.StatusBadRequest},
+		{"BadJSON", http.MethodPost, "/v1/rooms", "not an object", http.StatusBadRequest},
+		{"MissingGameMode", http.MethodPost, "/v1/rooms", CreateRoomRequest{Name: "V1 Room", GameMode: "Missing"}, http.StatusNotFound},
+		{"EmptyName", http.MethodPatch, "/v1/rooms/" + rid, UpdateRoomRequest{Name: &empty}, http.StatusBadRequest},
+		{"GetMissing", http.MethodGet, "/v1/rooms/missing", nil, http.StatusNotFound},
+		{"PatchMissing", http.MethodPatch, "/v1/rooms/missing", UpdateRoomRequest{}, http.StatusNotFound},
+		{"DeleteMissing", http.MethodDelete, "/v1/rooms/missing", nil, http.StatusNotFound},
+		{"ModeMissing", http.MethodPut, "/v1/rooms/missing/mode", RoomGameModeRequest{GameMode: proxyGameMode}, http.StatusNotFound},
+		{"RulesMissing", http.MethodPut, "/v1/rooms/missing/rules", RulesRequest{Rules: "Rules"}, http.StatusNotFound},
+		{"GameMissing", http.MethodPost, "/v1/rooms/missing/game", nil, http.StatusNotFound},
+		{"StateMissing", http.MethodGet, "/v1/rooms/missing/game", nil, http.StatusNotFound},
+		{"ResultsMissing", http.MethodGet, "/v1/rooms/missing/results", nil, http.StatusNotFound},
+		{"UsersMissing", http.MethodGet, "/v1/rooms/missing/users", nil, http.StatusNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recorder := serveJSONRequest(t, test.method, test.path, test.body, nil)
+			requireErrorResponse(t, recorder, test.method+" "+test.path, test.want)
+		})
+	}
+}
+
+func TestV1RoomOwnerLimit(t *testing.T) {
+	setupGameServerTest(t)
+
+	room.SetMaxRoomsPerOwner(1)
+	t.Cleanup(func() { room.SetMaxRoomsPerOwner(0) })
+
+	token := testUserToken(t)
+	asOwner := func(request *http.Request) { request.Header.Set(TokenHeader, token) }
+
+	recorder := serveJSONRequest(t, http.MethodPost, "/v1/rooms", CreateRoomRequest{Name: "Owned Room", GameMode: proxyGameMode}, asOwner)
+	created := decodeResponse[RoomResponse](t, recorder)
+	t.Cleanup(func() { room.DeleteRoom(context.Background(), created.RID) })
+
+	if recorder.Code != http.StatusCreated || created.Owner == "" {
+		t.Fatalf(`POST /v1/rooms(First) = %d, %+v, want 201 with an owner`, recorder.Code, created)
+	}
+
+	recorder = serveJSONRequest(t, http.MethodPost, "/v1/rooms", CreateRoomRequest{Name: "Owned Room", GameMode: proxyGameMode}, asOwner)
+	requireErrorResponse(t, recorder, "POST /v1/rooms(OverLimit)", http.StatusTooManyRequests)
+}
+
+func TestV1RoomGame(t *testing.T) {
+	_, rid := setupGameServerTest(t)
+
+	recorder := serveJSONRequest(t, http.MethodPut, "/v1/rooms/"+rid+"/rules", RulesRequest{Rules: "First to ten"}, nil)
+	if rules := decodeResponse[RulesResponse](t, recorder); recorder.Code != http.StatusOK || rules.RID != rid || rules.Rules != "First to ten" {
+		t.Fatalf(`PUT /v1/rooms/:rid/rules = %d, %+v, want 200 with the rules`, recorder.Code, rules)
+	}
+
+	recorder = serveJSONRequest(t, http.MethodPost, "/v1/rooms/"+rid+"/game", nil, nil)
+	requireErrorResponse(t, recorder, "POST /v1/rooms/:rid/game(Exists)", http.StatusConflict)
+
+	err := room.EndRoomGame(context.Background(), rid)
+	if err != nil {
+		t.Fatalf(`EndRoomGame(%s) = %v, want nil`, rid, err)
+	}
+
+	recorder = serveJSONRequest(t, http.MethodPost, "/v1/rooms/"+rid+"/game", nil, nil)
+	if created := decodeResponse[RoomResponse](t, recorder); recorder.Code != http.StatusOK || created.Status != room.StatusCreated {
+		t.Fatalf(`POST /v1/rooms/:rid/game = %d, %+v, want 200 with status %s`, recorder.Code, created, room.StatusCreated)
+	}
+
+	recorder = serveJSONRequest(t, http.MethodGet, "/v1/rooms/"+rid+"/game", nil, nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf(`GET /v1/rooms/:rid/game = %d, %s, want 200`, recorder.Code, recorder.Body.String())
+	}
+
+	recorder = serveJSONRequest(t, http.MethodGet, "/v1/rooms/"+rid+"/results", nil, nil)
+	if results := decodeResponse[room.RoomResults](t, recorder); recorder.Code != http.StatusOK || results.Scores == nil {
+		t.Fatalf(`GET /v1/rooms/:rid/results = %d, %+v, want 200 with scores`, recorder.Code, results)
+	}
+}
+
+func TestV1RoomGameStateErrors(t *testing.T) {
+	_, rid := setupGameServerTest(t)
+	t.Cleanup(func() {
+		testBackend.SetFailure("GetGameState", nil)
+		room.InvalidateRoomGameState(rid)
+	})
+
+	tests := []struct {
+		name    string
+		failure error
+		want    int
+	}{
+		{"Unavailable", &sErr.UnavailableError{Space: "Game server", Reason: "circuit open"}, http.StatusServiceUnavailable},
+		{"Internal", errors.New("game server exploded"), http.StatusInternalServerError},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			testBackend.SetFailure("GetGameState", test.failure)
+			room.InvalidateRoomGameState(rid)
+
+			recorder := serveJSONRequest(t, http.MethodGet, "/v1/rooms/"+rid+"/game", nil, nil)
+			requireErrorResponse(t, recorder, "GET /v1/rooms/:rid/game("+test.name+")", test.want)
+		})
+	}
+}
+
+func TestV1RoomGameMode(t *testing.T) {
+	game, rid := setupGameServerTest(t)
+
+	recorder := serveJSONRequest(t, http.MethodPut, "/v1/rooms/"+rid+"/mode", RoomGameModeRequest{GameMode: proxyGameMode}, nil)
+	if switched := decodeResponse[RoomResponse](t, recorder); recorder.Code != http.StatusOK || switched.GameMode != proxyGameMode {
+		t.Fatalf(`PUT /v1/rooms/:rid/mode = %d, %+v, want 200 on %s`, recorder.Code, switched, proxyGameMode)
+	}
+
+	registry.RegisterGameMode(signedGameMode, game.server.URL)
+	t.Cleanup(func() { registry.RemoveGameMode(signedGameMode) })
+
+	running := room.StatusRunning
+	recorder = serveJSONRequest(t, http.MethodPatch, "/v1/rooms/"+rid, UpdateRoomRequest{Status: &running}, nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf(`PATCH /v1/rooms/:rid(Running) = %d, want 200`, recorder.Code)
+	}
+
+	tests := []struct {
+		name     string
+		gameMode string
+		want     int
+	}{
+		{"Running", proxyGameMode, http.StatusConflict},
+		{"RunningIncompatible", signedGameMode, http.StatusConflict},
+		{"Missing", "Missing", http.StatusNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recorder := serveJSONRequest(t, http.MethodPut, "/v1/rooms/"+rid+"/mode", RoomGameModeRequest{GameMode: test.gameMode}, nil)
+			requireErrorResponse(t, recorder, "PUT /v1/rooms/:rid/mode("+test.name+")", test.want)
+		})
+	}
+}
+
+func TestV1RoomMembership(t *testing.T) {
+	_, rid := setupGameServerTest(t)
+
+	uid, _ := user.CreateUser("V1 Player")
+	t.Cleanup(func() {
+		lobby.RemoveUserFromAllRooms(context.Background(), uid)
+		user.DeleteUser(uid)
+	})
+
+	recorder := serveJSONRequest(t, http.MethodPost, "/v1/rooms/"+rid+"/users", MembershipRequest{UID: uid}, nil)
+	if members := decodeResponse[[]UserResponse](t, recorder); recorder.Code != http.StatusOK || len(members) != 1 || members[0].UID != uid {
+		t.Fatalf(`POST /v1/rooms/:rid/users = %d, %+v, want 200 with %s`, recorder.Code, members, uid)
+	}
+
+	recorder = serveJSONRequest(t, http.MethodPost, "/v1/rooms/"+rid+"/users", MembershipRequest{UID: uid}, nil)
+	requireErrorResponse(t, recorder, "POST /v1/rooms/:rid/users(Again)", http.StatusConflict)
+
+	recorder = serveJSONRequest(t, http.MethodGet, "/v1/rooms/"+rid+"/users", nil, nil)
+	if members := decodeResponse[[]UserResponse](t, recorder); recorder.Code != http.StatusOK || len(members) != 1 {
+		t.Fatalf(`GET /v1/rooms/:rid/users = %d, %+v, want 200 with one user`, recorder.Code, members)
+	}
+
+	recorder = serveJSONRequest(t, http.MethodDelete, "/v1/rooms/"+rid+"/users/"+uid, nil, nil)
+	if recorder.Code != http.StatusNoContent {
+		t.Fatalf(`DELETE /v1/rooms/:rid/users/:uid = %d, want 204`, recorder.Code)
+	}
+
+	recorder = serveJSONRequest(t, http.MethodDelete, "/v1/rooms/"+rid+"/users/"+uid, nil, nil)
+	requireErrorResponse(t, recorder, "DELETE /v1/rooms/:rid/users/:uid(Again)", http.StatusNotFound)
+
+	recorder = serveJSONRequest(t, http.MethodPost, "/v1/rooms/"+rid+"/users", MembershipRequest{UID: "missing"}, nil)
+	requireErrorResponse(t, recorder, "POST /v1/rooms/:rid/users(MissingUser)", http.StatusNotFound)
+}
+
+func TestV1RoomFull(t *testing.T) {
+	setupGameServerTest(t)
+
+	roomJSON, _ := json.Marshal(room.Room{Name: "Full Room", GameMode: proxyGameMode, MaxPlayers: 1})
+	rid, err := room.CreateRoom(context.Background(), roomJSON)
+	if err != nil {
+		t.Fatalf(`CreateRoom(Full) = %v, want nil`, err)
+	}
+	t.Cleanup(func() { room.DeleteRoom(context.Background(), rid) })
+
+	for i, want := range []int{http.StatusOK, http.StatusConflict} {
+		uid, _ := user.CreateUser("V1 Player")
+		t.Cleanup(func() {
+			lobby.RemoveUserFromAllRooms(context.Background(), uid)
+			user.DeleteUser(uid)
+		})
+
+		recorder := serveJSONRequest(t, http.MethodPost, "/v1/rooms/"+rid+"/users", MembershipRequest{UID: uid}, nil)
+		if recorder.Code != want {
+			t.Fatalf(`POST /v1/rooms/:rid/users(Player %d) = %d, %s, want %d`, i+1, recorder.Code, recorder.Body.String(), want)
+		}
+	}
+}
+
+func TestV1GameModes(t *testing.T) {
+	registry.SetGameModeSecrets(map[string]string{signedGameMode: proxySecret})
+	t.Cleanup(func() {
+		registry.SetGameModeSecrets(nil)
+		registry.RemoveGameMode(signedGameMode)
+	})
+
+	registration := RegisterGameModeRequest{Name: signedGameMode, URL: signedGameModeURL, Version: "1.2.0"}
+
+	recorder := serveSignedRequest(t, http.MethodPost, "/v1/gamemodes", registration, proxySecret)
+	registered := decodeResponse[GameModeResponse](t, recorder)
+	if recorder.Code != http.StatusCreated || registered.Name != signedGameMode || registered.Version != "1.2.0" || strings.Contains(recorder.Body.String(), signedGameModeURL) {
+		t.Fatalf(`POST /v1/gamemodes = %d, %s, want 201 without the URL`, recorder.Code, recorder.Body.String())
+	}
+
+	recorder = serveJSONRequest(t, http.MethodGet, "/v1/gamemodes", nil, nil)
+	if modes := decodeResponse[[]string](t, recorder); recorder.Code != http.StatusOK || !strings.Contains(strings.Join(modes, ","), signedGameMode) {
+		t.Fatalf(`GET /v1/gamemodes = %d, %v, want 200 listing %s`, recorder.Code, modes, signedGameMode)
+	}
+
+	recorder = serveJSONRequest(t, http.MethodGet, "/v1/gamemodes/"+signedGameMode, nil, nil)
+	if versions := decodeResponse[[]GameModeResponse](t, recorder); recorder.Code != http.StatusOK || len(versions) != 1 || versions[0].Version != "1.2.0" {
+		t.Fatalf(`GET /v1/gamemodes/:gameMode = %d, %+v, want 200 with 1.2.0`, recorder.Code, versions)
+	}
+
+	heartbeat := GameModeHeartbeatRequest{URL: signedGameModeURL, Version: "1.2.0"}
+	recorder = serveSignedRequest(t, http.MethodPost, "/v1/gamemodes/"+signedGameMode+"/heartbeat", heartbeat, proxySecret)
+	if beat := decodeResponse[GameModeResponse](t, recorder); recorder.Code != http.StatusOK || beat.Health != registry.HealthHealthy {
+		t.Fatalf(`POST /v1/gamemodes/:gameMode/heartbeat = %d, %+v, want 200 and healthy`, recorder.Code, beat)
+	}
+}
+
+func TestV1GameModeErrors(t *testing.T) {
+	registry.SetGameModeSecrets(map[string]string{signedGameMode: proxySecret})
+	t.Cleanup(func() {
+		registry.SetGameModeSecrets(nil)
+		registry.RemoveGameMode(signedGameMode)
+	})
+
+	registration := RegisterGameModeRequest{Name: signedGameMode, URL: signedGameModeURL}
+	heartbeatPath := "/v1/gamemodes/" + signedGameMode + "/heartbeat"
+
+	recorder := serveSignedRequest(t, http.MethodPost, "/v1/gamemodes", registration, proxySecret)
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf(`POST /v1/gamemodes = %d, %s, want 201`, recorder.Code, recorder.Body.String())
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   any
+		secret string
+		want   int
+	}{
+		{"Unsigned", http.MethodPost, "/v1/gamemodes", registration, "", http.StatusUnauthorized},
+		{"WrongSecret", http.MethodPost, "/v1/gamemodes", registration, "wrong-secret", http.StatusUnauthorized},
+		{"Duplicate", http.MethodPost, "/v1/gamemodes", registration, proxySecret, http.StatusConflict},
+		{"BadTransport", http.MethodPost, "/v1/gamemodes", RegisterGameModeRequest{Name: signedGameMode, URL: signedGameModeURL, Version: "2.0.0", Transport: "pigeon"}, proxySecret, http.StatusBadRequest},
+		{"GetMissing", http.MethodGet, "/v1/gamemodes/Missing", nil, "", http.StatusNotFound},
+		{"HeartbeatUnsigned", http.MethodPost, heartbeatPath, GameModeHeartbeatRequest{URL: signedGameModeURL}, "", http.StatusUnauthorized},
+		{"HeartbeatNoURL", http.MethodPost, heartbeatPath, GameModeHeartbeatRequest{}, proxySecret, http.StatusBadRequest},
+		{"HeartbeatUnknownURL", http.MethodPost, heartbeatPath, GameModeHeartbeatRequest{URL: "http://127.0.0.1:10"}, proxySecret, http.StatusNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recorder := serveSignedRequest(t, test.method, test.path, test.body, test.secret)
+			requireErrorResponse(t, recorder, test.method+" "+test.path, test.want)
+		})
+	}
+}
+
+func TestV1GameEvents(t *testing.T) {
+	registry.SetGameModeSecrets(map[string]string{proxyGameMode: proxySecret})
+	t.Cleanup(func() { registry.SetGameModeSecrets(nil) })
+
+	_, rid := setupGameServerTest(t)
+	path := "/v1/games/" + rid + "/events"
+
+	event := lobby.GameEvent{Kind: lobby.GameEventScoresUpdated, Scores: map[string]int{"player": 3}}
+	recorder := serveSignedRequest(t, http.MethodPost, path, event, proxySecret)
+	if results := decodeResponse[room.RoomResults](t, recorder); recorder.Code != http.StatusOK || results.Scores["player"] != 3 {
+		t.Fatalf(`POST /v1/games/:rid/events = %d, %+v, want 200 with the new score`, recorder.Code, results)
+	}
+
+	tests := []struct {
+		name   string
+		path   string
+		event  lobby.GameEvent
+		secret string
+		want   int
+	}{
+		{"Unsigned", path, event, "", http.StatusUnauthorized},
+		{"NoKind", path, lobby.GameEvent{}, proxySecret, http.StatusBadRequest},
+		{"UnknownKind", path, lobby.GameEvent{Kind: "exploded"}, proxySecret, http.StatusBadRequest},
+		{"MissingRoom", "/v1/games/missing/events", event, proxySecret, http.StatusNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recorder := serveSignedRequest(t, http.MethodPost, test.path, test.event, test.secret)
+			requireErrorResponse(t, recorder, "POST "+test.path, test.want)
+		})
+	}
+}
+
+func TestErrorStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"EmptyValue", &sErr.EmptyValueError{Field: "Name"}, http.StatusBadRequest},
+		{"InvalidValue", &sErr.InvalidValueError[string]{Field: "Name", Value: "?"}, http.StatusBadRequest},
+		{"InvalidNumber", &sErr.InvalidValueError[int]{Field: "Limit", Value: -1}, http.StatusBadRequest},
+		{"MatchNotFound", &sErr.MatchNotFoundError[string]{Space: "Users", Field: "UID", Value: "missing"}, http.StatusNotFound},
+		{"MatchFound", &sErr.MatchFoundError[string]{Space: "Users", Field: "UID", Value: "found"}, http.StatusConflict},
+		{"Incompatible", &sErr.IncompatibleVersionError{Current: "Mode@1.0.0", Requested: "Mode@2.0.0"}, http.StatusConflict},
+		{"CapacityReached", &sErr.CapacityReachedError{Space: "Room", Capacity: 1}, http.StatusConflict},
+		{"InvalidState", &sErr.InvalidStateError{Space: "Room", State: "running", Action: "change gamemode"}, http.StatusConflict},
+		{"Authorization", &sErr.AuthorizationError{Space: "Room", Reason: "no token"}, http.StatusUnauthorized},
+		{"Unavailable", &sErr.UnavailableError{Space: "Rooms", Reason: "shutting down"}, http.StatusServiceUnavailable},
+		{"LimitExceeded", &sErr.LimitExceededError{Space: "Rooms", Reason: "too many"}, http.StatusTooManyRequests},
+		{"Wrapped", fmt.Errorf("could not join: %w", &sErr.CapacityReachedError{Space: "Room", Capacity: 1}), http.StatusConflict},
+		{"Other", errors.New("unexpected"), http.StatusInternalServerError},
+	}
+
+	for _, test := range tests {
+		got := errorStatus(test.err)
+		if got != test.want {
+			t.Fatalf(`errorStatus(%s) = %d, want %d`, test.name, got, test.want)
+		}
+	}
+}
+
+// serveJSONRequest sends body as JSON, letting prepare adjust the request
+// first when it is not nil.
+func serveJSONRequest(t *testing.T, method string, path string, body any, prepare func(request *http.Request)) *httptest.ResponseRecorder {
+	var reqBody []byte
+	if body != nil {
+		var err error
+		reqBody, err = json.Marshal(body)
+		if err != nil {
+			t.Fatalf(`Marshal(%s %s) = %v, want nil`, method, path, err)
+		}
+	}
+
+	request := httptest.NewRequest(method, path, strings.NewReader(string(reqBody)))
+	request.Header.Set("Content-Type", "application/json")
+	if prepare != nil {
+		prepare(request)
+	}
+
+	recorder := httptest.NewRecorder()
+	newRouter().ServeHTTP(recorder, request)
+
+	return recorder
+}
+
+// serveSignedRequest signs the request as a game mode would. An empty secret
+// leaves it unsigned.
+func serveSignedRequest(t *testing.T, method string, path string, body any, secret string) *httptest.ResponseRecorder {
+	reqBody, _ := json.Marshal(body)
+	if body == nil {
+		reqBody = nil
+	}
+
+	return serveJSONRequest(t, method, path, body, func(request *http.Request) {
+		if secret != "" {
+			utils.SignRequest(request, secret, reqBody)
+		}
+	})
+}
+
+func decodeResponse[T any](t *testing.T, recorder *httptest.ResponseRecorder) T {
+	var response T
+	err := json.Unmarshal(recorder.Body.Bytes(), &response)
+	if err != nil {
+		t.Fatalf(`Unmarshal(%d, %s) = %v, want nil`, recorder.Code, recorder.Body.String(), err)
+	}
+
+	return response
+}
+
+func requireErrorResponse(t *testing.T, recorder *httptest.ResponseRecorder, request string, want int) {
+	t.Helper()
+
+	var response ErrorResponse
+	err := json.Unmarshal(recorder.Body.Bytes(), &response)
+	if recorder.Code != want || err != nil || response.Error == "" {
+		t.Fatalf(`%s = %d, %s, want %d with an error body`, request, recorder.Code, recorder.Body.String(), want)
+	}
+}
+
+func containsRoom(rooms []RoomResponse, rid string) bool {
+	for _, r := range rooms {
+		if r.RID == rid {
+			return true
+		}
+	}
+
+	return false
+}
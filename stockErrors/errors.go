@@ -66,6 +66,61 @@ func (e *HttpRequestError) Error() string {
 	return fmt.Sprintf("http request %q failed. Returned error code: %d", e.Call, e.Code)
 }
 
+type AuthorizationError struct {
+	Space  string
+	Reason string
+}
+
+func (e *AuthorizationError) Error() string {
+	return fmt.Sprintf("authorization failed for %s: %s", e.Space, e.Reason)
+}
+
+type IncompatibleVersionError struct {
+	Current   string
+	Requested string
+}
+
+func (e *IncompatibleVersionError) Error() string {
+	return fmt.Sprintf("gamemode %s is not compatible with running gamemode %s", e.Requested, e.Current)
+}
+
+type UnavailableError struct {
+	Space  string
+	Reason string
+}
+
+func (e *UnavailableError) Error() string {
+	return fmt.Sprintf("%s is unavailable: %s", e.Space, e.Reason)
+}
+
+type LimitExceededError struct {
+	Space  string
+	Reason string
+}
+
+func (e *LimitExceededError) Error() string {
+	return fmt.Sprintf("%s limit exceeded: %s", e.Space, e.Reason)
+}
+
+type CapacityReachedError struct {
+	Space    string
+	Capacity int
+}
+
+func (e *CapacityReachedError) Error() string {
+	return fmt.Sprintf("%s is full: all %d places are taken", e.Space, e.Capacity)
+}
+
+type InvalidStateError struct {
+	Space  string
+	State  string
+	Action string
+}
+
+func (e *InvalidStateError) Error() string {
+	return fmt.Sprintf("%s is %s: cannot %s", e.Space, e.State, e.Action)
+}
+
 var (
 	EV_ERR  *EmptyValueError
 	IV_ERR  *InvalidValueError[string]
@@ -73,4 +128,10 @@ var (
 	MF_ERR  *MatchFoundError[string]
 	ES_ERR  *EmptySetError
 	HR_ERR  *HttpRequestError
+	AU_ERR  *AuthorizationError
+	IC_ERR  *IncompatibleVersionError
+	UA_ERR  *UnavailableError
+	LE_ERR  *LimitExceededError
+	CR_ERR  *CapacityReachedError
+	IS_ERR  *InvalidStateError
 )
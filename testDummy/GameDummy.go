@@ -1,40 +1,104 @@
 package testDummy
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+
+	"Engee-Server/cors"
+	"Engee-Server/gameClient/payload"
 )
 
-func corsMiddleWare() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "*")
-		c.Header("Access-Control-Allow-Headers", "*")
+func Serve(port string) {
+	router := gin.Default()
+
+	router.Use(cors.MiddleWare(cors.DefaultPolicy()))
 
-		if c.Request.Method == "OPTIONS" {
-			c.Writer.WriteHeader(http.StatusOK)
-			c.Writer.Write([]byte(""))
-		}
+	router.POST("/games", createGame)
+	router.GET("/games/:id", func(c *gin.Context) {
+		sendReply(c, []byte(`{"phase":"Created","scores":{},"players":[]}`))
+	})
+	router.GET("/games/:id/snapshot", func(c *gin.Context) {
+		sendReply(c, []byte(`{"phase":"Created","scores":{}}`))
+	})
+	router.PUT("/games/:id/start", gameCommand)
+	router.PUT("/games/:id/pause", gameCommand)
+	router.PUT("/games/:id/reset", gameCommand)
+	router.PUT("/games/:id/rules", setRules)
+	router.DELETE("/games/:id/players/:uid", gameCommand)
+	router.DELETE("/games/:id", gameCommand)
 
-		c.Next()
+	router.Run(":" + port)
+}
+
+func createGame(c *gin.Context) {
+	var request payload.CreateGameRequest
+	err := json.NewDecoder(c.Request.Body).Decode(&request)
+	if err == nil {
+		err = validateRequest(request.Version, request.RID, request.RID)
 	}
+
+	echoReply(c, request.RID, err)
 }
 
-func Serve(port string) {
-	router := gin.Default()
+func setRules(c *gin.Context) {
+	var request payload.RulesRequest
+	err := json.NewDecoder(c.Request.Body).Decode(&request)
+	if err == nil {
+		err = validateRequest(request.Version, request.RID, c.Param("id"))
+	}
 
-	router.Use(corsMiddleWare())
+	echoReply(c, request.RID, err)
+}
 
-	router.POST("/games", func(c *gin.Context) { c.Writer.Write([]byte{}) })
-	router.PUT("/games/:id/start", func(c *gin.Context) { c.Writer.Write([]byte{}) })
-	router.PUT("/games/:id/pause", func(c *gin.Context) { c.Writer.Write([]byte{}) })
-	router.PUT("/games/:id/reset", func(c *gin.Context) { c.Writer.Write([]byte{}) })
-	router.PUT("/games/:id/rules", func(c *gin.Context) { c.Writer.Write([]byte{}) })
-	router.DELETE("/games/:id/players/:id", func(c *gin.Context) { c.Writer.Write([]byte{}) })
-	router.DELETE("/games/:id", func(c *gin.Context) { c.Writer.Write([]byte{}) })
+func gameCommand(c *gin.Context) {
+	var request payload.CommandRequest
+	err := json.NewDecoder(c.Request.Body).Decode(&request)
+	if err == nil {
+		err = validateRequest(request.Version, request.RID, c.Param("id"))
+	}
 
-	router.Run(":" + port)
+	if err == nil && c.Param("uid") != "" && request.UID != c.Param("uid") {
+		err = fmt.Errorf("uid %q does not match path", request.UID)
+	}
+
+	echoReply(c, request.RID, err)
+}
+
+func validateRequest(version int, rid string, pathRID string) error {
+	err := payload.CheckVersion(version)
+	if err != nil {
+		return err
+	}
+
+	if rid == "" || rid != pathRID {
+		return fmt.Errorf("rid %q does not match path", rid)
+	}
+
+	return nil
+}
+
+func echoReply(c *gin.Context, rid string, err error) {
+	reply := payload.Reply{
+		Version: payload.Version,
+		RID:     rid,
+		Status:  payload.StatusOK,
+	}
+
+	if err != nil {
+		reply.Status = payload.StatusError
+		reply.Message = err.Error()
+
+		response, _ := json.Marshal(reply)
+		c.Writer.WriteHeader(http.StatusBadRequest)
+		c.Writer.Write(response)
+		return
+	}
+
+	response, _ := json.Marshal(reply)
+	sendReply(c, response)
 }
 
 func sendReply(c *gin.Context, response []byte) {
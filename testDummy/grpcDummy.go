@@ -0,0 +1,161 @@
+package testDummy
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"Engee-Server/gameClient/gamepb"
+)
+
+type GRPCGameDummy struct {
+	gamepb.UnimplementedGameServiceServer
+
+	lock      sync.Mutex
+	games     map[string]*gamepb.GameState
+	rules     map[string]string
+	snapshots map[string][]byte
+}
+
+func ServeGRPC(listener net.Listener, options ...grpc.ServerOption) (*grpc.Server, *GRPCGameDummy) {
+	dummy := &GRPCGameDummy{
+		games:     make(map[string]*gamepb.GameState),
+		rules:     make(map[string]string),
+		snapshots: make(map[string][]byte),
+	}
+
+	server := grpc.NewServer(options...)
+	gamepb.RegisterGameServiceServer(server, dummy)
+
+	go server.Serve(listener)
+
+	return server, dummy
+}
+
+func (d *GRPCGameDummy) GetRules(rid string) string {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	return d.rules[rid]
+}
+
+func (d *GRPCGameDummy) GetSnapshot(rid string) []byte {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	return d.snapshots[rid]
+}
+
+func (d *GRPCGameDummy) CreateGame(ctx context.Context, request *gamepb.CreateGameRequest) (*gamepb.GameReply, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	players := make([]string, 0, len(request.GetPlayers()))
+	for _, player := range request.GetPlayers() {
+		players = append(players, player.GetUid())
+	}
+
+	d.games[request.GetRid()] = &gamepb.GameState{
+		Phase:   "Created",
+		Scores:  make(map[string]int64),
+		Players: players,
+	}
+	d.rules[request.GetRid()] = request.GetRules()
+	d.snapshots[request.GetRid()] = request.GetSnapshot()
+
+	return &gamepb.GameReply{}, nil
+}
+
+func (d *GRPCGameDummy) EndGame(ctx context.Context, request *gamepb.GameRequest) (*gamepb.GameReply, error) {
+	return d.update(request.GetRid(), func(game *gamepb.GameState) {
+		delete(d.games, request.GetRid())
+		delete(d.rules, request.GetRid())
+		delete(d.snapshots, request.GetRid())
+	})
+}
+
+func (d *GRPCGameDummy) SetRules(ctx context.Context, request *gamepb.SetRulesRequest) (*gamepb.GameReply, error) {
+	return d.update(request.GetRid(), func(game *gamepb.GameState) {
+		d.rules[request.GetRid()] = request.GetRules()
+	})
+}
+
+func (d *GRPCGameDummy) StartGame(ctx context.Context, request *gamepb.GameRequest) (*gamepb.GameReply, error) {
+	return d.update(request.GetRid(), func(game *gamepb.GameState) {
+		game.Phase = "Running"
+	})
+}
+
+func (d *GRPCGameDummy) PauseGame(ctx context.Context, request *gamepb.GameRequest) (*gamepb.GameReply, error) {
+	return d.update(request.GetRid(), func(game *gamepb.GameState) {
+		game.Phase = "Paused"
+	})
+}
+
+func (d *GRPCGameDummy) ResetGame(ctx context.Context, request *gamepb.GameRequest) (*gamepb.GameReply, error) {
+	return d.update(request.GetRid(), func(game *gamepb.GameState) {
+		game.Phase = "Created"
+		game.Scores = make(map[string]int64)
+	})
+}
+
+func (d *GRPCGameDummy) RemovePlayer(ctx context.Context, request *gamepb.RemovePlayerRequest) (*gamepb.GameReply, error) {
+	return d.update(request.GetRid(), func(game *gamepb.GameState) {
+		players := make([]string, 0, len(game.Players))
+		for _, player := range game.Players {
+			if player != request.GetUid() {
+				players = append(players, player)
+			}
+		}
+
+		game.Players = players
+	})
+}
+
+func (d *GRPCGameDummy) GetState(ctx context.Context, request *gamepb.GameRequest) (*gamepb.GameState, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	game, found := d.games[request.GetRid()]
+	if !found {
+		return nil, status.Errorf(codes.NotFound, "game %s not found", request.GetRid())
+	}
+
+	return proto.Clone(game).(*gamepb.GameState), nil
+}
+
+func (d *GRPCGameDummy) ExportSnapshot(ctx context.Context, request *gamepb.GameRequest) (*gamepb.Snapshot, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	game, found := d.games[request.GetRid()]
+	if !found {
+		return nil, status.Errorf(codes.NotFound, "game %s not found", request.GetRid())
+	}
+
+	state, err := proto.Marshal(game)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "could not export game %s: %v", request.GetRid(), err)
+	}
+
+	return &gamepb.Snapshot{State: state}, nil
+}
+
+func (d *GRPCGameDummy) update(rid string, apply func(game *gamepb.GameState)) (*gamepb.GameReply, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	game, found := d.games[rid]
+	if !found {
+		return nil, status.Errorf(codes.NotFound, "game %s not found", rid)
+	}
+
+	apply(game)
+
+	return &gamepb.GameReply{}, nil
+}
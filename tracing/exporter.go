@@ -0,0 +1,189 @@
+package tracing
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	sErr "Engee-Server/stockErrors"
+)
+
+const ExporterNone = ""
+const ExporterStdout = "stdout"
+const ExporterFile = "file"
+
+const serviceName = "Engee-Server"
+
+var exportLock sync.Mutex
+var exporter io.Writer
+var exportFile *os.File
+
+// The exported types mirror the OTLP/JSON trace encoding, one
+// ExportTraceServiceRequest per line, which the OpenTelemetry Collector's
+// file receiver can forward to any tracing backend.
+type exportRequest struct {
+	ResourceSpans []resourceSpans `json:"resourceSpans"`
+}
+
+type resourceSpans struct {
+	Resource   resource     `json:"resource"`
+	ScopeSpans []scopeSpans `json:"scopeSpans"`
+}
+
+type resource struct {
+	Attributes []keyValue `json:"attributes"`
+}
+
+type scopeSpans struct {
+	Scope scope          `json:"scope"`
+	Spans []exportedSpan `json:"spans"`
+}
+
+type scope struct {
+	Name string `json:"name"`
+}
+
+type exportedSpan struct {
+	TraceID           string     `json:"traceId"`
+	SpanID            string     `json:"spanId"`
+	ParentSpanID      string     `json:"parentSpanId,omitempty"`
+	Name              string     `json:"name"`
+	Kind              SpanKind   `json:"kind"`
+	StartTimeUnixNano string     `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string     `json:"endTimeUnixNano"`
+	Attributes        []keyValue `json:"attributes,omitempty"`
+	Status            status     `json:"status"`
+}
+
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+type anyValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type status struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// Configure selects where finished spans are written: nowhere, stdout, or
+// appended to file.
+func Configure(kind string, file string) error {
+	var writer io.Writer
+	var opened *os.File
+
+	switch kind {
+	case ExporterNone:
+	case ExporterStdout:
+		writer = os.Stdout
+	case ExporterFile:
+		if file == "" {
+			return &sErr.EmptyValueError{
+				Field: "Trace File",
+			}
+		}
+
+		var err error
+		opened, err = os.OpenFile(file, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return err
+		}
+
+		writer = opened
+	default:
+		return &sErr.InvalidValueError[string]{
+			Field: "Trace Exporter",
+			Value: kind,
+		}
+	}
+
+	Close()
+
+	exportLock.Lock()
+	defer exportLock.Unlock()
+
+	exporter = writer
+	exportFile = opened
+
+	return nil
+}
+
+func Close() error {
+	exportLock.Lock()
+	defer exportLock.Unlock()
+
+	exporter = nil
+	if exportFile == nil {
+		return nil
+	}
+
+	err := exportFile.Close()
+	exportFile = nil
+
+	return err
+}
+
+func export(s *Span, end time.Time) {
+	exportLock.Lock()
+	defer exportLock.Unlock()
+
+	if exporter == nil {
+		return
+	}
+
+	line, err := json.Marshal(exportRequest{
+		ResourceSpans: []resourceSpans{{
+			Resource: resource{
+				Attributes: []keyValue{{Key: "service.name", Value: anyValue{StringValue: serviceName}}},
+			},
+			ScopeSpans: []scopeSpans{{
+				Scope: scope{Name: serviceName},
+				Spans: []exportedSpan{s.exported(end)},
+			}},
+		}},
+	})
+	if err != nil {
+		return
+	}
+
+	exporter.Write(append(line, '\n'))
+}
+
+func (s *Span) exported(end time.Time) exportedSpan {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	span := exportedSpan{
+		TraceID:           s.TraceID(),
+		SpanID:            s.SpanID(),
+		Name:              s.name,
+		Kind:              s.kind,
+		StartTimeUnixNano: strconv.FormatInt(s.start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(end.UnixNano(), 10),
+		Status:            status{Code: s.status, Message: s.message},
+	}
+
+	if s.parentID != [8]byte{} {
+		span.ParentSpanID = hex.EncodeToString(s.parentID[:])
+	}
+
+	keys := make([]string, 0, len(s.attributes))
+	for key := range s.attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		span.Attributes = append(span.Attributes, keyValue{Key: key, Value: anyValue{StringValue: s.attributes[key]}})
+	}
+
+	return span
+}
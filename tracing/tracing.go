@@ -0,0 +1,215 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+)
+
+const TraceParentHeader = "traceparent"
+
+type SpanKind int
+
+// Span kinds and status codes use the OpenTelemetry protocol values so
+// exported spans can be read by OTLP tooling as they are.
+const (
+	KindInternal SpanKind = 1
+	KindServer   SpanKind = 2
+	KindClient   SpanKind = 3
+)
+
+const statusError = 2
+
+const flagSampled = 0x01
+
+type contextKey struct{}
+
+type Span struct {
+	traceID  [16]byte
+	spanID   [8]byte
+	parentID [8]byte
+	flags    byte
+	name     string
+	kind     SpanKind
+	start    time.Time
+
+	lock       sync.Mutex
+	attributes map[string]string
+	status     int
+	message    string
+	ended      bool
+}
+
+// Start begins a span for work done inside the lobby server, as a child of
+// the span carried by ctx when there is one.
+func Start(ctx context.Context, name string) (context.Context, *Span) {
+	return startSpan(ctx, name, KindInternal, SpanFromContext(ctx))
+}
+
+// StartClient begins a span for a call made to another server.
+func StartClient(ctx context.Context, name string) (context.Context, *Span) {
+	return startSpan(ctx, name, KindClient, SpanFromContext(ctx))
+}
+
+// StartServer begins a span for a request received from a caller, continuing
+// the caller's trace when traceParent is a valid W3C traceparent header.
+func StartServer(ctx context.Context, name string, traceParent string) (context.Context, *Span) {
+	return startSpan(ctx, name, KindServer, parseTraceParent(traceParent))
+}
+
+func startSpan(ctx context.Context, name string, kind SpanKind, parent *Span) (context.Context, *Span) {
+	span := &Span{
+		name:       name,
+		kind:       kind,
+		start:      time.Now(),
+		flags:      flagSampled,
+		attributes: make(map[string]string),
+	}
+
+	if parent != nil {
+		span.traceID = parent.traceID
+		span.parentID = parent.spanID
+		span.flags = parent.flags
+	} else {
+		rand.Read(span.traceID[:])
+	}
+
+	rand.Read(span.spanID[:])
+
+	return context.WithValue(ctx, contextKey{}, span), span
+}
+
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(contextKey{}).(*Span)
+	return span
+}
+
+// TraceParent returns the W3C traceparent header for the span carried by
+// ctx, or "" when ctx carries no span.
+func TraceParent(ctx context.Context) string {
+	span := SpanFromContext(ctx)
+	if span == nil {
+		return ""
+	}
+
+	return span.TraceParent()
+}
+
+func (s *Span) TraceID() string {
+	if s == nil {
+		return ""
+	}
+
+	return hex.EncodeToString(s.traceID[:])
+}
+
+func (s *Span) SpanID() string {
+	if s == nil {
+		return ""
+	}
+
+	return hex.EncodeToString(s.spanID[:])
+}
+
+func (s *Span) TraceParent() string {
+	return "00-" + s.TraceID() + "-" + s.SpanID() + "-" + hex.EncodeToString([]byte{s.flags})
+}
+
+func (s *Span) SetAttribute(key string, value string) {
+	if s == nil {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.attributes[key] = value
+}
+
+// RecordError marks the span as failed with err. A nil err is ignored, so
+// callers can record whatever error an operation returned.
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.status = statusError
+	s.message = err.Error()
+}
+
+// End finishes the span and hands it to the configured exporter. Only the
+// first call has any effect.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+
+	end := time.Now()
+
+	s.lock.Lock()
+	if s.ended {
+		s.lock.Unlock()
+		return
+	}
+	s.ended = true
+	s.lock.Unlock()
+
+	if s.flags&flagSampled != 0 {
+		export(s, end)
+	}
+}
+
+func parseTraceParent(header string) *Span {
+	parts := strings.Split(strings.TrimSpace(header), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" {
+		return nil
+	}
+
+	if parts[0] == "00" && len(parts) != 4 {
+		return nil
+	}
+
+	span := &Span{}
+	if !decodeID(parts[1], span.traceID[:]) || !decodeID(parts[2], span.spanID[:]) {
+		return nil
+	}
+
+	flags := make([]byte, 1)
+	if !decodeID(parts[3], flags) {
+		return nil
+	}
+	span.flags = flags[0] & flagSampled
+
+	return span
+}
+
+// decodeID fills id from lowercase hex, rejecting the all-zero IDs the W3C
+// trace context specification reserves as invalid.
+func decodeID(value string, id []byte) bool {
+	if len(value) != hex.EncodedLen(len(id)) || strings.ToLower(value) != value {
+		return false
+	}
+
+	_, err := hex.Decode(id, []byte(value))
+	if err != nil {
+		return false
+	}
+
+	if len(id) == 1 {
+		return true
+	}
+
+	for _, b := range id {
+		if b != 0 {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,112 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	sErr "Engee-Server/stockErrors"
+)
+
+const testTraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+const testTraceParent = "00-" + testTraceID + "-00f067aa0ba902b7-01"
+
+func TestStartServerContinuesTrace(t *testing.T) {
+	_, span := StartServer(context.Background(), "GET /", testTraceParent)
+
+	if span.TraceID() != testTraceID || span.parentID == [8]byte{} {
+		t.Fatalf(`StartServer(TraceParent) = %s, want trace %s with parent`, span.TraceParent(), testTraceID)
+	}
+}
+
+func TestStartServerInvalidTraceParent(t *testing.T) {
+	for _, header := range []string{
+		"",
+		"00-" + testTraceID + "-00f067aa0ba902b7",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-" + testTraceID + "-0000000000000000-01",
+		"00-" + strings.ToUpper(testTraceID) + "-00f067aa0ba902b7-01",
+		"ff-" + testTraceID + "-00f067aa0ba902b7-01",
+	} {
+		_, span := StartServer(context.Background(), "GET /", header)
+		if span.TraceID() == testTraceID || span.parentID != [8]byte{} {
+			t.Fatalf(`StartServer(%q) = %s, want new root trace`, header, span.TraceParent())
+		}
+	}
+}
+
+func TestStartChildSpan(t *testing.T) {
+	ctx, parent := Start(context.Background(), "parent")
+	_, child := StartClient(ctx, "child")
+
+	if child.TraceID() != parent.TraceID() || child.parentID != parent.spanID {
+		t.Fatalf(`StartClient(Child) = %s, want child of %s`, child.TraceParent(), parent.TraceParent())
+	}
+
+	if TraceParent(ctx) != parent.TraceParent() {
+		t.Fatalf(`TraceParent(ctx) = %q, want %q`, TraceParent(ctx), parent.TraceParent())
+	}
+}
+
+func TestExport(t *testing.T) {
+	out := setupExportTest(t)
+
+	_, span := StartServer(context.Background(), "GET /rooms", testTraceParent)
+	span.SetAttribute("rid", "room")
+	span.RecordError(errors.New("failed"))
+	span.End()
+	span.End()
+
+	var request exportRequest
+	err := json.Unmarshal(out.Bytes(), &request)
+	if err != nil {
+		t.Fatalf(`End(Export) = %q, %v, want one OTLP JSON line`, out.String(), err)
+	}
+
+	exported := request.ResourceSpans[0].ScopeSpans[0].Spans[0]
+	if exported.TraceID != testTraceID || exported.ParentSpanID != "00f067aa0ba902b7" || exported.Kind != KindServer {
+		t.Fatalf(`End(Export) = %+v, want server span continuing %s`, exported, testTraceParent)
+	}
+
+	if exported.Status.Code != statusError || exported.Attributes[0].Value.StringValue != "room" {
+		t.Fatalf(`End(Export) = %+v, want error status and rid attribute`, exported)
+	}
+}
+
+func TestExportUnsampled(t *testing.T) {
+	out := setupExportTest(t)
+
+	_, span := StartServer(context.Background(), "GET /rooms", "00-"+testTraceID+"-00f067aa0ba902b7-00")
+	span.End()
+
+	if out.Len() != 0 {
+		t.Fatalf(`End(Unsampled) = %q, want nothing exported`, out.String())
+	}
+}
+
+func TestConfigureInvalid(t *testing.T) {
+	err := Configure("collector", "")
+	if !errors.As(err, &sErr.IV_ERR) {
+		t.Fatalf(`Configure(InvalidExporter) = %v, want InvalidValueError`, err)
+	}
+
+	err = Configure(ExporterFile, "")
+	if !errors.As(err, &sErr.EV_ERR) {
+		t.Fatalf(`Configure(NoFile) = %v, want EmptyValueError`, err)
+	}
+}
+
+func setupExportTest(t *testing.T) *bytes.Buffer {
+	out := &bytes.Buffer{}
+
+	exportLock.Lock()
+	exporter = out
+	exportLock.Unlock()
+
+	t.Cleanup(func() { Close() })
+
+	return out
+}
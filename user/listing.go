@@ -0,0 +1,66 @@
+package user
+
+import (
+	"strings"
+
+	"golang.org/x/exp/maps"
+
+	sErr "Engee-Server/stockErrors"
+	"Engee-Server/utils"
+)
+
+const SortCreated = "created"
+const SortName = "name"
+
+type UserFilter struct {
+	Status string
+	Name   string
+}
+
+func ListUsers(filter UserFilter, page utils.PageRequest) ([]User, string, error) {
+	if page.Sort == "" {
+		page.Sort = SortCreated
+	}
+
+	var key func(User) utils.SortKey
+	switch page.Sort {
+	case SortCreated:
+		key = func(u User) utils.SortKey {
+			return utils.SortKey{Value: utils.NumericSortValue(u.Created.UnixNano()), ID: u.UID}
+		}
+	case SortName:
+		key = func(u User) utils.SortKey {
+			return utils.SortKey{Value: strings.ToLower(u.Name), ID: u.UID}
+		}
+	default:
+		return nil, "", &sErr.InvalidValueError[string]{
+			Field: "Sort",
+			Value: page.Sort,
+		}
+	}
+
+	lock.Lock()
+	all := maps.Values(users)
+	lock.Unlock()
+
+	matches := make([]User, 0)
+	for _, u := range all {
+		if filter.matches(u) {
+			matches = append(matches, u)
+		}
+	}
+
+	return utils.Paginate(matches, page, key)
+}
+
+func (filter UserFilter) matches(u User) bool {
+	if filter.Status != "" && !strings.EqualFold(u.Status, filter.Status) {
+		return false
+	}
+
+	if filter.Name != "" && !strings.Contains(strings.ToLower(u.Name), strings.ToLower(filter.Name)) {
+		return false
+	}
+
+	return true
+}
@@ -1,24 +1,52 @@
 package user
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 
+	"Engee-Server/metrics"
 	sErr "Engee-Server/stockErrors"
 	"Engee-Server/utils"
 )
 
 type User struct {
-	UID    string `json:"uid"`
-	Name   string `json:"name"`
-	Status string `json:"status"`
+	UID     string    `json:"uid"`
+	Name    string    `json:"name"`
+	Status  string    `json:"status"`
+	Created time.Time `json:"created"`
 }
 
+var lock sync.Mutex
 var users = make(map[string]User)
 var heartbeats map[string]time.Time
+var tokens = make(map[string]string)
+var userIPs = make(map[string]string)
+var maxUsersPerIP int
+
+var heartbeatExpiries = metrics.NewCounter(
+	"engee_user_heartbeat_expiries_total",
+	"Users deleted after missing their heartbeat.",
+)
+
+func SetMaxUsersPerIP(max int) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	maxUsersPerIP = max
+}
 
 func CreateUser(name string) (string, error) {
+	return CreateUserFromIP(name, "")
+}
+
+// CreateUserFromIP creates a user on behalf of a client at ip, refusing once
+// that client already has the configured maximum number of users.
+func CreateUserFromIP(name string, ip string) (string, error) {
 	if name == "" {
 		return "", &sErr.EmptyValueError{
 			Field: "Name",
@@ -29,20 +57,49 @@ func CreateUser(name string) (string, error) {
 	newUser.UID = uuid.NewString()
 	newUser.Name = name
 	newUser.Status = "New"
+	newUser.Created = time.Now().UTC()
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	if ip != "" && maxUsersPerIP > 0 && countUsersFromIP(ip) >= maxUsersPerIP {
+		return "", &sErr.LimitExceededError{
+			Space:  "Users per IP",
+			Reason: fmt.Sprintf("%s already has %d users", ip, maxUsersPerIP),
+		}
+	}
 
 	if heartbeats == nil {
 		heartbeats = make(map[string]time.Time)
-		go utils.MonitorHeartbeats(&heartbeats, DeleteUser)
+		go utils.MonitorHeartbeats(&heartbeats, &lock, expireUser)
 	}
 
 	users[newUser.UID] = newUser
 	heartbeats[newUser.UID] = time.Now()
 
+	if ip != "" {
+		userIPs[newUser.UID] = ip
+	}
+
 	return newUser.UID, nil
 }
 
+func countUsersFromIP(ip string) int {
+	count := 0
+	for _, userIP := range userIPs {
+		if userIP == ip {
+			count++
+		}
+	}
+
+	return count
+}
+
 func Heartbeat(uid string) error {
-	_, err := GetUser(uid)
+	lock.Lock()
+	defer lock.Unlock()
+
+	_, err := getUser(uid)
 	if err != nil {
 		return err
 	}
@@ -53,22 +110,17 @@ func Heartbeat(uid string) error {
 }
 
 func GetUser(uid string) (User, error) {
-	if uid == "" {
-		return User{}, &sErr.EmptyValueError{
-			Field: "UID",
-		}
-	}
+	lock.Lock()
+	defer lock.Unlock()
 
-	user, found := users[uid]
-	if !found {
-		return user, &sErr.MatchNotFoundError[string]{
-			Space: "Users",
-			Field: "UID",
-			Value: uid,
-		}
-	}
+	return getUser(uid)
+}
 
-	return user, nil
+func CountUsers() int {
+	lock.Lock()
+	defer lock.Unlock()
+
+	return len(users)
 }
 
 func UpdateUserName(uid string, name string) error {
@@ -78,7 +130,10 @@ func UpdateUserName(uid string, name string) error {
 		}
 	}
 
-	user, err := GetUser(uid)
+	lock.Lock()
+	defer lock.Unlock()
+
+	user, err := getUser(uid)
 	if err != nil {
 		return err
 	}
@@ -96,7 +151,10 @@ func UpdateUserStatus(uid string, status string) error {
 		}
 	}
 
-	user, err := GetUser(uid)
+	lock.Lock()
+	defer lock.Unlock()
+
+	user, err := getUser(uid)
 	if err != nil {
 		return err
 	}
@@ -107,14 +165,95 @@ func UpdateUserStatus(uid string, status string) error {
 	return nil
 }
 
+func expireUser(uid string) error {
+	err := DeleteUser(uid)
+	if err != nil {
+		return err
+	}
+
+	heartbeatExpiries.Inc()
+	return nil
+}
+
 func DeleteUser(uid string) error {
-	_, err := GetUser(uid)
+	lock.Lock()
+	defer lock.Unlock()
+
+	_, err := getUser(uid)
 	if err != nil {
 		return err
 	}
 
 	delete(users, uid)
 	delete(heartbeats, uid)
+	delete(userIPs, uid)
+
+	for token, owner := range tokens {
+		if owner == uid {
+			delete(tokens, token)
+		}
+	}
 
 	return nil
 }
+
+func IssueUserToken(uid string) (string, error) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	_, err := getUser(uid)
+	if err != nil {
+		return "", err
+	}
+
+	tokenBytes := make([]byte, 32)
+	_, err = rand.Read(tokenBytes)
+	if err != nil {
+		return "", fmt.Errorf("could not generate token: %w", err)
+	}
+
+	token := hex.EncodeToString(tokenBytes)
+	tokens[token] = uid
+
+	return token, nil
+}
+
+func AuthenticateUser(token string) (string, error) {
+	if token == "" {
+		return "", &sErr.EmptyValueError{
+			Field: "Token",
+		}
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	uid, found := tokens[token]
+	if !found {
+		return "", &sErr.AuthorizationError{
+			Space:  "Users",
+			Reason: "token is not recognised",
+		}
+	}
+
+	return uid, nil
+}
+
+func getUser(uid string) (User, error) {
+	if uid == "" {
+		return User{}, &sErr.EmptyValueError{
+			Field: "UID",
+		}
+	}
+
+	user, found := users[uid]
+	if !found {
+		return user, &sErr.MatchNotFoundError[string]{
+			Space: "Users",
+			Field: "UID",
+			Value: uid,
+		}
+	}
+
+	return user, nil
+}
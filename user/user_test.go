@@ -7,6 +7,7 @@ import (
 	"github.com/google/uuid"
 
 	sErr "Engee-Server/stockErrors"
+	"Engee-Server/utils"
 )
 
 var testUser = User{
@@ -31,6 +32,39 @@ func TestCreateUser(t *testing.T) {
 	t.Cleanup(cleanAfterTest)
 }
 
+func TestCreateUserFromIPLimit(t *testing.T) {
+	SetMaxUsersPerIP(1)
+	t.Cleanup(cleanAfterTest)
+
+	_, err := CreateUserFromIP(testUserName, "192.0.2.1")
+	if err != nil {
+		t.Fatalf(`CreateUserFromIP(First) = %v, want nil`, err)
+	}
+
+	id, err := CreateUserFromIP(testUserName, "192.0.2.1")
+	if id != "" || !errors.As(err, &sErr.LE_ERR) {
+		t.Fatalf(`CreateUserFromIP(OverLimit) = %q, %v, want "", LimitExceededError`, id, err)
+	}
+
+	_, err = CreateUserFromIP(testUserName, "192.0.2.2")
+	if err != nil {
+		t.Fatalf(`CreateUserFromIP(OtherIP) = %v, want nil`, err)
+	}
+}
+
+func TestCreateUserFromIPLimitFreed(t *testing.T) {
+	SetMaxUsersPerIP(1)
+	t.Cleanup(cleanAfterTest)
+
+	id, _ := CreateUserFromIP(testUserName, "192.0.2.1")
+	DeleteUser(id)
+
+	_, err := CreateUserFromIP(testUserName, "192.0.2.1")
+	if err != nil {
+		t.Fatalf(`CreateUserFromIP(AfterDelete) = %v, want nil`, err)
+	}
+}
+
 func TestCreateUniqueNameUsers(t *testing.T) {
 	CreateUser(testUserName)
 	id, err := CreateUser(newUserName)
@@ -233,11 +267,92 @@ func TestDeleteDouble(t *testing.T) {
 	}
 }
 
+func TestAuthenticateUser(t *testing.T) {
+	id, _ := setupUserTest(t)
+
+	token, err := IssueUserToken(id)
+	if token == "" || err != nil {
+		t.Fatalf(`IssueUserToken(Valid) = %q, %v, want "token", nil`, token, err)
+	}
+
+	uid, err := AuthenticateUser(token)
+	if uid != id || err != nil {
+		t.Fatalf(`AuthenticateUser(Valid) = %q, %v, want %q, nil`, uid, err, id)
+	}
+}
+
+func TestIssueUserTokenInvalidID(t *testing.T) {
+	setupUserTest(t)
+
+	token, err := IssueUserToken(randomID)
+	if token != "" || !errors.As(err, &sErr.MNF_ERR) {
+		t.Fatalf(`IssueUserToken(InvalidID) = %q, %v, want "", MatchNotFoundError`, token, err)
+	}
+}
+
+func TestAuthenticateUserInvalidToken(t *testing.T) {
+	setupUserTest(t)
+
+	uid, err := AuthenticateUser(randomID)
+	if uid != "" || !errors.As(err, &sErr.AU_ERR) {
+		t.Fatalf(`AuthenticateUser(InvalidToken) = %q, %v, want "", AuthorizationError`, uid, err)
+	}
+}
+
+func TestAuthenticateUserAfterDelete(t *testing.T) {
+	id, _ := setupUserTest(t)
+
+	token, _ := IssueUserToken(id)
+	DeleteUser(id)
+
+	_, err := AuthenticateUser(token)
+	if !errors.As(err, &sErr.AU_ERR) {
+		t.Fatalf(`AuthenticateUser(DeletedUser) = %v, want AuthorizationError`, err)
+	}
+}
+
+func TestListUsers(t *testing.T) {
+	id, _ := setupUserTest(t)
+	CreateUser(newUserName)
+
+	users, next, err := ListUsers(UserFilter{Name: "test"}, utils.PageRequest{})
+	if len(users) != 1 || users[0].UID != id || next != "" || err != nil {
+		t.Fatalf(`ListUsers(Name) = %v, %q, %v, want [test user], "", nil`, users, next, err)
+	}
+}
+
+func TestListUsersSortedByName(t *testing.T) {
+	setupUserTest(t)
+	CreateUser(newUserName)
+
+	users, next, err := ListUsers(UserFilter{}, utils.PageRequest{Sort: SortName, Limit: 1})
+	if len(users) != 1 || users[0].Name != newUserName || next == "" || err != nil {
+		t.Fatalf(`ListUsers(SortName) = %v, %q, %v, want [%s], cursor, nil`, users, next, err, newUserName)
+	}
+
+	users, next, err = ListUsers(UserFilter{}, utils.PageRequest{Sort: SortName, Limit: 1, Cursor: next})
+	if len(users) != 1 || users[0].Name != testUserName || next != "" || err != nil {
+		t.Fatalf(`ListUsers(SortName Cursor) = %v, %q, %v, want [%s], "", nil`, users, next, err, testUserName)
+	}
+}
+
+func TestListUsersInvalidLimit(t *testing.T) {
+	setupUserTest(t)
+
+	var invalidLimit *sErr.InvalidValueError[int]
+
+	_, _, err := ListUsers(UserFilter{}, utils.PageRequest{Limit: -1})
+	if !errors.As(err, &invalidLimit) {
+		t.Fatalf(`ListUsers(InvalidLimit) = %v, want InvalidValueError`, err)
+	}
+}
+
 func setupUserTest(t *testing.T) (string, User) {
 	id, _ := CreateUser(testUserName)
 
 	tuInstance := testUser
 	tuInstance.UID = id
+	tuInstance.Created = users[id].Created
 
 	t.Cleanup(cleanAfterTest)
 
@@ -259,5 +374,11 @@ func confirmUserNotExist(t *testing.T, id string) {
 }
 
 func cleanAfterTest() {
+	lock.Lock()
+	defer lock.Unlock()
+
 	users = make(map[string]User)
+	tokens = make(map[string]string)
+	userIPs = make(map[string]string)
+	maxUsersPerIP = 0
 }
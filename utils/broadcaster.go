@@ -0,0 +1,67 @@
+package utils
+
+import "sync"
+
+const subscriberBuffer = 16
+
+type Broadcaster[T any] struct {
+	lock        sync.Mutex
+	subscribers map[chan T]bool
+}
+
+func NewBroadcaster[T any]() *Broadcaster[T] {
+	return &Broadcaster[T]{
+		subscribers: make(map[chan T]bool),
+	}
+}
+
+func (b *Broadcaster[T]) Subscribe() chan T {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	subscriber := make(chan T, subscriberBuffer)
+	b.subscribers[subscriber] = true
+
+	return subscriber
+}
+
+func (b *Broadcaster[T]) Unsubscribe(subscriber chan T) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	_, found := b.subscribers[subscriber]
+	if found {
+		delete(b.subscribers, subscriber)
+		close(subscriber)
+	}
+}
+
+func (b *Broadcaster[T]) Publish(message T) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for subscriber := range b.subscribers {
+		select {
+		case subscriber <- message:
+		default:
+			// Slow subscribers miss messages rather than block publishers
+		}
+	}
+}
+
+func (b *Broadcaster[T]) Close() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for subscriber := range b.subscribers {
+		delete(b.subscribers, subscriber)
+		close(subscriber)
+	}
+}
+
+func (b *Broadcaster[T]) Count() int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return len(b.subscribers)
+}
@@ -1,30 +1,74 @@
 package utils
 
 import (
-	"log"
+	"log/slog"
+	"sync"
 	"time"
 )
 
 const heartbeatPeriod = 3 * time.Second
 const heartbeatThreshold = 12 * time.Second
 
-func MonitorHeartbeats(heartbeats *map[string]time.Time, Delete func(uid string) error) {
+var monitorsStopped = make(chan struct{})
+var stopMonitors sync.Once
+
+func StopHeartbeatMonitors() {
+	stopMonitors.Do(func() {
+		close(monitorsStopped)
+	})
+}
+
+func HeartbeatMonitorsStopped() bool {
+	select {
+	case <-monitorsStopped:
+		return true
+	default:
+		return false
+	}
+}
+
+// WaitForNextCheck sleeps for period and reports whether monitors should keep running.
+func WaitForNextCheck(period time.Duration) bool {
+	select {
+	case <-time.After(period):
+		return true
+	case <-monitorsStopped:
+		return false
+	}
+}
+
+func MonitorHeartbeats(heartbeats *map[string]time.Time, lock sync.Locker, Delete func(uid string) error) {
 	for {
 		if heartbeats == nil {
 			return
 		}
 
-		now := time.Now()
+		lock.Lock()
+		expired := expiredHeartbeats(*heartbeats, time.Now())
+		lock.Unlock()
 
-		for uid, lastBeat := range *heartbeats {
-			if (now.Sub(lastBeat) * time.Second) < heartbeatThreshold {
-				err := Delete(uid)
-				if err != nil {
-					log.Printf("[Error] Failed to delete after heartbeat failure: %v", err)
-				}
+		for _, uid := range expired {
+			err := Delete(uid)
+			if err != nil {
+				slog.Error("Failed to delete after heartbeat failure", "id", uid, "error", err)
 			}
 		}
 
-		time.Sleep(heartbeatPeriod)
+		if !WaitForNextCheck(heartbeatPeriod) {
+			return
+		}
+	}
+}
+
+// expiredHeartbeats returns the IDs whose last beat is older than the
+// threshold at now.
+func expiredHeartbeats(heartbeats map[string]time.Time, now time.Time) []string {
+	expired := make([]string, 0)
+	for uid, lastBeat := range heartbeats {
+		if now.Sub(lastBeat) > heartbeatThreshold {
+			expired = append(expired, uid)
+		}
 	}
+
+	return expired
 }
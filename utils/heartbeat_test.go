@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExpiredHeartbeats(t *testing.T) {
+	now := time.Now()
+	heartbeats := map[string]time.Time{
+		"fresh":    now.Add(-time.Second),
+		"boundary": now.Add(-heartbeatThreshold),
+		"stale":    now.Add(-heartbeatThreshold - time.Second),
+	}
+
+	expired := expiredHeartbeats(heartbeats, now)
+	if len(expired) != 1 || expired[0] != "stale" {
+		t.Fatalf(`expiredHeartbeats(Mixed) = %v, want [stale]`, expired)
+	}
+}
+
+func TestExpiredHeartbeatsNone(t *testing.T) {
+	now := time.Now()
+	heartbeats := map[string]time.Time{"fresh": now}
+
+	expired := expiredHeartbeats(heartbeats, now)
+	if len(expired) != 0 {
+		t.Fatalf(`expiredHeartbeats(Fresh) = %v, want []`, expired)
+	}
+}
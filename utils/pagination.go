@@ -0,0 +1,134 @@
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"golang.org/x/exp/slices"
+
+	sErr "Engee-Server/stockErrors"
+)
+
+const DefaultPageSize = 50
+const MaxPageSize = 200
+
+type PageRequest struct {
+	Sort       string
+	Descending bool
+	Cursor     string
+	Limit      int
+}
+
+// SortKey orders items by Value, falling back to ID so ties are stable.
+type SortKey struct {
+	Value string `json:"value"`
+	ID    string `json:"id"`
+}
+
+type pageCursor struct {
+	Sort       string  `json:"sort"`
+	Descending bool    `json:"desc"`
+	After      SortKey `json:"after"`
+}
+
+func NumericSortValue(n int64) string {
+	return fmt.Sprintf("%020d", n)
+}
+
+func Paginate[T any](items []T, request PageRequest, key func(T) SortKey) ([]T, string, error) {
+	limit := request.Limit
+	if limit == 0 {
+		limit = DefaultPageSize
+	}
+
+	if limit < 0 || limit > MaxPageSize {
+		return nil, "", &sErr.InvalidValueError[int]{
+			Field: "Limit",
+			Value: request.Limit,
+		}
+	}
+
+	type entry struct {
+		item T
+		key  SortKey
+	}
+
+	entries := make([]entry, 0, len(items))
+	for _, item := range items {
+		entries = append(entries, entry{item: item, key: key(item)})
+	}
+
+	compare := func(a SortKey, b SortKey) int {
+		order := strings.Compare(a.Value, b.Value)
+		if order == 0 {
+			order = strings.Compare(a.ID, b.ID)
+		}
+
+		if request.Descending {
+			return -order
+		}
+
+		return order
+	}
+
+	slices.SortFunc(entries, func(a entry, b entry) int {
+		return compare(a.key, b.key)
+	})
+
+	start := 0
+	if request.Cursor != "" {
+		after, err := decodeCursor(request)
+		if err != nil {
+			return nil, "", err
+		}
+
+		for start < len(entries) && compare(entries[start].key, after) <= 0 {
+			start++
+		}
+	}
+
+	end := min(start+limit, len(entries))
+
+	page := make([]T, 0, end-start)
+	for _, e := range entries[start:end] {
+		page = append(page, e.item)
+	}
+
+	next := ""
+	if end < len(entries) && end > start {
+		next = encodeCursor(pageCursor{
+			Sort:       request.Sort,
+			Descending: request.Descending,
+			After:      entries[end-1].key,
+		})
+	}
+
+	return page, next, nil
+}
+
+func encodeCursor(cursor pageCursor) string {
+	cursorJSON, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(cursorJSON)
+}
+
+func decodeCursor(request PageRequest) (SortKey, error) {
+	invalid := &sErr.InvalidValueError[string]{
+		Field: "Cursor",
+		Value: request.Cursor,
+	}
+
+	cursorJSON, err := base64.RawURLEncoding.DecodeString(request.Cursor)
+	if err != nil {
+		return SortKey{}, invalid
+	}
+
+	var cursor pageCursor
+	err = json.Unmarshal(cursorJSON, &cursor)
+	if err != nil || cursor.Sort != request.Sort || cursor.Descending != request.Descending {
+		return SortKey{}, invalid
+	}
+
+	return cursor.After, nil
+}
@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	sErr "Engee-Server/stockErrors"
+)
+
+type SemVer struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+func ParseSemVer(version string) (SemVer, error) {
+	if version == "" {
+		return SemVer{}, &sErr.EmptyValueError{
+			Field: "Version",
+		}
+	}
+
+	parts := strings.Split(strings.TrimPrefix(version, "v"), ".")
+	if len(parts) > 3 {
+		return SemVer{}, &sErr.InvalidValueError[string]{
+			Field: "Version",
+			Value: version,
+		}
+	}
+
+	numbers := make([]int, 3)
+	for i, part := range parts {
+		number, err := strconv.Atoi(part)
+		if err != nil || number < 0 {
+			return SemVer{}, &sErr.InvalidValueError[string]{
+				Field: "Version",
+				Value: version,
+			}
+		}
+
+		numbers[i] = number
+	}
+
+	return SemVer{
+		Major: numbers[0],
+		Minor: numbers[1],
+		Patch: numbers[2],
+	}, nil
+}
+
+func (v SemVer) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+func (v SemVer) Compare(other SemVer) int {
+	if v.Major != other.Major {
+		return compareInts(v.Major, other.Major)
+	}
+
+	if v.Minor != other.Minor {
+		return compareInts(v.Minor, other.Minor)
+	}
+
+	return compareInts(v.Patch, other.Patch)
+}
+
+func compareInts(a int, b int) int {
+	if a < b {
+		return -1
+	}
+
+	if a > b {
+		return 1
+	}
+
+	return 0
+}
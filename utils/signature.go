@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"time"
+
+	sErr "Engee-Server/stockErrors"
+)
+
+const SignatureHeader = "X-Engee-Signature"
+const TimestampHeader = "X-Engee-Timestamp"
+
+const signatureTolerance = 5 * time.Minute
+
+func ComputeSignature(secret string, method string, uri string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(method + "\n" + uri + "\n" + timestamp + "\n"))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func SignRequest(request *http.Request, secret string, body []byte) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	signature := ComputeSignature(secret, request.Method, request.URL.RequestURI(), timestamp, body)
+
+	request.Header.Set(TimestampHeader, timestamp)
+	request.Header.Set(SignatureHeader, signature)
+}
+
+func VerifySignature(secret string, method string, uri string, timestamp string, signature string, body []byte) error {
+	if secret == "" {
+		return &sErr.AuthorizationError{
+			Space:  uri,
+			Reason: "no secret configured",
+		}
+	}
+
+	if timestamp == "" || signature == "" {
+		return &sErr.AuthorizationError{
+			Space:  uri,
+			Reason: "request is not signed",
+		}
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return &sErr.AuthorizationError{
+			Space:  uri,
+			Reason: "timestamp is invalid",
+		}
+	}
+
+	skew := time.Since(time.Unix(seconds, 0))
+	if skew > signatureTolerance || skew < -signatureTolerance {
+		return &sErr.AuthorizationError{
+			Space:  uri,
+			Reason: "timestamp is outside the accepted window",
+		}
+	}
+
+	expected := ComputeSignature(secret, method, uri, timestamp, body)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return &sErr.AuthorizationError{
+			Space:  uri,
+			Reason: "signature does not match",
+		}
+	}
+
+	return nil
+}
+
+func VerifyRequest(request *http.Request, secret string, body []byte) error {
+	return VerifySignature(
+		secret,
+		request.Method,
+		request.URL.RequestURI(),
+		request.Header.Get(TimestampHeader),
+		request.Header.Get(SignatureHeader),
+		body,
+	)
+}